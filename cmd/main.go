@@ -118,6 +118,9 @@ var (
 				&relaymodel.AccountApiKey{},
 				&relaymodel.RelayHourlyUsage{},
 				&relaymodel.RelayUsage{},
+				&relaymodel.File{},
+				&relaymodel.Batch{},
+				&relaymodel.FileChunk{},
 
 				// System
 				&systemmodel.Role{},
@@ -0,0 +1,335 @@
+package v1
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/samber/lo"
+
+	"github.com/modelgate/modelgate/internal/relay/model"
+	"github.com/modelgate/modelgate/pkg/common"
+)
+
+// maxUploadFileSize 上传文件大小上限
+const maxUploadFileSize = 200 << 20
+
+// openAIFile OpenAI 文件对象
+type openAIFile struct {
+	Id        string `json:"id"`
+	Object    string `json:"object"`
+	Bytes     int64  `json:"bytes"`
+	CreatedAt int64  `json:"created_at"`
+	Filename  string `json:"filename"`
+	Purpose   string `json:"purpose"`
+	Status    string `json:"status"`
+}
+
+// openAIBatch OpenAI 批量任务对象
+type openAIBatch struct {
+	Id               string          `json:"id"`
+	Object           string          `json:"object"`
+	Endpoint         string          `json:"endpoint"`
+	Errors           any             `json:"errors"`
+	InputFileId      string          `json:"input_file_id"`
+	CompletionWindow string          `json:"completion_window"`
+	Status           string          `json:"status"`
+	OutputFileId     *string         `json:"output_file_id"`
+	ErrorFileId      *string         `json:"error_file_id"`
+	CreatedAt        int64           `json:"created_at"`
+	InProgressAt     *int64          `json:"in_progress_at"`
+	ExpiresAt        int64           `json:"expires_at"`
+	FinalizingAt     *int64          `json:"finalizing_at"`
+	CompletedAt      *int64          `json:"completed_at"`
+	FailedAt         *int64          `json:"failed_at"`
+	ExpiredAt        *int64          `json:"expired_at"`
+	CancellingAt     *int64          `json:"cancelling_at"`
+	CancelledAt      *int64          `json:"cancelled_at"`
+	RequestCounts    gin.H           `json:"request_counts"`
+	Metadata         json.RawMessage `json:"metadata"`
+}
+
+// UploadFile 上传文件，目前只支持批量任务输入文件
+func (s *RelayService) UploadFile(c *gin.Context) {
+	// 解析表单前限制请求体大小，预留表单字段的空间
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxUploadFileSize+1<<20)
+	header, err := c.FormFile("file")
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "file is too large"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	purpose := c.PostForm("purpose")
+	if purpose != string(model.FilePurposeBatch) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unsupported purpose: " + purpose})
+		return
+	}
+	if header.Size > maxUploadFileSize {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "file is too large"})
+		return
+	}
+	f, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	defer f.Close()
+	info, err := s.relayService.CreateFile(c, &model.CreateFileRequest{
+		AccountId: common.GetAccountId(c),
+		Filename:  header.Filename,
+		Purpose:   model.FilePurpose(purpose),
+		Content:   f,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, toOpenAIFile(info))
+}
+
+// ListFiles 文件列表
+func (s *RelayService) ListFiles(c *gin.Context) {
+	after, limit, err := parseListParams(c, model.ParseFileId, 10000)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	list, err := s.relayService.GetFileList(c, &model.GetFileListRequest{
+		AccountId: common.GetAccountId(c),
+		Purpose:   model.FilePurpose(c.Query("purpose")),
+		After:     after,
+		Limit:     limit + 1,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, listPage(lo.Map(list, func(item *model.File, _ int) *openAIFile { return toOpenAIFile(item) }), limit,
+		func(item *openAIFile) string { return item.Id }))
+}
+
+// GetFile 文件详情
+func (s *RelayService) GetFile(c *gin.Context) {
+	id, err := model.ParseFileId(c.Param("file_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	info, err := s.relayService.GetFile(c, common.GetAccountId(c), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, toOpenAIFile(info))
+}
+
+// GetFileContent 下载文件内容
+func (s *RelayService) GetFileContent(c *gin.Context) {
+	id, err := model.ParseFileId(c.Param("file_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	info, content, err := s.relayService.OpenFileContent(c, common.GetAccountId(c), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	defer content.Close()
+	c.DataFromReader(http.StatusOK, info.Bytes, "application/octet-stream", content, map[string]string{
+		"Content-Disposition": fmt.Sprintf("attachment; filename=%q", info.Filename),
+	})
+}
+
+// DeleteFile 删除文件
+func (s *RelayService) DeleteFile(c *gin.Context) {
+	id, err := model.ParseFileId(c.Param("file_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err = s.relayService.DeleteFile(c, common.GetAccountId(c), id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"id": c.Param("file_id"), "object": "file", "deleted": true})
+}
+
+// CreateBatch 创建批量任务
+func (s *RelayService) CreateBatch(c *gin.Context) {
+	var req struct {
+		InputFileId      string          `json:"input_file_id"`
+		Endpoint         string          `json:"endpoint"`
+		CompletionWindow string          `json:"completion_window"`
+		Metadata         json.RawMessage `json:"metadata"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	info, err := s.relayService.CreateBatch(c, &model.CreateBatchRequest{
+		AccountId:        common.GetAccountId(c),
+		AccountApiKeyId:  common.GetApiKeyId(c),
+		InputFileId:      req.InputFileId,
+		Endpoint:         req.Endpoint,
+		CompletionWindow: req.CompletionWindow,
+		Metadata:         req.Metadata,
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, toOpenAIBatch(info))
+}
+
+// ListBatches 批量任务列表
+func (s *RelayService) ListBatches(c *gin.Context) {
+	after, limit, err := parseListParams(c, model.ParseBatchId, 100)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	list, err := s.relayService.GetBatchList(c, &model.GetBatchListRequest{
+		AccountId: common.GetAccountId(c),
+		After:     after,
+		Limit:     limit + 1,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, listPage(lo.Map(list, func(item *model.Batch, _ int) *openAIBatch { return toOpenAIBatch(item) }), limit,
+		func(item *openAIBatch) string { return item.Id }))
+}
+
+// GetBatch 批量任务详情
+func (s *RelayService) GetBatch(c *gin.Context) {
+	id, err := model.ParseBatchId(c.Param("batch_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	info, err := s.relayService.GetBatch(c, common.GetAccountId(c), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, toOpenAIBatch(info))
+}
+
+// CancelBatch 取消批量任务
+func (s *RelayService) CancelBatch(c *gin.Context) {
+	id, err := model.ParseBatchId(c.Param("batch_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	info, err := s.relayService.CancelBatch(c, common.GetAccountId(c), id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, toOpenAIBatch(info))
+}
+
+// parseListParams 解析 after、limit 分页参数，limit 默认 20
+func parseListParams(c *gin.Context, parseId func(string) (int64, error), maxLimit int64) (after int64, limit int64, err error) {
+	if v := c.Query("after"); v != "" {
+		if after, err = parseId(v); err != nil {
+			return
+		}
+	}
+	limit, err = strconv.ParseInt(c.DefaultQuery("limit", "20"), 10, 64)
+	if err != nil || limit <= 0 {
+		return 0, 0, fmt.Errorf("invalid limit: %s", c.Query("limit"))
+	}
+	limit = min(limit, maxLimit)
+	return
+}
+
+// listPage 多查询一条判断是否还有下一页
+func listPage[T any](list []T, limit int64, idOf func(T) string) gin.H {
+	hasMore := int64(len(list)) > limit
+	if hasMore {
+		list = list[:limit]
+	}
+	data := gin.H{
+		"object":   "list",
+		"data":     list,
+		"first_id": nil,
+		"last_id":  nil,
+		"has_more": hasMore,
+	}
+	if len(list) > 0 {
+		data["first_id"] = idOf(list[0])
+		data["last_id"] = idOf(list[len(list)-1])
+	}
+	return data
+}
+
+func toOpenAIFile(info *model.File) *openAIFile {
+	return &openAIFile{
+		Id:        info.ObjectId(),
+		Object:    "file",
+		Bytes:     info.Bytes,
+		CreatedAt: info.CreatedAt.Unix(),
+		Filename:  info.Filename,
+		Purpose:   string(info.Purpose),
+		Status:    "processed",
+	}
+}
+
+func toOpenAIBatch(info *model.Batch) *openAIBatch {
+	unix := func(t *time.Time) *int64 {
+		if t == nil {
+			return nil
+		}
+		return lo.ToPtr(t.Unix())
+	}
+	fileId := func(id int64) *string {
+		if id == 0 {
+			return nil
+		}
+		return lo.ToPtr(model.FileIdPrefix + strconv.FormatInt(id, 10))
+	}
+	batch := &openAIBatch{
+		Id:               info.ObjectId(),
+		Object:           "batch",
+		Endpoint:         info.Endpoint,
+		InputFileId:      *fileId(info.InputFileId),
+		CompletionWindow: info.CompletionWindow,
+		Status:           string(info.Status),
+		OutputFileId:     fileId(info.OutputFileId),
+		ErrorFileId:      fileId(info.ErrorFileId),
+		CreatedAt:        info.CreatedAt.Unix(),
+		InProgressAt:     unix(info.InProgressAt),
+		ExpiresAt:        info.ExpiresAt.Unix(),
+		FinalizingAt:     unix(info.FinalizingAt),
+		CompletedAt:      unix(info.CompletedAt),
+		FailedAt:         unix(info.FailedAt),
+		ExpiredAt:        unix(info.ExpiredAt),
+		CancellingAt:     unix(info.CancellingAt),
+		CancelledAt:      unix(info.CancelledAt),
+		RequestCounts: gin.H{
+			"total":     info.TotalCount,
+			"completed": info.CompletedCount,
+			"failed":    info.FailedCount,
+		},
+		Metadata: json.RawMessage(lo.CoalesceOrEmpty(info.Metadata, "{}")),
+	}
+	if info.ErrorMessage != "" {
+		batch.Errors = gin.H{
+			"object": "list",
+			"data":   []gin.H{{"code": "invalid_request", "message": info.ErrorMessage, "line": nil}},
+		}
+	}
+	return batch
+}
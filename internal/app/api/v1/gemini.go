@@ -0,0 +1,67 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GenerateContent Gemini generateContent、streamGenerateContent 接口，经协议转换路由到任意供应商
+func (s *RelayService) GenerateContent(c *gin.Context) {
+	modelCode, method, _ := strings.Cut(c.Param("model_method"), ":")
+	var stream bool
+	switch method {
+	case "generateContent":
+	case "streamGenerateContent":
+		stream = true
+	default:
+		writeGeminiError(c, http.StatusNotFound, fmt.Errorf("unsupported method: %s", method))
+		return
+	}
+	err := s.generateContent(c, modelCode, stream)
+	// 流式响应已开始写入时无法再返回错误
+	if err != nil && !c.Writer.Written() {
+		writeGeminiError(c, relayErrorStatus(err), err)
+	}
+}
+
+func (s *RelayService) generateContent(c *gin.Context, modelCode string, stream bool) (err error) {
+	data, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return
+	}
+	defer c.Request.Body.Close()
+	reqBody := make(map[string]any)
+	if err = json.Unmarshal(data, &reqBody); err != nil {
+		return
+	}
+	// model、stream 在请求路径中，写入请求体供协议转换使用
+	reqBody["model"] = modelCode
+	if stream {
+		reqBody["stream"] = true
+	}
+	inputData, err := json.Marshal(reqBody)
+	if err != nil {
+		return
+	}
+	return s.execute(c, &relayInput{
+		ProviderCode: c.Query("provider"),
+		ModelCode:    modelCode,
+		Stream:       stream,
+		Body:         inputData,
+		UrlPath:      c.Request.URL.Path,
+	})
+}
+
+// writeGeminiError Gemini 错误响应格式
+func writeGeminiError(c *gin.Context, code int, err error) {
+	c.JSON(code, gin.H{"error": gin.H{
+		"code":    code,
+		"message": err.Error(),
+		"status":  strings.ToUpper(strings.ReplaceAll(http.StatusText(code), " ", "_")),
+	}})
+}
@@ -0,0 +1,138 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/samber/lo"
+
+	"github.com/modelgate/modelgate/internal/relay/model"
+	"github.com/modelgate/modelgate/pkg/common"
+)
+
+// openAIModel OpenAI 模型信息，context_window、max_output_tokens 为扩展字段
+type openAIModel struct {
+	Id              string `json:"id"`
+	Object          string `json:"object"`
+	Created         int64  `json:"created"`
+	OwnedBy         string `json:"owned_by"`
+	Root            string `json:"root"`
+	ContextWindow   int64  `json:"context_window,omitempty"`
+	MaxOutputTokens int64  `json:"max_output_tokens,omitempty"`
+}
+
+// anthropicModel Anthropic 模型信息
+type anthropicModel struct {
+	Type            string `json:"type"`
+	Id              string `json:"id"`
+	DisplayName     string `json:"display_name"`
+	CreatedAt       string `json:"created_at"`
+	ContextWindow   int64  `json:"context_window,omitempty"`
+	MaxOutputTokens int64  `json:"max_output_tokens,omitempty"`
+}
+
+// ListModels 模型列表，携带 anthropic-version 请求头时返回 Anthropic 格式
+func (s *RelayService) ListModels(c *gin.Context) {
+	list, err := s.availableModels(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if isAnthropicRequest(c) {
+		c.JSON(http.StatusOK, anthropicModelPage(c, list))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"object": "list",
+		"data":   lo.Map(list, func(item *model.AvailableModel, _ int) *openAIModel { return toOpenAIModel(item) }),
+	})
+}
+
+// GetModel 模型详情
+func (s *RelayService) GetModel(c *gin.Context) {
+	list, err := s.availableModels(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	info, ok := lo.Find(list, func(item *model.AvailableModel) bool { return item.Code == c.Param("model") })
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "model not found: " + c.Param("model")})
+		return
+	}
+	if isAnthropicRequest(c) {
+		c.JSON(http.StatusOK, toAnthropicModel(info))
+		return
+	}
+	c.JSON(http.StatusOK, toOpenAIModel(info))
+}
+
+func (s *RelayService) availableModels(c *gin.Context) ([]*model.AvailableModel, error) {
+	scope := &model.ApiKeyScope{Models: common.GetApiKeyModels(c)}
+	return s.relayService.GetAvailableModelList(c, scope)
+}
+
+func isAnthropicRequest(c *gin.Context) bool {
+	return c.GetHeader("anthropic-version") != ""
+}
+
+func toOpenAIModel(info *model.AvailableModel) *openAIModel {
+	return &openAIModel{
+		Id:              info.Code,
+		Object:          "model",
+		Created:         info.CreatedAt.Unix(),
+		OwnedBy:         info.ProviderCode,
+		Root:            info.ActualCode,
+		ContextWindow:   info.ContextWindow,
+		MaxOutputTokens: info.MaxOutputTokens,
+	}
+}
+
+func toAnthropicModel(info *model.AvailableModel) *anthropicModel {
+	return &anthropicModel{
+		Type:            "model",
+		Id:              info.Code,
+		DisplayName:     lo.Ternary(info.Name != "", info.Name, info.Code),
+		CreatedAt:       info.CreatedAt.UTC().Format(time.RFC3339),
+		ContextWindow:   info.ContextWindow,
+		MaxOutputTokens: info.MaxOutputTokens,
+	}
+}
+
+// anthropicModelPage 按 before_id、after_id、limit 分页，limit 默认 20，最大 1000
+func anthropicModelPage(c *gin.Context, list []*model.AvailableModel) gin.H {
+	start, end := 0, len(list)
+	if afterId := c.Query("after_id"); afterId != "" {
+		_, index, _ := lo.FindIndexOf(list, func(item *model.AvailableModel) bool { return item.Code == afterId })
+		start = index + 1
+	}
+	if beforeId := c.Query("before_id"); beforeId != "" {
+		_, index, ok := lo.FindIndexOf(list, func(item *model.AvailableModel) bool { return item.Code == beforeId })
+		end = lo.Ternary(ok, index, 0)
+	}
+	limit, err := strconv.Atoi(c.Query("limit"))
+	if err != nil || limit <= 0 {
+		limit = 20
+	}
+	limit = min(limit, 1000)
+	// before_id 从后往前取
+	if c.Query("before_id") != "" {
+		start = max(start, end-limit)
+	} else {
+		end = min(end, start+limit)
+	}
+	page := lo.Map(list[start:max(start, end)], func(item *model.AvailableModel, _ int) *anthropicModel { return toAnthropicModel(item) })
+	data := gin.H{
+		"data":     page,
+		"has_more": lo.Ternary(c.Query("before_id") != "", start > 0, end < len(list)),
+		"first_id": nil,
+		"last_id":  nil,
+	}
+	if len(page) > 0 {
+		data["first_id"] = page[0].Id
+		data["last_id"] = page[len(page)-1].Id
+	}
+	return data
+}
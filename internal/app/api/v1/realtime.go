@@ -0,0 +1,116 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/modelgate/modelgate/internal/relay/model"
+	"github.com/modelgate/modelgate/internal/runtime"
+	"github.com/modelgate/modelgate/internal/runtime/core"
+	"github.com/modelgate/modelgate/internal/runtime/hooks"
+	"github.com/modelgate/modelgate/pkg/common"
+	"github.com/modelgate/modelgate/pkg/utils"
+)
+
+// realtimeBetaProtocol 浏览器通过子协议声明 OpenAI-Beta: realtime=v1
+const realtimeBetaProtocol = "openai-beta.realtime-v1"
+
+var realtimeUpgrader = websocket.Upgrader{
+	// 使用 API Key 鉴权，不依赖 Cookie，允许跨域
+	CheckOrigin:  func(r *http.Request) bool { return true },
+	Subprotocols: []string{"realtime"},
+}
+
+// Realtime 代理 Realtime API 的 WebSocket 会话，连接前预扣，每次响应结束时结算
+func (s *RelayService) Realtime(c *gin.Context) {
+	modelCode := c.Query("model")
+	if modelCode == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "model is required"})
+		return
+	}
+	scope := &model.ApiKeyScope{Models: common.GetApiKeyModels(c)}
+	if !scope.AllowModel(modelCode) {
+		err := fmt.Errorf("%w: %s", model.ErrModelNotAllowed, modelCode)
+		c.JSON(relayErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	currentModel, err := s.relayService.ResolveModel(c, &model.ResolveModelRequest{ProviderCode: c.Query("provider"), ModelCode: modelCode})
+	if err != nil {
+		c.JSON(relayErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	// 会话开始时还没有用量，升级连接前先预扣，余额不足时拒绝
+	coreModel := currentModel.ToCoreModel()
+	accountId := common.GetAccountId(c)
+	reserve := hooks.RealtimeReserve(coreModel)
+	if reserve > 0 {
+		if _, err = s.relayService.DeductBalance(c, accountId, reserve, 0, model.LedgerTypeConsume, "realtime reserve"); err != nil {
+			c.JSON(http.StatusPaymentRequired, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	// 升级失败时 Upgrader 已返回错误响应
+	conn, err := realtimeUpgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		log.Warnf("realtime upgrade error: %v", err)
+		s.refundRealtimeReserve(c, accountId, reserve)
+		return
+	}
+	defer conn.Close()
+
+	header := c.Request.Header.Clone()
+	if header.Get("OpenAI-Beta") == "" && slices.Contains(websocket.Subprotocols(c.Request), realtimeBetaProtocol) {
+		header.Set("OpenAI-Beta", "realtime=v1")
+	}
+	rCtx := core.Get()
+	defer core.Put(rCtx)
+	rCtx.RequestUUID = utils.NewUUIDv7()
+	rCtx.AttemptNo = 1
+	rCtx.ProviderCode = c.Query("provider")
+	rCtx.ModelCode = modelCode
+	rCtx.CurrentModel = coreModel
+	rCtx.AccountApiKeyId = common.GetApiKeyId(c)
+	rCtx.AccountId = accountId
+	rCtx.PreCost = reserve
+	rCtx.UrlPath = c.Request.URL.Path
+	rCtx.Protocol = core.ProtocolOpenAI
+	rCtx.Endpoint = core.EndpointRealtime
+	rCtx.Header = header
+	rCtx.ClientConn = conn
+	if err = runtime.Run(c.Request.Context(), rCtx); err != nil {
+		writeRealtimeError(conn, err)
+	}
+	// 没有经过计费 hook 结算时（如流水线去掉了计费）退回预扣
+	if rCtx.Settle == nil {
+		s.refundRealtimeReserve(c, accountId, reserve)
+	}
+}
+
+// refundRealtimeReserve 退回会话开始前的预扣
+func (s *RelayService) refundRealtimeReserve(c *gin.Context, accountId, reserve int64) {
+	if reserve <= 0 {
+		return
+	}
+	if _, err := s.relayService.AddBalance(context.WithoutCancel(c), accountId, reserve, 0, model.LedgerTypeRefund, "realtime reserve"); err != nil {
+		log.Errorf("realtime refund reserve error: %v", err)
+	}
+}
+
+// writeRealtimeError 以 Realtime error 事件通知客户端，连接已关闭时忽略
+func writeRealtimeError(conn *websocket.Conn, err error) {
+	data, _ := json.Marshal(gin.H{
+		"type":  "error",
+		"error": gin.H{"type": "server_error", "message": err.Error()},
+	})
+	if wErr := conn.WriteMessage(websocket.TextMessage, data); wErr != nil {
+		return
+	}
+	_ = conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseInternalServerErr, ""))
+}
@@ -3,15 +3,17 @@ package v1
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
-	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/samber/do/v2"
 	"github.com/samber/lo"
 
 	"github.com/modelgate/modelgate/internal/relay"
+	"github.com/modelgate/modelgate/internal/relay/model"
 	"github.com/modelgate/modelgate/internal/runtime"
 	"github.com/modelgate/modelgate/internal/runtime/core"
 	"github.com/modelgate/modelgate/pkg/common"
@@ -30,66 +32,113 @@ func NewRelayService(i do.Injector) (*RelayService, error) {
 
 func (s *RelayService) Run(c *gin.Context) {
 	err := s.run(c, "", "")
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	// 二进制响应已开始写入时无法再返回错误
+	if err != nil && !c.Writer.Written() {
+		c.JSON(relayErrorStatus(err), gin.H{"error": err.Error()})
 	}
 }
 
 func (s *RelayService) RunWithProvider(c *gin.Context) {
 	err := s.run(c, c.Param("provider"), c.Param("path"))
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	// 二进制响应已开始写入时无法再返回错误
+	if err != nil && !c.Writer.Written() {
+		c.JSON(relayErrorStatus(err), gin.H{"error": err.Error()})
 	}
 }
 
+// relayErrorStatus 所有供应商 API Key 都没有容量时返回 429，客户端可以稍后重试；API Key 不允许使用该模型时返回 403
+func relayErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, model.ErrNoCapacity):
+		return http.StatusTooManyRequests
+	case errors.Is(err, model.ErrModelNotAllowed):
+		return http.StatusForbidden
+	}
+	return http.StatusInternalServerError
+}
+
 func (s *RelayService) run(c *gin.Context, relayProvider, relayPath string) (err error) {
 	data, err := io.ReadAll(c.Request.Body)
 	if err != nil {
 		return
 	}
 	defer c.Request.Body.Close()
-	providerCode, modelCode, stream, inputData, err := s.parseInputBody(data)
+	contentType := c.GetHeader("Content-Type")
+	var providerCode, modelCode string
+	var stream bool
+	var inputData []byte
+	if utils.IsMultipart(contentType) {
+		providerCode, modelCode, inputData, err = s.parseMultipartBody(contentType, data)
+	} else {
+		contentType = ""
+		providerCode, modelCode, stream, inputData, err = s.parseInputBody(data)
+	}
 	if err != nil {
 		return
 	}
-	providerCode = lo.Ternary(relayProvider != "", relayProvider, providerCode)
-	currentModel, err := s.relayService.ResolveModel(c, providerCode, modelCode)
-	if err != nil {
+	return s.execute(c, &relayInput{
+		ProviderCode: lo.Ternary(relayProvider != "", relayProvider, providerCode),
+		ModelCode:    modelCode,
+		Stream:       stream,
+		ContentType:  contentType,
+		Body:         inputData,
+		UrlPath:      lo.Ternary(relayPath != "", relayPath, c.Request.URL.Path),
+	})
+}
+
+// relayInput 解析后的入站请求
+type relayInput struct {
+	ProviderCode string
+	ModelCode    string
+	Stream       bool
+	ContentType  string // 为空表示 JSON
+	Body         []byte
+	UrlPath      string // 转发到上游的路径
+}
+
+// execute 解析模型并执行，非流式响应原样写回
+func (s *RelayService) execute(c *gin.Context, input *relayInput) (err error) {
+	scope := &model.ApiKeyScope{Models: common.GetApiKeyModels(c)}
+	if !scope.AllowModel(input.ModelCode) {
+		err = fmt.Errorf("%w: %s", model.ErrModelNotAllowed, input.ModelCode)
 		return
 	}
-	cModel := &core.Model{
-		ModelId:           currentModel.ModelId,
-		ModelCode:         currentModel.ModelCode,
-		ProviderId:        currentModel.ProviderId,
-		ProviderCode:      currentModel.ProviderCode,
-		BaseUrl:           currentModel.BaseUrl,
-		ApiKeyId:          currentModel.ApiKeyId,
-		ApiKeyEncrypted:   currentModel.ApiKeyEncrypted,
-		InputPrice:        currentModel.InputPrice,
-		InputCachePrice:   currentModel.InputCachePrice,
-		OutputPrice:       currentModel.OutputPrice,
-		TokenNum:          currentModel.TokenNum,
-		PointsPerCurrency: currentModel.PointsPerCurrency,
+	currentModel, err := s.relayService.ResolveModel(c, &model.ResolveModelRequest{ProviderCode: input.ProviderCode, ModelCode: input.ModelCode})
+	if err != nil {
+		return
 	}
 	rCtx := core.Get()
 	defer core.Put(rCtx)
 	rCtx.RequestUUID = utils.NewUUIDv7()
 	rCtx.AttemptNo = 1
-	rCtx.CurrentModel = cModel
+	rCtx.ProviderCode = input.ProviderCode
+	rCtx.ModelCode = input.ModelCode
+	rCtx.CurrentModel = currentModel.ToCoreModel()
 	rCtx.AccountApiKeyId = common.GetApiKeyId(c)
 	rCtx.AccountId = common.GetAccountId(c)
-	rCtx.UrlPath = lo.Ternary(relayPath != "", relayPath, c.Request.URL.Path)
-	rCtx.IsAnthropic = strings.HasPrefix(c.Request.URL.Path, "/v1/relay/anthropic/")
-	rCtx.InputBody = inputData
+	rCtx.UrlPath = input.UrlPath
+	rCtx.Protocol = core.PathProtocol(c.Request.URL.Path)
+	rCtx.Endpoint = core.PathEndpoint(c.Request.URL.Path)
+	rCtx.ContentType = input.ContentType
+	rCtx.InputBody = input.Body
 	rCtx.Header = c.Request.Header
-	if stream {
+	if input.Stream {
 		rCtx.IsStream = true
-		rCtx.StreamWriter = newGinSSEWriter(c)
+		rCtx.StreamWriter = newGinSSEWriter(c, rCtx.Protocol)
 	}
-	if err = runtime.Run(c, rCtx); err != nil {
+	if rCtx.Endpoint == core.EndpointSpeech {
+		rCtx.BinaryWriter = newGinBinaryWriter(c)
+	}
+	// 客户端断开时取消上游请求
+	if err = runtime.Run(c.Request.Context(), rCtx); err != nil {
+		return
+	}
+	if input.Stream || rCtx.BinaryWriter != nil {
 		return
 	}
-	if stream {
+	// 本地估算的结果没有上游响应
+	if rCtx.HTTPResponse == nil {
+		c.Data(http.StatusOK, "application/json", rCtx.RawResponse)
 		return
 	}
 	for k, v := range rCtx.HTTPResponse.Header {
@@ -100,6 +149,20 @@ func (s *RelayService) run(c *gin.Context, relayProvider, relayPath string) (err
 	return
 }
 
+// parseMultipartBody multipart 请求体原样透传，只读取 provider、model 字段
+func (s *RelayService) parseMultipartBody(contentType string, data []byte) (providerCode, modelCode string, inputData []byte, err error) {
+	values, err := utils.MultipartFormValues(contentType, data)
+	if err != nil {
+		return
+	}
+	providerCode, modelCode, inputData = values["provider"], values["model"], data
+	// provider 不是标准请求参数，去除，boundary 不变
+	if _, ok := values["provider"]; ok {
+		inputData, _, err = utils.MultipartRemoveFields(contentType, data, "provider")
+	}
+	return
+}
+
 func (s *RelayService) parseInputBody(data []byte) (providerCode, modelCode string, stream bool, inputData []byte, err error) {
 	reqBody := make(map[string]any)
 	if err = json.Unmarshal(data, &reqBody); err != nil {
@@ -117,35 +180,53 @@ func (s *RelayService) parseInputBody(data []byte) (providerCode, modelCode stri
 }
 
 type GinSSEWriter struct {
-	w       gin.ResponseWriter
-	flusher http.Flusher
+	w        gin.ResponseWriter
+	flusher  http.Flusher
+	protocol core.Protocol
 }
 
-func newGinSSEWriter(c *gin.Context) *GinSSEWriter {
+func newGinSSEWriter(c *gin.Context, protocol core.Protocol) *GinSSEWriter {
 	return &GinSSEWriter{
-		w:       c.Writer,
-		flusher: c.Writer.(http.Flusher),
+		w:        c.Writer,
+		flusher:  c.Writer.(http.Flusher),
+		protocol: protocol,
 	}
 }
 
+// Open 重试时每次尝试都会调用，响应头在第一次写入时才发送，失败时仍可返回 JSON 错误
 func (g *GinSSEWriter) Open() error {
+	return nil
+}
+
+func (g *GinSSEWriter) writeHeader() {
+	if g.w.Written() {
+		return
+	}
 	g.w.Header().Set("Content-Type", "text/event-stream")
 	g.w.Header().Set("Cache-Control", "no-cache")
 	g.w.Header().Set("Connection", "keep-alive")
 	g.w.Header().Set("X-Accel-Buffering", "no") // Nginx
 	g.w.WriteHeader(http.StatusOK)
-	return nil
 }
 
 // Write 写入
 func (g *GinSSEWriter) Write(chunk *core.StreamChunk) error {
+	g.writeHeader()
 	if chunk.Finish {
+		// 只有 Chat Completions 以 [DONE] 结束，Anthropic 和 Responses 以结束事件收尾
+		if g.protocol != core.ProtocolOpenAI {
+			return nil
+		}
 		_, err := g.w.Write([]byte("data: [DONE]\n\n"))
 		g.flusher.Flush()
 		return err
 	}
 
-	buf := bytes.NewBuffer([]byte("data: "))
+	buf := new(bytes.Buffer)
+	if chunk.Event != "" {
+		buf.WriteString("event: " + chunk.Event + "\n")
+	}
+	buf.WriteString("data: ")
 	buf.Write([]byte(chunk.Data))
 	buf.Write([]byte("\n\n"))
 
@@ -158,3 +239,33 @@ func (g *GinSSEWriter) Write(chunk *core.StreamChunk) error {
 func (g *GinSSEWriter) Close() error {
 	return nil
 }
+
+type GinBinaryWriter struct {
+	w       gin.ResponseWriter
+	flusher http.Flusher
+}
+
+func newGinBinaryWriter(c *gin.Context) *GinBinaryWriter {
+	return &GinBinaryWriter{
+		w:       c.Writer,
+		flusher: c.Writer.(http.Flusher),
+	}
+}
+
+// WriteHeader 透传上游响应头，分块写入时去掉 Content-Length
+func (g *GinBinaryWriter) WriteHeader(statusCode int, header http.Header) {
+	for k, v := range header {
+		if k == "Content-Length" {
+			continue
+		}
+		g.w.Header().Set(k, v[0])
+	}
+	g.w.WriteHeader(statusCode)
+}
+
+// Write 写入并立即刷新
+func (g *GinBinaryWriter) Write(p []byte) (n int, err error) {
+	n, err = g.w.Write(p)
+	g.flusher.Flush()
+	return
+}
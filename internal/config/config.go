@@ -35,6 +35,8 @@ type Config struct {
 	Secret      secretConfig    `envPrefix:"SECRET_"`
 	RateLimit   RateLimitConfig `envPrefix:"RATE_LIMIT_"`
 	Redis       redisConfig     `envPrefix:"REDIS_"`
+	Batch       batchConfig     `envPrefix:"BATCH_"`
+	Notify      notifyConfig    `envPrefix:"NOTIFY_"`
 }
 
 type databaseConfig struct {
@@ -53,6 +55,16 @@ type redisConfig struct {
 	DB       int    `env:"DB"`
 }
 
+type batchConfig struct {
+	Storage     string `env:"STORAGE"`     // 文件存储：local 本地目录，db 数据库；多实例部署时使用 db 或把 FileDir 挂载为共享目录
+	FileDir     string `env:"FILE_DIR"`    // 本地文件存储目录，相对路径基于 app path
+	Concurrency int    `env:"CONCURRENCY"` // 批量任务并发数
+}
+
+type notifyConfig struct {
+	WebhookUrl string `env:"WEBHOOK_URL"` // 事件通知地址，POST JSON，为空时只记录日志
+}
+
 type secretConfig struct {
 	Key string `env:"KEY"`
 }
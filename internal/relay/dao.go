@@ -142,3 +142,40 @@ type RelayHourlyUsageDAO interface {
 	FindOneByID(ctx context.Context, id int64) (m *model.RelayHourlyUsage, err error)
 	Delete(ctx context.Context, filter *model.RelayHourlyUsageFilter) (int64, error)
 }
+
+type FileDAO interface {
+	Create(ctx context.Context, m *model.File) error
+	Save(ctx context.Context, m *model.File) error
+	Update(ctx context.Context, filter *model.FileFilter, update map[string]any) (int64, error)
+	UpdateOne(ctx context.Context, m *model.File, update map[string]any) error
+	Count(ctx context.Context, f *model.FileFilter) (total int64, err error)
+	Find(ctx context.Context, f *model.FileFilter, opts ...db.Option) (ms []*model.File, err error)
+	FindOne(ctx context.Context, f *model.FileFilter, opts ...db.Option) (*model.File, error)
+	FindOneByID(ctx context.Context, id int64) (m *model.File, err error)
+	Delete(ctx context.Context, filter *model.FileFilter) (int64, error)
+}
+
+type BatchDAO interface {
+	Create(ctx context.Context, m *model.Batch) error
+	Save(ctx context.Context, m *model.Batch) error
+	Update(ctx context.Context, filter *model.BatchFilter, update map[string]any) (int64, error)
+	UpdateOne(ctx context.Context, m *model.Batch, update map[string]any) error
+	Count(ctx context.Context, f *model.BatchFilter) (total int64, err error)
+	Find(ctx context.Context, f *model.BatchFilter, opts ...db.Option) (ms []*model.Batch, err error)
+	FindOne(ctx context.Context, f *model.BatchFilter, opts ...db.Option) (*model.Batch, error)
+	FindOneByID(ctx context.Context, id int64) (m *model.Batch, err error)
+	Delete(ctx context.Context, filter *model.BatchFilter) (int64, error)
+}
+
+type FileChunkDAO interface {
+	Create(ctx context.Context, m *model.FileChunk) error
+	Save(ctx context.Context, m *model.FileChunk) error
+	Update(ctx context.Context, filter *model.FileChunkFilter, update map[string]any) (int64, error)
+	UpdateOne(ctx context.Context, m *model.FileChunk, update map[string]any) error
+	Count(ctx context.Context, f *model.FileChunkFilter) (total int64, err error)
+	Find(ctx context.Context, f *model.FileChunkFilter, opts ...db.Option) (ms []*model.FileChunk, err error)
+	FindOne(ctx context.Context, f *model.FileChunkFilter, opts ...db.Option) (*model.FileChunk, error)
+	FindOneByID(ctx context.Context, id int64) (m *model.FileChunk, err error)
+	Delete(ctx context.Context, filter *model.FileChunkFilter) (int64, error)
+	SumSize(ctx context.Context, path string) (int64, error)
+}
@@ -0,0 +1,21 @@
+package dao
+
+import (
+	"github.com/samber/do/v2"
+	"gorm.io/gorm"
+
+	"github.com/modelgate/modelgate/internal/relay"
+	"github.com/modelgate/modelgate/internal/relay/model"
+	"github.com/modelgate/modelgate/pkg/db"
+)
+
+type BatchDao struct {
+	*db.BaseDAO[model.Batch, model.BatchFilter]
+}
+
+func NewBatchDao(i do.Injector) (relay.BatchDAO, error) {
+	dbConn := do.MustInvoke[*gorm.DB](i)
+	return &BatchDao{
+		BaseDAO: db.NewBaseDAO[model.Batch, model.BatchFilter](dbConn),
+	}, nil
+}
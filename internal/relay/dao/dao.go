@@ -17,4 +17,7 @@ func Init(i do.Injector) {
 	do.Provide(i, NewLedgerDao)
 	do.Provide(i, NewRelayHourlyUsageDao)
 	do.Provide(i, NewRelayUsageDao)
+	do.Provide(i, NewFileDao)
+	do.Provide(i, NewBatchDao)
+	do.Provide(i, NewFileChunkDao)
 }
@@ -0,0 +1,21 @@
+package dao
+
+import (
+	"github.com/samber/do/v2"
+	"gorm.io/gorm"
+
+	"github.com/modelgate/modelgate/internal/relay"
+	"github.com/modelgate/modelgate/internal/relay/model"
+	"github.com/modelgate/modelgate/pkg/db"
+)
+
+type FileDao struct {
+	*db.BaseDAO[model.File, model.FileFilter]
+}
+
+func NewFileDao(i do.Injector) (relay.FileDAO, error) {
+	dbConn := do.MustInvoke[*gorm.DB](i)
+	return &FileDao{
+		BaseDAO: db.NewBaseDAO[model.File, model.FileFilter](dbConn),
+	}, nil
+}
@@ -0,0 +1,30 @@
+package dao
+
+import (
+	"context"
+
+	"github.com/samber/do/v2"
+	"gorm.io/gorm"
+
+	"github.com/modelgate/modelgate/internal/relay"
+	"github.com/modelgate/modelgate/internal/relay/model"
+	"github.com/modelgate/modelgate/pkg/db"
+)
+
+type FileChunkDao struct {
+	*db.BaseDAO[model.FileChunk, model.FileChunkFilter]
+}
+
+func NewFileChunkDao(i do.Injector) (relay.FileChunkDAO, error) {
+	dbConn := do.MustInvoke[*gorm.DB](i)
+	return &FileChunkDao{
+		BaseDAO: db.NewBaseDAO[model.FileChunk, model.FileChunkFilter](dbConn),
+	}, nil
+}
+
+// SumSize 文件所有分块的总大小
+func (d *FileChunkDao) SumSize(ctx context.Context, path string) (size int64, err error) {
+	err = d.GetDB().Model(&model.FileChunk{}).Where("path = ?", path).
+		Select("COALESCE(SUM(size), 0)").Scan(&size).Error
+	return
+}
@@ -1873,3 +1873,516 @@ func (mr *MockRelayHourlyUsageDAOMockRecorder) UpdateOne(ctx, m, update any) *go
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOne", reflect.TypeOf((*MockRelayHourlyUsageDAO)(nil).UpdateOne), ctx, m, update)
 }
+
+// MockFileDAO is a mock of FileDAO interface.
+type MockFileDAO struct {
+	ctrl     *gomock.Controller
+	recorder *MockFileDAOMockRecorder
+	isgomock struct{}
+}
+
+// MockFileDAOMockRecorder is the mock recorder for MockFileDAO.
+type MockFileDAOMockRecorder struct {
+	mock *MockFileDAO
+}
+
+// NewMockFileDAO creates a new mock instance.
+func NewMockFileDAO(ctrl *gomock.Controller) *MockFileDAO {
+	mock := &MockFileDAO{ctrl: ctrl}
+	mock.recorder = &MockFileDAOMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFileDAO) EXPECT() *MockFileDAOMockRecorder {
+	return m.recorder
+}
+
+// Count mocks base method.
+func (m *MockFileDAO) Count(ctx context.Context, f *model.FileFilter) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Count", ctx, f)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Count indicates an expected call of Count.
+func (mr *MockFileDAOMockRecorder) Count(ctx, f any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Count", reflect.TypeOf((*MockFileDAO)(nil).Count), ctx, f)
+}
+
+// Create mocks base method.
+func (m_2 *MockFileDAO) Create(ctx context.Context, m *model.File) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "Create", ctx, m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockFileDAOMockRecorder) Create(ctx, m any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockFileDAO)(nil).Create), ctx, m)
+}
+
+// Delete mocks base method.
+func (m *MockFileDAO) Delete(ctx context.Context, filter *model.FileFilter) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, filter)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockFileDAOMockRecorder) Delete(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockFileDAO)(nil).Delete), ctx, filter)
+}
+
+// Find mocks base method.
+func (m *MockFileDAO) Find(ctx context.Context, f *model.FileFilter, opts ...db.Option) ([]*model.File, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, f}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Find", varargs...)
+	ret0, _ := ret[0].([]*model.File)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find.
+func (mr *MockFileDAOMockRecorder) Find(ctx, f any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, f}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockFileDAO)(nil).Find), varargs...)
+}
+
+// FindOne mocks base method.
+func (m *MockFileDAO) FindOne(ctx context.Context, f *model.FileFilter, opts ...db.Option) (*model.File, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, f}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "FindOne", varargs...)
+	ret0, _ := ret[0].(*model.File)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindOne indicates an expected call of FindOne.
+func (mr *MockFileDAOMockRecorder) FindOne(ctx, f any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, f}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOne", reflect.TypeOf((*MockFileDAO)(nil).FindOne), varargs...)
+}
+
+// FindOneByID mocks base method.
+func (m *MockFileDAO) FindOneByID(ctx context.Context, id int64) (*model.File, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindOneByID", ctx, id)
+	ret0, _ := ret[0].(*model.File)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindOneByID indicates an expected call of FindOneByID.
+func (mr *MockFileDAOMockRecorder) FindOneByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOneByID", reflect.TypeOf((*MockFileDAO)(nil).FindOneByID), ctx, id)
+}
+
+// Save mocks base method.
+func (m_2 *MockFileDAO) Save(ctx context.Context, m *model.File) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "Save", ctx, m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockFileDAOMockRecorder) Save(ctx, m any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockFileDAO)(nil).Save), ctx, m)
+}
+
+// Update mocks base method.
+func (m *MockFileDAO) Update(ctx context.Context, filter *model.FileFilter, update map[string]any) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, filter, update)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockFileDAOMockRecorder) Update(ctx, filter, update any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockFileDAO)(nil).Update), ctx, filter, update)
+}
+
+// UpdateOne mocks base method.
+func (m_2 *MockFileDAO) UpdateOne(ctx context.Context, m *model.File, update map[string]any) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "UpdateOne", ctx, m, update)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateOne indicates an expected call of UpdateOne.
+func (mr *MockFileDAOMockRecorder) UpdateOne(ctx, m, update any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOne", reflect.TypeOf((*MockFileDAO)(nil).UpdateOne), ctx, m, update)
+}
+
+// MockBatchDAO is a mock of BatchDAO interface.
+type MockBatchDAO struct {
+	ctrl     *gomock.Controller
+	recorder *MockBatchDAOMockRecorder
+	isgomock struct{}
+}
+
+// MockBatchDAOMockRecorder is the mock recorder for MockBatchDAO.
+type MockBatchDAOMockRecorder struct {
+	mock *MockBatchDAO
+}
+
+// NewMockBatchDAO creates a new mock instance.
+func NewMockBatchDAO(ctrl *gomock.Controller) *MockBatchDAO {
+	mock := &MockBatchDAO{ctrl: ctrl}
+	mock.recorder = &MockBatchDAOMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBatchDAO) EXPECT() *MockBatchDAOMockRecorder {
+	return m.recorder
+}
+
+// Count mocks base method.
+func (m *MockBatchDAO) Count(ctx context.Context, f *model.BatchFilter) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Count", ctx, f)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Count indicates an expected call of Count.
+func (mr *MockBatchDAOMockRecorder) Count(ctx, f any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Count", reflect.TypeOf((*MockBatchDAO)(nil).Count), ctx, f)
+}
+
+// Create mocks base method.
+func (m_2 *MockBatchDAO) Create(ctx context.Context, m *model.Batch) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "Create", ctx, m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockBatchDAOMockRecorder) Create(ctx, m any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockBatchDAO)(nil).Create), ctx, m)
+}
+
+// Delete mocks base method.
+func (m *MockBatchDAO) Delete(ctx context.Context, filter *model.BatchFilter) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, filter)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockBatchDAOMockRecorder) Delete(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockBatchDAO)(nil).Delete), ctx, filter)
+}
+
+// Find mocks base method.
+func (m *MockBatchDAO) Find(ctx context.Context, f *model.BatchFilter, opts ...db.Option) ([]*model.Batch, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, f}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Find", varargs...)
+	ret0, _ := ret[0].([]*model.Batch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find.
+func (mr *MockBatchDAOMockRecorder) Find(ctx, f any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, f}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockBatchDAO)(nil).Find), varargs...)
+}
+
+// FindOne mocks base method.
+func (m *MockBatchDAO) FindOne(ctx context.Context, f *model.BatchFilter, opts ...db.Option) (*model.Batch, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, f}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "FindOne", varargs...)
+	ret0, _ := ret[0].(*model.Batch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindOne indicates an expected call of FindOne.
+func (mr *MockBatchDAOMockRecorder) FindOne(ctx, f any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, f}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOne", reflect.TypeOf((*MockBatchDAO)(nil).FindOne), varargs...)
+}
+
+// FindOneByID mocks base method.
+func (m *MockBatchDAO) FindOneByID(ctx context.Context, id int64) (*model.Batch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindOneByID", ctx, id)
+	ret0, _ := ret[0].(*model.Batch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindOneByID indicates an expected call of FindOneByID.
+func (mr *MockBatchDAOMockRecorder) FindOneByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOneByID", reflect.TypeOf((*MockBatchDAO)(nil).FindOneByID), ctx, id)
+}
+
+// Save mocks base method.
+func (m_2 *MockBatchDAO) Save(ctx context.Context, m *model.Batch) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "Save", ctx, m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockBatchDAOMockRecorder) Save(ctx, m any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockBatchDAO)(nil).Save), ctx, m)
+}
+
+// Update mocks base method.
+func (m *MockBatchDAO) Update(ctx context.Context, filter *model.BatchFilter, update map[string]any) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, filter, update)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockBatchDAOMockRecorder) Update(ctx, filter, update any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockBatchDAO)(nil).Update), ctx, filter, update)
+}
+
+// UpdateOne mocks base method.
+func (m_2 *MockBatchDAO) UpdateOne(ctx context.Context, m *model.Batch, update map[string]any) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "UpdateOne", ctx, m, update)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateOne indicates an expected call of UpdateOne.
+func (mr *MockBatchDAOMockRecorder) UpdateOne(ctx, m, update any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOne", reflect.TypeOf((*MockBatchDAO)(nil).UpdateOne), ctx, m, update)
+}
+
+// MockFileChunkDAO is a mock of FileChunkDAO interface.
+type MockFileChunkDAO struct {
+	ctrl     *gomock.Controller
+	recorder *MockFileChunkDAOMockRecorder
+	isgomock struct{}
+}
+
+// MockFileChunkDAOMockRecorder is the mock recorder for MockFileChunkDAO.
+type MockFileChunkDAOMockRecorder struct {
+	mock *MockFileChunkDAO
+}
+
+// NewMockFileChunkDAO creates a new mock instance.
+func NewMockFileChunkDAO(ctrl *gomock.Controller) *MockFileChunkDAO {
+	mock := &MockFileChunkDAO{ctrl: ctrl}
+	mock.recorder = &MockFileChunkDAOMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFileChunkDAO) EXPECT() *MockFileChunkDAOMockRecorder {
+	return m.recorder
+}
+
+// Count mocks base method.
+func (m *MockFileChunkDAO) Count(ctx context.Context, f *model.FileChunkFilter) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Count", ctx, f)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Count indicates an expected call of Count.
+func (mr *MockFileChunkDAOMockRecorder) Count(ctx, f any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Count", reflect.TypeOf((*MockFileChunkDAO)(nil).Count), ctx, f)
+}
+
+// Create mocks base method.
+func (m_2 *MockFileChunkDAO) Create(ctx context.Context, m *model.FileChunk) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "Create", ctx, m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockFileChunkDAOMockRecorder) Create(ctx, m any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockFileChunkDAO)(nil).Create), ctx, m)
+}
+
+// Delete mocks base method.
+func (m *MockFileChunkDAO) Delete(ctx context.Context, filter *model.FileChunkFilter) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, filter)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockFileChunkDAOMockRecorder) Delete(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockFileChunkDAO)(nil).Delete), ctx, filter)
+}
+
+// Find mocks base method.
+func (m *MockFileChunkDAO) Find(ctx context.Context, f *model.FileChunkFilter, opts ...db.Option) ([]*model.FileChunk, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, f}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Find", varargs...)
+	ret0, _ := ret[0].([]*model.FileChunk)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find.
+func (mr *MockFileChunkDAOMockRecorder) Find(ctx, f any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, f}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockFileChunkDAO)(nil).Find), varargs...)
+}
+
+// FindOne mocks base method.
+func (m *MockFileChunkDAO) FindOne(ctx context.Context, f *model.FileChunkFilter, opts ...db.Option) (*model.FileChunk, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, f}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "FindOne", varargs...)
+	ret0, _ := ret[0].(*model.FileChunk)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindOne indicates an expected call of FindOne.
+func (mr *MockFileChunkDAOMockRecorder) FindOne(ctx, f any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, f}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOne", reflect.TypeOf((*MockFileChunkDAO)(nil).FindOne), varargs...)
+}
+
+// FindOneByID mocks base method.
+func (m *MockFileChunkDAO) FindOneByID(ctx context.Context, id int64) (*model.FileChunk, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindOneByID", ctx, id)
+	ret0, _ := ret[0].(*model.FileChunk)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindOneByID indicates an expected call of FindOneByID.
+func (mr *MockFileChunkDAOMockRecorder) FindOneByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOneByID", reflect.TypeOf((*MockFileChunkDAO)(nil).FindOneByID), ctx, id)
+}
+
+// Save mocks base method.
+func (m_2 *MockFileChunkDAO) Save(ctx context.Context, m *model.FileChunk) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "Save", ctx, m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockFileChunkDAOMockRecorder) Save(ctx, m any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockFileChunkDAO)(nil).Save), ctx, m)
+}
+
+// SumSize mocks base method.
+func (m *MockFileChunkDAO) SumSize(ctx context.Context, path string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SumSize", ctx, path)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SumSize indicates an expected call of SumSize.
+func (mr *MockFileChunkDAOMockRecorder) SumSize(ctx, path any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SumSize", reflect.TypeOf((*MockFileChunkDAO)(nil).SumSize), ctx, path)
+}
+
+// Update mocks base method.
+func (m *MockFileChunkDAO) Update(ctx context.Context, filter *model.FileChunkFilter, update map[string]any) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, filter, update)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockFileChunkDAOMockRecorder) Update(ctx, filter, update any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockFileChunkDAO)(nil).Update), ctx, filter, update)
+}
+
+// UpdateOne mocks base method.
+func (m_2 *MockFileChunkDAO) UpdateOne(ctx context.Context, m *model.FileChunk, update map[string]any) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "UpdateOne", ctx, m, update)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateOne indicates an expected call of UpdateOne.
+func (mr *MockFileChunkDAOMockRecorder) UpdateOne(ctx, m, update any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOne", reflect.TypeOf((*MockFileChunkDAO)(nil).UpdateOne), ctx, m, update)
+}
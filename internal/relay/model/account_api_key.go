@@ -1,7 +1,10 @@
 package model
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"path"
 	"time"
 
 	"github.com/samber/lo"
@@ -12,6 +15,9 @@ import (
 	"github.com/modelgate/modelgate/pkg/types"
 )
 
+// ErrModelNotAllowed API Key 的权限不包含请求的模型
+var ErrModelNotAllowed = errors.New("model is not allowed for this api key")
+
 // AccountApiKey 账号API密钥
 type AccountApiKey struct {
 	db.Model
@@ -36,6 +42,38 @@ func (AccountApiKey) TableName() string {
 	return TableAccountApiKey
 }
 
+// ApiKeyScope API Key 权限，如 {"models": ["gpt-4o", "claude-*"]}
+type ApiKeyScope struct {
+	Models []string `json:"models,omitempty"` // 允许使用的模型代码，支持 * 通配，为空不限制
+}
+
+// AllowModel 是否允许使用该模型
+func (s *ApiKeyScope) AllowModel(code string) bool {
+	if s == nil || len(s.Models) == 0 {
+		return true
+	}
+	for _, pattern := range s.Models {
+		if ok, _ := path.Match(pattern, code); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// GetScope 解析权限，兼容以 JSON 字符串形式保存的权限，无法解析时不限制
+func (m *AccountApiKey) GetScope() *ApiKeyScope {
+	data := []byte(m.Scope)
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		data = []byte(text)
+	}
+	scope := &ApiKeyScope{}
+	if err := json.Unmarshal(data, scope); err != nil {
+		return &ApiKeyScope{}
+	}
+	return scope
+}
+
 type AccountApiKeyFilter struct {
 	ID        db.F[int64]
 	IDs       db.F[[]int64] `gorm:"column:id"`
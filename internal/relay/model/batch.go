@@ -0,0 +1,140 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/modelgate/modelgate/pkg/db"
+)
+
+// BatchStatus 批量任务状态
+type BatchStatus string
+
+const (
+	BatchStatusValidating BatchStatus = "validating"  // 校验输入文件
+	BatchStatusFailed     BatchStatus = "failed"      // 校验失败
+	BatchStatusInProgress BatchStatus = "in_progress" // 执行中
+	BatchStatusFinalizing BatchStatus = "finalizing"  // 生成结果文件
+	BatchStatusCompleted  BatchStatus = "completed"   // 已完成
+	BatchStatusExpired    BatchStatus = "expired"     // 超出完成时间窗口
+	BatchStatusCancelling BatchStatus = "cancelling"  // 取消中
+	BatchStatusCancelled  BatchStatus = "cancelled"   // 已取消
+)
+
+// BatchIdPrefix 对外批量任务 ID 前缀
+const BatchIdPrefix = "batch_"
+
+// BatchEndpoints 批量任务支持的接口
+var BatchEndpoints = []string{
+	"/v1/chat/completions",
+	"/v1/completions",
+	"/v1/embeddings",
+	"/v1/responses",
+}
+
+// Batch 批量任务
+type Batch struct {
+	db.Model
+
+	AccountId        int64       `gorm:"type:bigint unsigned;not null;default:0;index:idx_account"`                                                                                                 // 账户ID
+	AccountApiKeyId  int64       `gorm:"type:bigint unsigned;not null;default:0"`                                                                                                                   // 账户 API Key ID
+	Endpoint         string      `gorm:"type:varchar(100);not null;default:''"`                                                                                                                     // 请求接口
+	CompletionWindow string      `gorm:"type:varchar(20);not null;default:'24h'"`                                                                                                                   // 完成时间窗口
+	InputFileId      int64       `gorm:"type:bigint unsigned;not null;default:0"`                                                                                                                   // 输入文件ID
+	OutputFileId     int64       `gorm:"type:bigint unsigned;not null;default:0"`                                                                                                                   // 成功结果文件ID
+	ErrorFileId      int64       `gorm:"type:bigint unsigned;not null;default:0"`                                                                                                                   // 失败结果文件ID
+	Status           BatchStatus `gorm:"type:enum('validating','failed','in_progress','finalizing','completed','expired','cancelling','cancelled');not null;default:'validating';index:idx_status"` // 状态
+	TotalCount       int64       `gorm:"type:bigint unsigned;not null;default:0"`                                                                                                                   // 总请求数
+	CompletedCount   int64       `gorm:"type:bigint unsigned;not null;default:0"`                                                                                                                   // 成功请求数
+	FailedCount      int64       `gorm:"type:bigint unsigned;not null;default:0"`                                                                                                                   // 失败请求数
+	Metadata         string      `gorm:"type:json;default:null"`                                                                                                                                    // 自定义元数据
+	ErrorMessage     string      `gorm:"type:varchar(1000);not null;default:''"`                                                                                                                    // 失败原因
+	ExpiresAt        time.Time   `gorm:"type:datetime(3);not null"`                                                                                                                                 // 过期时间
+	InProgressAt     *time.Time  `gorm:"type:datetime(3);"`                                                                                                                                         // 开始执行时间
+	FinalizingAt     *time.Time  `gorm:"type:datetime(3);"`                                                                                                                                         // 开始生成结果时间
+	CompletedAt      *time.Time  `gorm:"type:datetime(3);"`                                                                                                                                         // 完成时间
+	FailedAt         *time.Time  `gorm:"type:datetime(3);"`                                                                                                                                         // 失败时间
+	ExpiredAt        *time.Time  `gorm:"type:datetime(3);"`                                                                                                                                         // 过期时间
+	CancellingAt     *time.Time  `gorm:"type:datetime(3);"`                                                                                                                                         // 开始取消时间
+	CancelledAt      *time.Time  `gorm:"type:datetime(3);"`                                                                                                                                         // 取消时间
+}
+
+func (Batch) TableName() string {
+	return TableBatch
+}
+
+// ObjectId 对外批量任务 ID
+func (m *Batch) ObjectId() string {
+	return BatchIdPrefix + strconv.FormatInt(m.ID, 10)
+}
+
+// IsFinished 是否已结束
+func (m *Batch) IsFinished() bool {
+	switch m.Status {
+	case BatchStatusFailed, BatchStatusCompleted, BatchStatusExpired, BatchStatusCancelled:
+		return true
+	}
+	return false
+}
+
+// ParseBatchId 解析对外批量任务 ID
+func ParseBatchId(id string) (int64, error) {
+	v, err := strconv.ParseInt(strings.TrimPrefix(id, BatchIdPrefix), 10, 64)
+	if err != nil || !strings.HasPrefix(id, BatchIdPrefix) {
+		return 0, fmt.Errorf("invalid batch id: %s", id)
+	}
+	return v, nil
+}
+
+// BatchFilter 过滤器
+type BatchFilter struct {
+	ID        db.F[int64]
+	IDs       db.F[[]int64] `gorm:"column:id"`
+	AccountId db.F[int64]
+	Status    db.F[[]BatchStatus]
+}
+
+type CreateBatchRequest struct {
+	AccountId        int64
+	AccountApiKeyId  int64
+	InputFileId      string
+	Endpoint         string
+	CompletionWindow string
+	Metadata         json.RawMessage
+}
+
+type GetBatchListRequest struct {
+	AccountId int64
+	After     int64 // 返回 ID 小于 After 的记录
+	Limit     int64
+}
+
+// BatchRequestLine 输入文件中的一行请求
+type BatchRequestLine struct {
+	CustomId string          `json:"custom_id"`
+	Method   string          `json:"method"`
+	Url      string          `json:"url"`
+	Body     json.RawMessage `json:"body"`
+}
+
+// BatchResultLine 结果文件中的一行
+type BatchResultLine struct {
+	Id       string               `json:"id"`
+	CustomId string               `json:"custom_id"`
+	Response *BatchResultResponse `json:"response"`
+	Error    *BatchResultError    `json:"error"`
+}
+
+type BatchResultResponse struct {
+	StatusCode int             `json:"status_code"`
+	RequestId  string          `json:"request_id"`
+	Body       json.RawMessage `json:"body"`
+}
+
+type BatchResultError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
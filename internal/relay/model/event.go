@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+// EventType 事件类型
+type EventType string
+
+const (
+	EventProviderApiKeyDisabled EventType = "provider_api_key.disabled" // 供应商 API Key 被自动禁用
+)
+
+// Event 需要通知运维人员的事件
+type Event struct {
+	Type    EventType      `json:"type"`
+	Message string         `json:"message"`
+	Data    map[string]any `json:"data,omitempty"`
+	Time    time.Time      `json:"time"`
+}
@@ -0,0 +1,93 @@
+package model
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/modelgate/modelgate/pkg/db"
+)
+
+// FilePurpose 文件用途
+type FilePurpose string
+
+const (
+	FilePurposeBatch       FilePurpose = "batch"        // 批量任务输入
+	FilePurposeBatchOutput FilePurpose = "batch_output" // 批量任务输出
+)
+
+// FileIdPrefix 对外文件 ID 前缀
+const FileIdPrefix = "file-"
+
+// File 上传文件，内容保存在文件存储中
+type File struct {
+	db.Model
+
+	AccountId int64       `gorm:"type:bigint unsigned;not null;default:0;index:idx_account_purpose"`                    // 账户ID
+	Filename  string      `gorm:"type:varchar(255);not null;default:''"`                                                // 文件名
+	Purpose   FilePurpose `gorm:"type:enum('batch','batch_output');not null;default:'batch';index:idx_account_purpose"` // 用途
+	Bytes     int64       `gorm:"type:bigint unsigned;not null;default:0"`                                              // 文件大小
+	Path      string      `gorm:"type:varchar(500);not null;default:''"`                                                // 相对存储目录的路径
+}
+
+func (File) TableName() string {
+	return TableFile
+}
+
+// ObjectId 对外文件 ID
+func (m *File) ObjectId() string {
+	return FileIdPrefix + strconv.FormatInt(m.ID, 10)
+}
+
+// ParseFileId 解析对外文件 ID
+func ParseFileId(id string) (int64, error) {
+	v, err := strconv.ParseInt(strings.TrimPrefix(id, FileIdPrefix), 10, 64)
+	if err != nil || !strings.HasPrefix(id, FileIdPrefix) {
+		return 0, fmt.Errorf("invalid file id: %s", id)
+	}
+	return v, nil
+}
+
+// FileFilter 过滤器
+type FileFilter struct {
+	ID        db.F[int64]
+	IDs       db.F[[]int64] `gorm:"column:id"`
+	AccountId db.F[int64]
+	Purpose   db.F[FilePurpose]
+}
+
+type CreateFileRequest struct {
+	AccountId int64
+	Filename  string
+	Purpose   FilePurpose
+	Content   io.Reader // 文件内容，边读边写入文件存储
+}
+
+type GetFileListRequest struct {
+	AccountId int64
+	Purpose   FilePurpose
+	After     int64 // 返回 ID 小于 After 的记录
+	Limit     int64
+}
+
+// FileChunk 共享存储的文件分块，按 Seq 顺序拼接为文件内容，多实例部署时使用
+type FileChunk struct {
+	db.Model
+
+	Path string `gorm:"type:varchar(255);not null;default:'';uniqueIndex:uk_path_seq"` // 文件存储路径
+	Seq  int64  `gorm:"type:int unsigned;not null;default:0;uniqueIndex:uk_path_seq"`  // 分块序号
+	Size int64  `gorm:"type:int unsigned;not null;default:0"`                          // 分块大小
+	Data []byte `gorm:"type:mediumblob"`                                               // 分块内容
+}
+
+func (FileChunk) TableName() string {
+	return TableFileChunk
+}
+
+// FileChunkFilter 过滤器
+type FileChunkFilter struct {
+	ID   db.F[int64]
+	Path db.F[string]
+	Seq  db.F[int64]
+}
@@ -1,8 +1,11 @@
 package model
 
 import (
+	"time"
+
 	"google.golang.org/protobuf/types/known/timestamppb"
 
+	"github.com/modelgate/modelgate/internal/runtime/core"
 	"github.com/modelgate/modelgate/pkg/db"
 	relaypb "github.com/modelgate/modelgate/pkg/proto/model/relay"
 	"github.com/modelgate/modelgate/pkg/types"
@@ -29,6 +32,13 @@ type Model struct {
 	Priority     int         `gorm:"type:int;not null;default:1"`                                                      // 优先级，越小越优先
 	Weight       int         `gorm:"type:int;not null;default:100"`                                                    // 权重
 	Status       ModelStatus `gorm:"type:enum('enabled','disabled','deprecated');not null;default:'enabled'"`          // 状态
+
+	ContextWindow   int64 `gorm:"type:int unsigned;not null;default:0"` // 上下文窗口 token 数，0 表示未知
+	MaxOutputTokens int64 `gorm:"type:int unsigned;not null;default:0"` // 最大输出 token 数，0 表示未知
+
+	RetryPolicy string     `gorm:"type:json;default:null"`                       // 重试策略，未设置的字段使用供应商的配置
+	LbStrategy  LbStrategy `gorm:"type:varchar(30);not null;default:'weighted'"` // 负载均衡策略，同一模型代码的模型组共用
+	Pipeline    string     `gorm:"type:json;default:null"`                       // hook 流水线，配置后替换供应商的流水线
 }
 
 func (Model) TableName() string {
@@ -57,6 +67,12 @@ func (m *Model) ToProto() *relaypb.Model {
 		Status:       string(m.Status),
 		CreatedAt:    timestamppb.New(m.CreatedAt),
 		UpdatedAt:    timestamppb.New(m.UpdatedAt),
+
+		ContextWindow:   m.ContextWindow,
+		MaxOutputTokens: m.MaxOutputTokens,
+		RetryPolicy:     m.RetryPolicy,
+		LbStrategy:      string(m.LbStrategy),
+		Pipeline:        m.Pipeline,
 	}
 }
 
@@ -96,6 +112,14 @@ type GetModelListRequest struct {
 	Status       ModelStatus
 }
 
+// ResolveModelRequest 解析模型请求，故障转移时排除已失败的模型和供应商 API Key
+type ResolveModelRequest struct {
+	ProviderCode     string
+	ModelCode        string
+	ExcludeModelIds  []int64
+	ExcludeApiKeyIds []int64
+}
+
 // ResolvedModel 解析后的模型
 type ResolvedModel struct {
 	ModelId         int64  // ID
@@ -103,12 +127,72 @@ type ResolvedModel struct {
 	ProviderId      int64  // 提供商 ID
 	ProviderCode    string // 提供商Code
 	BaseUrl         string // 基础URL
+	ProviderConfig  string // 供应商扩展配置（JSON）
+	Transport       string // 供应商 HTTP 传输配置（JSON）
 	ApiKeyId        int64  // 提供商 API Key ID
 	ApiKeyEncrypted string // 加密后的 API Key
 
 	InputPrice        float64 // 输入价格
 	InputCachePrice   float64 // 输入缓存价格
 	OutputPrice       float64 // 输出价格
+	AudioInputPrice   float64 // 音频输入价格
+	AudioOutputPrice  float64 // 音频输出价格
 	TokenNum          int64   // Token 数量
 	PointsPerCurrency int64   // 每个货币点数
+
+	PricingMode    PricingMode          // 计费方式
+	UnitPrice      float64              // 按量计费默认单价
+	UnitPriceRules []core.UnitPriceRule // 按量计费单价规则
+	BatchDiscount  float64              // 批量任务折扣
+
+	RetryPolicy core.RetryPolicy // 重试策略
+	Pipeline    core.Pipeline    // hook 流水线
+}
+
+// ToCoreModel 转换为运行时模型
+func (m *ResolvedModel) ToCoreModel() *core.Model {
+	return &core.Model{
+		ModelId:           m.ModelId,
+		ModelCode:         m.ModelCode,
+		ProviderId:        m.ProviderId,
+		ProviderCode:      m.ProviderCode,
+		BaseUrl:           m.BaseUrl,
+		ProviderConfig:    m.ProviderConfig,
+		Transport:         m.Transport,
+		ApiKeyId:          m.ApiKeyId,
+		ApiKeyEncrypted:   m.ApiKeyEncrypted,
+		InputPrice:        m.InputPrice,
+		InputCachePrice:   m.InputCachePrice,
+		OutputPrice:       m.OutputPrice,
+		AudioInputPrice:   m.AudioInputPrice,
+		AudioOutputPrice:  m.AudioOutputPrice,
+		TokenNum:          m.TokenNum,
+		PointsPerCurrency: m.PointsPerCurrency,
+		PricingMode:       string(m.PricingMode),
+		UnitPrice:         m.UnitPrice,
+		UnitPriceRules:    m.UnitPriceRules,
+		BatchDiscount:     m.BatchDiscount,
+		RetryPolicy:       m.RetryPolicy,
+		Pipeline:          m.Pipeline,
+	}
+}
+
+// AvailableModel 账号 API Key 可用的模型，同一 Code 在多个供应商下只返回一个
+type AvailableModel struct {
+	Code            string    // 模型代码，客户端请求使用的名称（可能是别名）
+	ActualCode      string    // 实际模型代码
+	Name            string    // 模型名称
+	ProviderCode    string    // 供应商代码
+	ContextWindow   int64     // 上下文窗口 token 数
+	MaxOutputTokens int64     // 最大输出 token 数
+	CreatedAt       time.Time // 创建时间
+}
+
+// RequestStatsRequest 一次尝试的负载均衡统计，未失败且没有延迟时只结束进行中计数，如请求本身的错误
+type RequestStatsRequest struct {
+	Member   string // 进行中请求的唯一标识
+	ModelId  int64
+	ApiKeyId int64
+	Latency  time.Duration
+	Failed   bool
 }
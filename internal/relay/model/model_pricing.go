@@ -1,10 +1,12 @@
 package model
 
 import (
+	"encoding/json"
 	"time"
 
 	"google.golang.org/protobuf/types/known/timestamppb"
 
+	"github.com/modelgate/modelgate/internal/runtime/core"
 	"github.com/modelgate/modelgate/pkg/db"
 	relaypb "github.com/modelgate/modelgate/pkg/proto/model/relay"
 	"github.com/modelgate/modelgate/pkg/types"
@@ -24,9 +26,16 @@ type ModelPricing struct {
 	InputPrice        float64      `gorm:"type:decimal(10,4) unsigned;not null;default:0"`                                // 每 1 token unit input token 价格
 	InputCachePrice   float64      `gorm:"type:decimal(10,4) unsigned;not null;default:0"`                                // 每 1 token unit input token 缓存价格
 	OutputPrice       float64      `gorm:"type:decimal(10,4) unsigned;not null;default:0"`                                // 每 1 token unit output token 价格
+	AudioInputPrice   float64      `gorm:"type:decimal(10,4) unsigned;not null;default:0"`                                // 每 1 token unit input audio token 价格，0 表示与文本相同
+	AudioOutputPrice  float64      `gorm:"type:decimal(10,4) unsigned;not null;default:0"`                                // 每 1 token unit output audio token 价格，0 表示与文本相同
 	EffectiveFrom     time.Time    `gorm:"type:datetime;not null;uniqueIndex:uk_provider_model_effective"`                // 生效时间
 	EffectiveTo       time.Time    `gorm:"type:datetime;not null;" json:"effective_to"`                                   // 失效时间
 	Status            EnableStatus `gorm:"type:enum('enabled','disabled');not null;default:'enabled'"`                    // 状态
+
+	PricingMode    PricingMode `gorm:"type:enum('token','image','second','character','search_unit');not null;default:'token'"` // 计费方式
+	UnitPrice      float64     `gorm:"type:decimal(16,8) unsigned;not null;default:0"`                                         // 按量计费默认单价，如每张图片、每秒音频、每个字符、每个搜索单元价格
+	UnitPriceRules string      `gorm:"type:json;default:null"`                                                                 // 按量计费单价规则，如按图片尺寸、质量定价
+	BatchDiscount  float64     `gorm:"type:decimal(5,4) unsigned;not null;default:0"`                                          // 批量任务折扣，如 0.5 表示半价，0 表示不打折
 }
 
 func (ModelPricing) TableName() string {
@@ -44,11 +53,26 @@ func (m *ModelPricing) ToProto() *relaypb.ModelPricing {
 		InputPrice:        float32(m.InputPrice),
 		InputCachePrice:   float32(m.InputCachePrice),
 		OutputPrice:       float32(m.OutputPrice),
+		AudioInputPrice:   float32(m.AudioInputPrice),
+		AudioOutputPrice:  float32(m.AudioOutputPrice),
 		EffectiveFrom:     timestamppb.New(m.EffectiveFrom),
 		EffectiveTo:       timestamppb.New(m.EffectiveTo),
 		Status:            string(m.Status),
 		CreatedAt:         timestamppb.New(m.CreatedAt),
+		PricingMode:       string(m.PricingMode),
+		UnitPrice:         float32(m.UnitPrice),
+		UnitPriceRules:    m.UnitPriceRules,
+		BatchDiscount:     float32(m.BatchDiscount),
+	}
+}
+
+// GetUnitPriceRules 解析按量计费单价规则
+func (m *ModelPricing) GetUnitPriceRules() (rules []core.UnitPriceRule, err error) {
+	if m.UnitPriceRules == "" {
+		return
 	}
+	err = json.Unmarshal([]byte(m.UnitPriceRules), &rules)
+	return
 }
 
 // ModelPricingFilter 过滤器
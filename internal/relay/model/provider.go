@@ -1,8 +1,11 @@
 package model
 
 import (
+	"encoding/json"
+
 	"google.golang.org/protobuf/types/known/timestamppb"
 
+	"github.com/modelgate/modelgate/internal/runtime/core"
 	"github.com/modelgate/modelgate/pkg/db"
 	relaypb "github.com/modelgate/modelgate/pkg/proto/model/relay"
 	"github.com/modelgate/modelgate/pkg/types"
@@ -16,6 +19,11 @@ type Provider struct {
 	Name    string       `gorm:"type:varchar(100);not null;default:'';index:idx_name,priority:1,length:32"` // 供应商名称
 	BaseUrl string       `gorm:"type:varchar(255);not null;default:''"`                                     // 接口URL
 	Status  EnableStatus `gorm:"type:enum('enabled','disabled');not null;default:'enabled'"`                // 状态
+	Config  string       `gorm:"type:json;default:null"`                                                    // 供应商扩展配置
+
+	RetryPolicy string `gorm:"type:json;default:null"` // 重试策略
+	Transport   string `gorm:"type:json;default:null"` // HTTP 传输配置，如代理、TLS、超时、连接数
+	Pipeline    string `gorm:"type:json;default:null"` // hook 流水线，为空时使用执行器默认的 hooks
 }
 
 func (Provider) TableName() string {
@@ -24,14 +32,32 @@ func (Provider) TableName() string {
 
 func (m *Provider) ToProto() *relaypb.Provider {
 	return &relaypb.Provider{
-		Id:        m.ID,
-		Code:      m.Code,
-		Name:      m.Name,
-		BaseUrl:   m.BaseUrl,
-		Status:    string(m.Status),
-		CreatedAt: timestamppb.New(m.CreatedAt),
-		UpdatedAt: timestamppb.New(m.UpdatedAt),
+		Id:          m.ID,
+		Code:        m.Code,
+		Name:        m.Name,
+		BaseUrl:     m.BaseUrl,
+		Status:      string(m.Status),
+		Config:      m.Config,
+		RetryPolicy: m.RetryPolicy,
+		Transport:   maskTransport(m.Transport),
+		Pipeline:    m.Pipeline,
+		CreatedAt:   timestamppb.New(m.CreatedAt),
+		UpdatedAt:   timestamppb.New(m.UpdatedAt),
+	}
+}
+
+// maskTransport 客户端私钥不返回给前端，替换为掩码
+func maskTransport(transport string) string {
+	cfg, err := core.ParseTransportConfig(transport)
+	if err != nil || (cfg.ClientKey == "" && cfg.ClientKeyEncrypted == "") {
+		return transport
+	}
+	cfg.ClientKey, cfg.ClientKeyEncrypted = core.MaskedClientKey, ""
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return ""
 	}
+	return string(data)
 }
 
 // ProviderFilter 过滤器
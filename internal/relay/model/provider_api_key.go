@@ -1,6 +1,7 @@
 package model
 
 import (
+	"errors"
 	"fmt"
 	"time"
 
@@ -11,24 +12,28 @@ import (
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+// ErrNoCapacity 供应商所有 API Key 都已达到限流或额度上限
+var ErrNoCapacity = errors.New("no capacity: all provider api keys are rate limited or out of quota")
+
 // ProviderApiKey
 type ProviderApiKey struct {
 	db.Model
 
-	ProviderId    int64        `gorm:"type:bigint;not null;default:0;index:idx_provider_status"`                                        // 供应商ID
-	ProviderCode  string       `gorm:"type:varchar(50);not null;default:''"`                                                            // 供应商代码
-	Name          string       `gorm:"type:varchar(100);not null;default:''"`                                                           // API Key名称
-	KeyPrefix     string       `gorm:"type:varchar(20);not null;default:''"`                                                            // API Key前缀
-	KeySuffix     string       `gorm:"type:varchar(10);not null;default:''"`                                                            // API Key后缀
-	KeyEncrypted  string       `gorm:"type:varchar(512);not null;default:''"`                                                           // API Key值
-	Weight        int          `gorm:"type:int;not null;default:100"`                                                                   // 权重
-	Status        ApiKeyStatus `gorm:"type:enum('enabled','disabled','cooldown');not null;default:'enabled';index:idx_provider_status"` // 状态
-	QuoteUsed     int64        `gorm:"type:bigint unsigned;not null;default:0"`                                                         // 已使用
-	QuoteLimit    *int64       `gorm:"type:bigint unsigned;default:null"`                                                               // 限额，null 不限
-	RateLimit     *int         `gorm:"type:int unsigned;default:null"`                                                                  // QPS 限流, null不限
-	LastUsedAt    *time.Time   `gorm:"type:datetime"`                                                                                   // 最后使用时间
-	FaildCount    int          `gorm:"type:int;not null;default:0"`                                                                     // 失败次数
-	CoolDownUntil *time.Time   `gorm:"type:datetime"`                                                                                   // 冷却结束时间
+	ProviderId     int64        `gorm:"type:bigint;not null;default:0;index:idx_provider_status"`                                        // 供应商ID
+	ProviderCode   string       `gorm:"type:varchar(50);not null;default:''"`                                                            // 供应商代码
+	Name           string       `gorm:"type:varchar(100);not null;default:''"`                                                           // API Key名称
+	KeyPrefix      string       `gorm:"type:varchar(20);not null;default:''"`                                                            // API Key前缀
+	KeySuffix      string       `gorm:"type:varchar(10);not null;default:''"`                                                            // API Key后缀
+	KeyEncrypted   string       `gorm:"type:varchar(512);not null;default:''"`                                                           // API Key值
+	Weight         int          `gorm:"type:int;not null;default:100"`                                                                   // 权重
+	Status         ApiKeyStatus `gorm:"type:enum('enabled','disabled','cooldown');not null;default:'enabled';index:idx_provider_status"` // 状态
+	QuoteUsed      int64        `gorm:"type:bigint unsigned;not null;default:0"`                                                         // 已使用
+	QuoteLimit     *int64       `gorm:"type:bigint unsigned;default:null"`                                                               // 限额，null 不限
+	RateLimit      *int         `gorm:"type:int unsigned;default:null"`                                                                  // QPS 限流, null不限
+	LastUsedAt     *time.Time   `gorm:"type:datetime"`                                                                                   // 最后使用时间
+	FaildCount     int          `gorm:"type:int;not null;default:0"`                                                                     // 失败次数
+	CoolDownUntil  *time.Time   `gorm:"type:datetime"`                                                                                   // 冷却结束时间
+	DisabledReason string       `gorm:"type:varchar(255);not null;default:''"`                                                           // 自动禁用原因
 }
 
 // TableName 表名
@@ -42,18 +47,22 @@ func (m *ProviderApiKey) GetWeight() int {
 
 func (m *ProviderApiKey) ToProto() *relaypb.ProviderApiKey {
 	item := &relaypb.ProviderApiKey{
-		Id:            m.ID,
-		ProviderId:    m.ProviderId,
-		ProviderCode:  m.ProviderCode,
-		Name:          m.Name,
-		Key:           fmt.Sprintf("%s...%s", m.KeyPrefix, m.KeySuffix),
-		Weight:        int64(m.Weight),
-		Status:        string(m.Status),
-		FaildCount:    int64(m.FaildCount),
-		LastUsedAt:    timestamppb.New(lo.FromPtr(m.LastUsedAt)),
-		CoolDownUntil: timestamppb.New(lo.FromPtr(m.CoolDownUntil)),
-		CreatedAt:     timestamppb.New(m.CreatedAt),
-		UpdatedAt:     timestamppb.New(m.UpdatedAt),
+		Id:             m.ID,
+		ProviderId:     m.ProviderId,
+		ProviderCode:   m.ProviderCode,
+		Name:           m.Name,
+		Key:            fmt.Sprintf("%s...%s", m.KeyPrefix, m.KeySuffix),
+		Weight:         int64(m.Weight),
+		Status:         string(m.Status),
+		FaildCount:     int64(m.FaildCount),
+		LastUsedAt:     timestamppb.New(lo.FromPtr(m.LastUsedAt)),
+		CoolDownUntil:  timestamppb.New(lo.FromPtr(m.CoolDownUntil)),
+		CreatedAt:      timestamppb.New(m.CreatedAt),
+		UpdatedAt:      timestamppb.New(m.UpdatedAt),
+		DisabledReason: m.DisabledReason,
+		QuoteUsed:      m.QuoteUsed,
+		QuoteLimit:     lo.FromPtr(m.QuoteLimit),
+		RateLimit:      int64(lo.FromPtr(m.RateLimit)),
 	}
 	return item
 }
@@ -66,6 +75,7 @@ type ProviderApiKeyFilter struct {
 	ProviderCode db.F[string]
 	Name         db.F[string]
 	Status       db.F[ApiKeyStatus]
+	Statuses     db.F[[]ApiKeyStatus] `gorm:"column:status"`
 }
 
 type CreateProviderApiKeyRequest struct {
@@ -89,3 +99,11 @@ type GetProviderApiKeyListRequest struct {
 	Name         string
 	Status       ApiKeyStatus
 }
+
+// ReportProviderApiKeyResultRequest 上报一次调用结果，用于熔断
+type ReportProviderApiKeyResultRequest struct {
+	ApiKeyId    int64
+	Success     bool
+	RateLimited bool   // 429，直接进入冷却
+	Rejected    string // 上游拒绝该 Key 的原因，如鉴权失败、余额不足，不为空时禁用该 Key
+}
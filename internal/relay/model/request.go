@@ -118,12 +118,16 @@ type CreateRequestRequest struct {
 	CompletionTokens int64
 	TotalTokens      int64
 	Status           RequestStatus
+	AttemptOnly      bool // 只创建尝试记录，不修改请求，如对冲请求的尝试
 }
 
 type UpdateRequestCompletedRequest struct {
 	RequestUUID      utils.UUIDv7
 	AttemptNo        int
 	ProviderCode     string
+	ProviderId       int64
+	ProviderApiKeyId int64
+	ModelId          int64
 	ActualModel      string
 	PromptTokens     int64
 	CompletionTokens int64
@@ -131,4 +135,5 @@ type UpdateRequestCompletedRequest struct {
 	Status           RequestStatus
 	ErrorCode        int
 	ErrorMessage     string
+	AttemptOnly      bool // 只更新尝试记录，如对冲请求中被取消的一方
 }
@@ -14,6 +14,9 @@ const (
 	TableRelayStat        = "relay_stats"
 	TableRelayUsage       = "relay_usages"
 	TableRelayHourlyUsage = "relay_hourly_usages"
+	TableFile             = "files"
+	TableBatch            = "batches"
+	TableFileChunk        = "file_chunks"
 )
 
 const (
@@ -41,6 +44,17 @@ const (
 	CurrencyPOINT Currency = "POINT" // 点数
 )
 
+// PricingMode 计费方式
+type PricingMode string
+
+const (
+	PricingModeToken      PricingMode = "token"       // 按 token
+	PricingModeImage      PricingMode = "image"       // 按图片张数
+	PricingModeSecond     PricingMode = "second"      // 按音频秒数
+	PricingModeCharacter  PricingMode = "character"   // 按字符数
+	PricingModeSearchUnit PricingMode = "search_unit" // 按搜索单元
+)
+
 // ApiKeyStatus API密钥状态
 type ApiKeyStatus string
 
@@ -51,6 +65,23 @@ const (
 	ApiKeyStatusRevoked  ApiKeyStatus = "revoked"  // 撤销
 )
 
+// LbStrategy 负载均衡策略
+type LbStrategy string
+
+const (
+	LbStrategyWeighted         LbStrategy = "weighted"          // 按静态权重随机
+	LbStrategyEWMALatency      LbStrategy = "ewma_latency"      // 按延迟的指数加权移动平均，延迟越低权重越高
+	LbStrategyLeastOutstanding LbStrategy = "least_outstanding" // 选择进行中请求最少的
+	LbStrategyErrorPenalized   LbStrategy = "error_penalized"   // 按错误率降低权重
+)
+
+var AllLbStrategies = []LbStrategy{
+	LbStrategyWeighted,
+	LbStrategyEWMALatency,
+	LbStrategyLeastOutstanding,
+	LbStrategyErrorPenalized,
+}
+
 // EnableStatus 启用状态
 type EnableStatus string
 
@@ -60,7 +91,18 @@ const (
 )
 
 const (
-	WorkerKeyLeader = "relay:worker:leader"
+	WorkerKeyLeader      = "relay:worker:leader"
+	WorkerKeyBatchLeader = "relay:worker:batch_leader"
+)
+
+const (
+	ProviderApiKeyBreakerPrefix = "relay:provider_api_key:breaker:" // 熔断状态，hash
+	ProviderApiKeyProbePrefix   = "relay:provider_api_key:probe:"   // 半开探测锁
+	ProviderApiKeyRatePrefix    = "relay:provider_api_key:rate:"    // 每秒请求数
+)
+
+const (
+	BalanceStatsPrefix = "relay:balance:" // 负载均衡统计，relay:balance:{model|provider_api_key}:{id}
 )
 
 const (
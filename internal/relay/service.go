@@ -3,12 +3,14 @@ package relay
 
 import (
 	"context"
+	"io"
 
 	"github.com/modelgate/modelgate/internal/relay/model"
 )
 
 type Service interface {
 	StartWorker(ctx context.Context)
+	StartBatchWorker(ctx context.Context)
 
 	CreateAccountApiKey(ctx context.Context, req *model.CreateAccountApiKeyRequest) (*model.AccountApiKey, error)
 	UpdateAccountApiKey(ctx context.Context, req *model.UpdateAccountApiKeyRequest) (*model.AccountApiKey, error)
@@ -25,12 +27,16 @@ type Service interface {
 	UpdateProviderApiKey(ctx context.Context, req *model.UpdateProviderApiKeyRequest) (*model.ProviderApiKey, error)
 	DeleteProviderApiKeys(ctx context.Context, req *model.DeleteProviderApiKeysRequest) error
 	GetProviderApiKeyList(ctx context.Context, req *model.GetProviderApiKeyListRequest) (int64, []*model.ProviderApiKey, error)
+	ReportProviderApiKeyResult(ctx context.Context, req *model.ReportProviderApiKeyResultRequest) error
 
 	CreateModel(ctx context.Context, req *model.CreateModelRequest) (*model.Model, error)
 	UpdateModel(ctx context.Context, req *model.UpdateModelRequest) (*model.Model, error)
 	DeleteModels(ctx context.Context, req *model.DeleteModelsRequest) error
 	GetModelList(ctx context.Context, req *model.GetModelListRequest) (int64, []*model.Model, error)
-	ResolveModel(ctx context.Context, provider string, modelCode string) (info *model.ResolvedModel, err error)
+	GetAvailableModelList(ctx context.Context, scope *model.ApiKeyScope) ([]*model.AvailableModel, error)
+	ResolveModel(ctx context.Context, req *model.ResolveModelRequest) (info *model.ResolvedModel, err error)
+	StartRequestStats(ctx context.Context, req *model.RequestStatsRequest) error
+	FinishRequestStats(ctx context.Context, req *model.RequestStatsRequest) error
 
 	CreateModelPricing(ctx context.Context, req *model.CreateModelPricingRequest) (*model.ModelPricing, error)
 	UpdateModelPricing(ctx context.Context, req *model.UpdateModelPricingRequest) (*model.ModelPricing, error)
@@ -60,4 +66,15 @@ type Service interface {
 	AddPointUsage(ctx context.Context, providerCode string, providerApiKeyId, accountApiKeyId int64, value int64) error
 	GetRelayInfo(ctx context.Context) (*model.RelayInfo, error)
 	GetRelayUsage(ctx context.Context) (*model.RelayUsage, error)
+
+	CreateFile(ctx context.Context, req *model.CreateFileRequest) (*model.File, error)
+	GetFile(ctx context.Context, accountId int64, id int64) (*model.File, error)
+	GetFileList(ctx context.Context, req *model.GetFileListRequest) ([]*model.File, error)
+	OpenFileContent(ctx context.Context, accountId int64, id int64) (*model.File, io.ReadCloser, error)
+	DeleteFile(ctx context.Context, accountId int64, id int64) error
+
+	CreateBatch(ctx context.Context, req *model.CreateBatchRequest) (*model.Batch, error)
+	GetBatch(ctx context.Context, accountId int64, id int64) (*model.Batch, error)
+	GetBatchList(ctx context.Context, req *model.GetBatchListRequest) ([]*model.Batch, error)
+	CancelBatch(ctx context.Context, accountId int64, id int64) (*model.Batch, error)
 }
@@ -16,9 +16,12 @@ import (
 )
 
 func (s *Service) CreateAccountApiKey(ctx context.Context, req *model.CreateAccountApiKeyRequest) (info *model.AccountApiKey, err error) {
-	scope, err := json.Marshal(req.AccountApiKey.Scope)
-	if err != nil {
-		return
+	// 权限本身是 JSON 时直接保存
+	scope := []byte(req.AccountApiKey.Scope)
+	if !json.Valid(scope) {
+		if scope, err = json.Marshal(req.AccountApiKey.Scope); err != nil {
+			return
+		}
 	}
 	rawKey := utils.GenApiKey()
 	prefix, suffix := utils.MaskApiKey(rawKey)
@@ -0,0 +1,243 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/samber/lo"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/modelgate/modelgate/internal/relay/model"
+	"github.com/modelgate/modelgate/pkg/utils"
+)
+
+const (
+	balanceKindModel          = "model"
+	balanceKindProviderApiKey = "provider_api_key"
+
+	balanceAlpha       = 0.1              // EWMA 平滑系数，越大越偏向最近的结果
+	balanceInflightTTL = 10 * time.Minute // 超过该时间仍未结束的请求不再计入进行中，避免实例退出后计数无法释放
+	balanceStatsTTL    = 24 * time.Hour
+	balanceMinFactor   = 0.05 // 降权的下限，保留少量流量用于更新统计
+)
+
+// balanceStats 候选项的实时统计，所有实例共享
+type balanceStats struct {
+	Latency  float64 // 延迟 EWMA，毫秒，0 表示还没有数据
+	ErrRate  float64 // 错误率 EWMA
+	Inflight int64   // 进行中的请求数
+}
+
+// balanceCandidate 参与负载均衡的候选项
+type balanceCandidate struct {
+	Weight int
+	Stats  balanceStats
+}
+
+// balanceStrategy 负载均衡策略，返回选中的候选项下标
+type balanceStrategy interface {
+	Pick(candidates []balanceCandidate) int
+}
+
+var balanceStrategies = map[model.LbStrategy]balanceStrategy{}
+
+// registerBalanceStrategy 注册负载均衡策略
+func registerBalanceStrategy(name model.LbStrategy, strategy balanceStrategy) {
+	balanceStrategies[name] = strategy
+}
+
+func init() {
+	registerBalanceStrategy(model.LbStrategyWeighted, weightedStrategy{})
+	registerBalanceStrategy(model.LbStrategyEWMALatency, ewmaLatencyStrategy{})
+	registerBalanceStrategy(model.LbStrategyLeastOutstanding, leastOutstandingStrategy{})
+	registerBalanceStrategy(model.LbStrategyErrorPenalized, errorPenalizedStrategy{})
+}
+
+// weightedStrategy 按静态权重随机
+type weightedStrategy struct{}
+
+func (weightedStrategy) Pick(candidates []balanceCandidate) int {
+	return pickIndexByWeight(lo.Map(candidates, func(c balanceCandidate, _ int) float64 {
+		return float64(c.Weight)
+	}))
+}
+
+// ewmaLatencyStrategy 权重除以延迟，没有延迟数据的候选项按平均延迟计算
+type ewmaLatencyStrategy struct{}
+
+func (ewmaLatencyStrategy) Pick(candidates []balanceCandidate) int {
+	known := lo.Filter(candidates, func(c balanceCandidate, _ int) bool { return c.Stats.Latency > 0 })
+	avg := 1.0
+	if len(known) > 0 {
+		avg = lo.SumBy(known, func(c balanceCandidate) float64 { return c.Stats.Latency }) / float64(len(known))
+	}
+	return pickIndexByWeight(lo.Map(candidates, func(c balanceCandidate, _ int) float64 {
+		latency := lo.Ternary(c.Stats.Latency > 0, c.Stats.Latency, avg)
+		return float64(c.Weight) / latency
+	}))
+}
+
+// leastOutstandingStrategy 选择进行中请求数与权重之比最小的，相同时按权重随机
+type leastOutstandingStrategy struct{}
+
+func (leastOutstandingStrategy) Pick(candidates []balanceCandidate) int {
+	load := lo.Map(candidates, func(c balanceCandidate, _ int) float64 {
+		return float64(c.Stats.Inflight+1) / float64(max(c.Weight, 1))
+	})
+	minLoad := lo.Min(load)
+	return pickIndexByWeight(lo.Map(candidates, func(c balanceCandidate, i int) float64 {
+		return lo.Ternary(load[i] == minLoad, float64(c.Weight), 0)
+	}))
+}
+
+// errorPenalizedStrategy 权重乘以 (1-错误率)²，错误率越高流量越少
+type errorPenalizedStrategy struct{}
+
+func (errorPenalizedStrategy) Pick(candidates []balanceCandidate) int {
+	return pickIndexByWeight(lo.Map(candidates, func(c balanceCandidate, _ int) float64 {
+		factor := math.Pow(1-min(c.Stats.ErrRate, 1), 2)
+		return float64(c.Weight) * max(factor, balanceMinFactor)
+	}))
+}
+
+// pickIndexByWeight 按权重随机选择下标，权重都为 0 时随机选择
+func pickIndexByWeight(weights []float64) int {
+	total := lo.Sum(weights)
+	if total <= 0 {
+		return rand.IntN(len(weights))
+	}
+	n := rand.Float64() * total
+	for i, w := range weights {
+		n -= w
+		if n < 0 {
+			return i
+		}
+	}
+	return len(weights) - 1
+}
+
+// pickBalanced 按策略选择，默认的权重策略不读取统计
+func pickBalanced[T utils.Weighter](ctx context.Context, s *Service, strategy model.LbStrategy, kind string, list []T, id func(T) int64) T {
+	impl, ok := balanceStrategies[strategy]
+	if !ok || strategy == model.LbStrategyWeighted || len(list) == 1 {
+		return utils.PickByWeight(list)
+	}
+	stats, err := s.loadBalanceStats(ctx, kind, lo.Map(list, func(item T, _ int) int64 { return id(item) }))
+	if err != nil {
+		log.Errorf("load balance stats error, fallback to weighted: %v", err)
+		return utils.PickByWeight(list)
+	}
+	candidates := lo.Map(list, func(item T, i int) balanceCandidate {
+		return balanceCandidate{Weight: item.GetWeight(), Stats: stats[i]}
+	})
+	return list[impl.Pick(candidates)]
+}
+
+func balanceStatsKey(kind string, id int64) string {
+	return fmt.Sprintf("%s%s:%d", model.BalanceStatsPrefix, kind, id)
+}
+
+func balanceInflightKey(kind string, id int64) string {
+	return balanceStatsKey(kind, id) + ":inflight"
+}
+
+// loadBalanceStats 批量读取统计
+func (s *Service) loadBalanceStats(ctx context.Context, kind string, ids []int64) (stats []balanceStats, err error) {
+	minScore := strconv.FormatInt(time.Now().Add(-balanceInflightTTL).UnixMilli(), 10)
+	pipe := s.redisClient.Pipeline()
+	hashCmds := make([]*redis.SliceCmd, len(ids))
+	countCmds := make([]*redis.IntCmd, len(ids))
+	for i, id := range ids {
+		hashCmds[i] = pipe.HMGet(ctx, balanceStatsKey(kind, id), "latency", "err")
+		countCmds[i] = pipe.ZCount(ctx, balanceInflightKey(kind, id), minScore, "+inf")
+	}
+	if _, err = pipe.Exec(ctx); err != nil {
+		return
+	}
+	stats = make([]balanceStats, len(ids))
+	for i := range ids {
+		values := hashCmds[i].Val()
+		if len(values) == 2 {
+			stats[i].Latency = parseFloat(values[0])
+			stats[i].ErrRate = parseFloat(values[1])
+		}
+		stats[i].Inflight = countCmds[i].Val()
+	}
+	return
+}
+
+func parseFloat(v any) float64 {
+	str, _ := v.(string)
+	f, _ := strconv.ParseFloat(str, 64)
+	return f
+}
+
+// finishStatsScript 结束进行中的请求并更新错误率和延迟的 EWMA，failed、latency 小于 0 表示不更新
+var finishStatsScript = redis.NewScript(`
+	local key = KEYS[1]
+	local alpha = tonumber(ARGV[1])
+	local failed = tonumber(ARGV[2])
+	local latency = tonumber(ARGV[3])
+
+	if failed >= 0 then
+		local err_rate = tonumber(redis.call('HGET', key, 'err') or '0')
+		redis.call('HSET', key, 'err', alpha * failed + (1 - alpha) * err_rate)
+	end
+	if latency >= 0 then
+		local old = tonumber(redis.call('HGET', key, 'latency') or '0')
+		if old == 0 then
+			old = latency
+		end
+		redis.call('HSET', key, 'latency', alpha * latency + (1 - alpha) * old)
+	end
+	redis.call('PEXPIRE', key, tonumber(ARGV[4]))
+	redis.call('ZREM', KEYS[2], ARGV[5])
+	return 1
+`)
+
+// balanceTargets 一次请求涉及的统计对象
+func balanceTargets(req *model.RequestStatsRequest) map[string]int64 {
+	return map[string]int64{
+		balanceKindModel:          req.ModelId,
+		balanceKindProviderApiKey: req.ApiKeyId,
+	}
+}
+
+// StartRequestStats 记录一次进行中的请求
+func (s *Service) StartRequestStats(ctx context.Context, req *model.RequestStatsRequest) (err error) {
+	now := time.Now()
+	pipe := s.redisClient.Pipeline()
+	for kind, id := range balanceTargets(req) {
+		key := balanceInflightKey(kind, id)
+		pipe.ZRemRangeByScore(ctx, key, "-inf", strconv.FormatInt(now.Add(-balanceInflightTTL).UnixMilli(), 10))
+		pipe.ZAdd(ctx, key, redis.Z{Score: float64(now.UnixMilli()), Member: req.Member})
+		pipe.Expire(ctx, key, balanceInflightTTL)
+	}
+	_, err = pipe.Exec(ctx)
+	return
+}
+
+// FinishRequestStats 结束一次请求，失败时更新错误率，成功时同时更新延迟
+func (s *Service) FinishRequestStats(ctx context.Context, req *model.RequestStatsRequest) (err error) {
+	failed, latency := -1, int64(-1)
+	switch {
+	case req.Failed:
+		failed = 1
+	case req.Latency > 0:
+		failed, latency = 0, req.Latency.Milliseconds()
+	}
+	for kind, id := range balanceTargets(req) {
+		keys := []string{balanceStatsKey(kind, id), balanceInflightKey(kind, id)}
+		err = finishStatsScript.Run(ctx, s.redisClient, keys,
+			balanceAlpha, failed, latency, balanceStatsTTL.Milliseconds(), req.Member).Err()
+		if err != nil {
+			return
+		}
+	}
+	return
+}
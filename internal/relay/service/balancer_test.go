@@ -0,0 +1,43 @@
+package service
+
+import "testing"
+
+// pickCounts 多次选择后各下标被选中的次数
+func pickCounts(strategy balanceStrategy, candidates []balanceCandidate, n int) []int {
+	counts := make([]int, len(candidates))
+	for range n {
+		counts[strategy.Pick(candidates)]++
+	}
+	return counts
+}
+
+func TestEWMALatencyStrategy(t *testing.T) {
+	counts := pickCounts(ewmaLatencyStrategy{}, []balanceCandidate{
+		{Weight: 100, Stats: balanceStats{Latency: 100}},
+		{Weight: 100, Stats: balanceStats{Latency: 1000}},
+	}, 10000)
+	if counts[0] < counts[1]*5 {
+		t.Fatalf("fast candidate should get most traffic, got %v", counts)
+	}
+}
+
+func TestLeastOutstandingStrategy(t *testing.T) {
+	counts := pickCounts(leastOutstandingStrategy{}, []balanceCandidate{
+		{Weight: 100, Stats: balanceStats{Inflight: 5}},
+		{Weight: 100, Stats: balanceStats{Inflight: 1}},
+		{Weight: 100, Stats: balanceStats{Inflight: 3}},
+	}, 100)
+	if counts[1] != 100 {
+		t.Fatalf("least outstanding candidate should always be picked, got %v", counts)
+	}
+}
+
+func TestErrorPenalizedStrategy(t *testing.T) {
+	counts := pickCounts(errorPenalizedStrategy{}, []balanceCandidate{
+		{Weight: 100, Stats: balanceStats{ErrRate: 0}},
+		{Weight: 100, Stats: balanceStats{ErrRate: 1}},
+	}, 10000)
+	if counts[1] == 0 || counts[0] < counts[1]*10 {
+		t.Fatalf("failing candidate should keep a small share, got %v", counts)
+	}
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/samber/lo"
+
+	"github.com/modelgate/modelgate/internal/relay/model"
+	"github.com/modelgate/modelgate/pkg/db"
+)
+
+// defaultCompletionWindow 默认完成时间窗口
+const defaultCompletionWindow = "24h"
+
+func (s *Service) CreateBatch(ctx context.Context, req *model.CreateBatchRequest) (info *model.Batch, err error) {
+	if !lo.Contains(model.BatchEndpoints, req.Endpoint) {
+		err = fmt.Errorf("unsupported batch endpoint: %s", req.Endpoint)
+		return
+	}
+	window := lo.CoalesceOrEmpty(req.CompletionWindow, defaultCompletionWindow)
+	duration, err := time.ParseDuration(window)
+	if err != nil || duration <= 0 {
+		err = fmt.Errorf("invalid completion_window: %s", window)
+		return
+	}
+	metadata := "{}"
+	if len(req.Metadata) > 0 && string(req.Metadata) != "null" {
+		var m map[string]any
+		if err = json.Unmarshal(req.Metadata, &m); err != nil {
+			err = fmt.Errorf("metadata must be a JSON object: %v", err)
+			return
+		}
+		metadata = string(req.Metadata)
+	}
+	fileId, err := model.ParseFileId(req.InputFileId)
+	if err != nil {
+		return
+	}
+	file, err := s.GetFile(ctx, req.AccountId, fileId)
+	if err != nil {
+		return
+	}
+	if file.Purpose != model.FilePurposeBatch {
+		err = fmt.Errorf("file %s purpose must be batch", req.InputFileId)
+		return
+	}
+	info = &model.Batch{
+		AccountId:        req.AccountId,
+		AccountApiKeyId:  req.AccountApiKeyId,
+		Endpoint:         req.Endpoint,
+		CompletionWindow: window,
+		InputFileId:      file.ID,
+		Status:           model.BatchStatusValidating,
+		Metadata:         metadata,
+		ExpiresAt:        time.Now().Add(duration),
+	}
+	err = s.batchDao.Create(ctx, info)
+	return
+}
+
+// GetBatch 获取账户的批量任务
+func (s *Service) GetBatch(ctx context.Context, accountId int64, id int64) (info *model.Batch, err error) {
+	info, err = s.batchDao.FindOne(ctx, &model.BatchFilter{ID: db.Eq(id), AccountId: db.Eq(accountId)})
+	if db.IsRecordNotFound(err) {
+		err = fmt.Errorf("batch not found: %s", model.BatchIdPrefix+strconv.FormatInt(id, 10))
+	}
+	return
+}
+
+// GetBatchList 按 ID 倒序返回账户的批量任务
+func (s *Service) GetBatchList(ctx context.Context, req *model.GetBatchListRequest) (list []*model.Batch, err error) {
+	f := &model.BatchFilter{
+		ID:        db.Lt(req.After, db.OmitIfZero[int64]()),
+		AccountId: db.Eq(req.AccountId),
+	}
+	list, err = s.batchDao.Find(ctx, f, db.WithPaging(1, req.Limit), db.WithOrder("-id", nil))
+	return
+}
+
+// CancelBatch 标记为取消中，由批量任务执行器停止执行并生成已完成部分的结果
+func (s *Service) CancelBatch(ctx context.Context, accountId int64, id int64) (info *model.Batch, err error) {
+	if info, err = s.GetBatch(ctx, accountId, id); err != nil {
+		return
+	}
+	if info.Status == model.BatchStatusCancelling {
+		return
+	}
+	if info.Status != model.BatchStatusValidating && info.Status != model.BatchStatusInProgress {
+		err = fmt.Errorf("cannot cancel batch with status %s", info.Status)
+		return
+	}
+	err = s.batchDao.UpdateOne(ctx, info, map[string]any{
+		"status":        model.BatchStatusCancelling,
+		"cancelling_at": time.Now(),
+	})
+	return
+}
@@ -0,0 +1,414 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"path"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/samber/lo"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/modelgate/modelgate/internal/config"
+	"github.com/modelgate/modelgate/internal/relay/model"
+	"github.com/modelgate/modelgate/internal/runtime"
+	"github.com/modelgate/modelgate/internal/runtime/core"
+	"github.com/modelgate/modelgate/pkg/db"
+	"github.com/modelgate/modelgate/pkg/utils"
+)
+
+// defaultBatchConcurrency 默认批量任务并发数
+const defaultBatchConcurrency = 4
+
+// StartBatchWorker 执行批量任务，同一时间只有一个实例执行
+func (s *Service) StartBatchWorker(ctx context.Context) {
+	log.Info("Start batch worker...")
+	go func() {
+		ticker := time.NewTicker(10 * time.Second)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				log.Info("leader lost, stop batch worker")
+				return
+			case <-ticker.C:
+				if err := s.processBatches(ctx); err != nil {
+					log.Error("process batches error: ", err)
+				}
+			}
+		}
+	}()
+}
+
+func (s *Service) processBatches(ctx context.Context) (err error) {
+	list, err := s.batchDao.Find(ctx, &model.BatchFilter{
+		Status: db.In([]model.BatchStatus{model.BatchStatusValidating, model.BatchStatusInProgress, model.BatchStatusCancelling}),
+	}, db.WithOrder("id", nil))
+	if err != nil {
+		return
+	}
+	for _, batch := range list {
+		if ctx.Err() != nil {
+			return
+		}
+		if pErr := s.processBatch(ctx, batch); pErr != nil {
+			log.Errorf("process batch %d error: %v", batch.ID, pErr)
+		}
+	}
+	return
+}
+
+// processBatch 按并发数分段执行，每段执行完追加到结果文件并保存进度
+// 结果文件是进度的依据，切换实例或中断后跳过已写入结果的 custom_id，避免重复执行和计费
+func (s *Service) processBatch(ctx context.Context, batch *model.Batch) (err error) {
+	if batch.Status == model.BatchStatusCancelling {
+		return s.finishBatch(ctx, batch, model.BatchStatusCancelled)
+	}
+	if time.Now().After(batch.ExpiresAt) {
+		return s.finishBatch(ctx, batch, model.BatchStatusExpired)
+	}
+	lines, err := s.readBatchLines(ctx, batch)
+	if err != nil {
+		return s.failBatch(ctx, batch, err)
+	}
+	if batch.Status == model.BatchStatusValidating {
+		if vErr := validateBatchLines(batch.Endpoint, lines); vErr != nil {
+			return s.failBatch(ctx, batch, vErr)
+		}
+		if err = s.batchDao.UpdateOne(ctx, batch, map[string]any{
+			"status":         model.BatchStatusInProgress,
+			"total_count":    len(lines),
+			"in_progress_at": time.Now(),
+		}); err != nil {
+			return
+		}
+	}
+
+	scope, err := s.batchApiKeyScope(ctx, batch)
+	if err != nil {
+		return s.failBatch(ctx, batch, err)
+	}
+	done, err := s.batchDoneIds(ctx, batch)
+	if err != nil {
+		return
+	}
+	pending := lo.Filter(lines, func(line []byte, _ int) bool {
+		var req model.BatchRequestLine
+		_ = json.Unmarshal(line, &req)
+		return !done[req.CustomId]
+	})
+	concurrency := lo.Ternary(config.GetConfig().Batch.Concurrency > 0, config.GetConfig().Batch.Concurrency, defaultBatchConcurrency)
+	for start := 0; start < len(pending); start += concurrency {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		// 取消或过期时停止执行，已完成部分仍生成结果文件
+		current, fErr := s.batchDao.FindOneByID(ctx, batch.ID)
+		if fErr != nil {
+			return fErr
+		}
+		if current.Status == model.BatchStatusCancelling {
+			return s.finishBatch(ctx, current, model.BatchStatusCancelled)
+		}
+		if time.Now().After(batch.ExpiresAt) {
+			return s.finishBatch(ctx, current, model.BatchStatusExpired)
+		}
+
+		chunk := pending[start:min(start+concurrency, len(pending))]
+		results := make([]*model.BatchResultLine, len(chunk))
+		deferred := make([]error, len(chunk))
+		var wg sync.WaitGroup
+		for i, line := range chunk {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				results[i], deferred[i] = s.runBatchLine(ctx, batch, scope, line)
+			}()
+		}
+		wg.Wait()
+
+		var output, errOutput []byte
+		var completed, failed int64
+		for _, result := range results {
+			if result == nil {
+				continue
+			}
+			data, mErr := json.Marshal(result)
+			if mErr != nil {
+				return mErr
+			}
+			if result.Error != nil {
+				errOutput = append(append(errOutput, data...), '\n')
+				failed++
+			} else {
+				output = append(append(output, data...), '\n')
+				completed++
+			}
+		}
+		if err = s.appendBatchResult(ctx, batchResultPath(batch.ID, "output"), output); err != nil {
+			return
+		}
+		if err = s.appendBatchResult(ctx, batchResultPath(batch.ID, "error"), errOutput); err != nil {
+			return
+		}
+		batch.CompletedCount += completed
+		batch.FailedCount += failed
+		if err = s.batchDao.UpdateOne(ctx, batch, map[string]any{
+			"completed_count": batch.CompletedCount,
+			"failed_count":    batch.FailedCount,
+		}); err != nil {
+			return
+		}
+		// 供应商暂时没有可用额度，停止本次执行，未写入结果的请求下次继续
+		if dErr, ok := lo.Find(deferred, func(err error) bool { return err != nil }); ok {
+			log.Warnf("batch %d deferred: %v", batch.ID, dErr)
+			return
+		}
+	}
+	return s.finishBatch(ctx, batch, model.BatchStatusCompleted)
+}
+
+// batchDoneIds 读取结果文件中已有的 custom_id，并按结果文件修正已保存的计数
+// 写入中断留下的不完整行忽略，并补上换行，该行对应的请求会重新执行
+func (s *Service) batchDoneIds(ctx context.Context, batch *model.Batch) (done map[string]bool, err error) {
+	done = make(map[string]bool)
+	counts := make(map[string]int64, 2)
+	for _, kind := range []string{"output", "error"} {
+		relPath := batchResultPath(batch.ID, kind)
+		content, oErr := s.storage.Open(ctx, relPath)
+		if errors.Is(oErr, fs.ErrNotExist) {
+			continue
+		} else if oErr != nil {
+			return nil, oErr
+		}
+		data, rErr := io.ReadAll(content)
+		_ = content.Close()
+		if rErr != nil {
+			return nil, rErr
+		}
+		for line := range bytes.Lines(data) {
+			var result model.BatchResultLine
+			if json.Unmarshal(line, &result) != nil || result.CustomId == "" || done[result.CustomId] {
+				continue
+			}
+			done[result.CustomId] = true
+			counts[kind]++
+		}
+		if len(data) > 0 && data[len(data)-1] != '\n' {
+			if err = s.storage.Append(ctx, relPath, []byte{'\n'}); err != nil {
+				return
+			}
+		}
+	}
+	if counts["output"] == batch.CompletedCount && counts["error"] == batch.FailedCount {
+		return
+	}
+	batch.CompletedCount, batch.FailedCount = counts["output"], counts["error"]
+	err = s.batchDao.UpdateOne(ctx, batch, map[string]any{
+		"completed_count": batch.CompletedCount,
+		"failed_count":    batch.FailedCount,
+	})
+	return
+}
+
+func (s *Service) readBatchLines(ctx context.Context, batch *model.Batch) (lines [][]byte, err error) {
+	file, err := s.GetFile(ctx, batch.AccountId, batch.InputFileId)
+	if err != nil {
+		return
+	}
+	content, err := s.storage.Open(ctx, file.Path)
+	if err != nil {
+		return
+	}
+	defer content.Close()
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return
+	}
+	for line := range bytes.Lines(data) {
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) == 0 {
+		err = errors.New("input file is empty")
+	}
+	return
+}
+
+// validateBatchLines 每行必须是合法请求，url 与批量任务接口一致，custom_id 不能重复
+func validateBatchLines(endpoint string, lines [][]byte) error {
+	customIds := make(map[string]struct{}, len(lines))
+	for i, line := range lines {
+		var req model.BatchRequestLine
+		if err := json.Unmarshal(line, &req); err != nil {
+			return fmt.Errorf("line %d: invalid json: %v", i+1, err)
+		}
+		if req.CustomId == "" {
+			return fmt.Errorf("line %d: custom_id is required", i+1)
+		}
+		if _, ok := customIds[req.CustomId]; ok {
+			return fmt.Errorf("line %d: duplicate custom_id %s", i+1, req.CustomId)
+		}
+		customIds[req.CustomId] = struct{}{}
+		if req.Method != http.MethodPost {
+			return fmt.Errorf("line %d: method must be POST", i+1)
+		}
+		if req.Url != endpoint {
+			return fmt.Errorf("line %d: url %s does not match batch endpoint %s", i+1, req.Url, endpoint)
+		}
+		if len(req.Body) == 0 {
+			return fmt.Errorf("line %d: body is required", i+1)
+		}
+	}
+	return nil
+}
+
+func (s *Service) batchApiKeyScope(ctx context.Context, batch *model.Batch) (scope *model.ApiKeyScope, err error) {
+	apiKey, err := s.accountApiKeyDao.FindOneByID(ctx, batch.AccountApiKeyId)
+	if err != nil {
+		return
+	}
+	if apiKey.Status != model.ApiKeyStatusEnabled {
+		err = errors.New("api key is not enabled")
+		return
+	}
+	return apiKey.GetScope(), nil
+}
+
+// runBatchLine 与 API 请求走同样的执行器，按批量折扣计费
+// 供应商暂时没有可用额度时不生成结果，返回错误，稍后重试
+func (s *Service) runBatchLine(ctx context.Context, batch *model.Batch, scope *model.ApiKeyScope, line []byte) (result *model.BatchResultLine, deferErr error) {
+	requestUUID := utils.NewUUIDv7()
+	result = &model.BatchResultLine{Id: "batch_req_" + requestUUID.String()}
+	var req model.BatchRequestLine
+	_ = json.Unmarshal(line, &req)
+	result.CustomId = req.CustomId
+
+	fail := func(code string, err error) (*model.BatchResultLine, error) {
+		result.Error = &model.BatchResultError{Code: code, Message: err.Error()}
+		return result, nil
+	}
+	reqBody := make(map[string]any)
+	if err := json.Unmarshal(req.Body, &reqBody); err != nil {
+		return fail("invalid_request", err)
+	}
+	providerCode, _ := reqBody["provider"].(string)
+	modelCode, _ := reqBody["model"].(string)
+	// provider 不是标准请求参数，批量任务不支持流式
+	delete(reqBody, "provider")
+	delete(reqBody, "stream")
+	inputData, err := json.Marshal(reqBody)
+	if err != nil {
+		return fail("invalid_request", err)
+	}
+	if !scope.AllowModel(modelCode) {
+		return fail("model_not_allowed", fmt.Errorf("%w: %s", model.ErrModelNotAllowed, modelCode))
+	}
+	currentModel, err := s.ResolveModel(ctx, &model.ResolveModelRequest{ProviderCode: providerCode, ModelCode: modelCode})
+	if errors.Is(err, model.ErrNoCapacity) {
+		return nil, err
+	} else if err != nil {
+		return fail("model_not_found", err)
+	}
+
+	rCtx := core.Get()
+	defer core.Put(rCtx)
+	rCtx.RequestUUID = requestUUID
+	rCtx.AttemptNo = 1
+	rCtx.ProviderCode = providerCode
+	rCtx.ModelCode = modelCode
+	rCtx.CurrentModel = currentModel.ToCoreModel()
+	rCtx.AccountApiKeyId = batch.AccountApiKeyId
+	rCtx.AccountId = batch.AccountId
+	rCtx.UrlPath = batch.Endpoint
+	rCtx.Protocol = core.PathProtocol(batch.Endpoint)
+	rCtx.Endpoint = core.PathEndpoint(batch.Endpoint)
+	rCtx.InputBody = inputData
+	rCtx.Header = http.Header{}
+	rCtx.IsBatch = true
+	runErr := runtime.Run(ctx, rCtx)
+
+	if rCtx.HTTPResponse != nil && json.Valid(rCtx.RawResponse) {
+		result.Response = &model.BatchResultResponse{
+			StatusCode: rCtx.HTTPResponse.StatusCode,
+			RequestId:  requestUUID.String(),
+			Body:       bytes.Clone(rCtx.RawResponse),
+		}
+	}
+	if runErr != nil {
+		return fail("request_failed", runErr)
+	}
+	return
+}
+
+// failBatch 校验失败
+func (s *Service) failBatch(ctx context.Context, batch *model.Batch, cause error) error {
+	log.Errorf("batch %d failed: %v", batch.ID, cause)
+	msg := cause.Error()
+	if len(msg) > 1000 {
+		msg = msg[:1000]
+	}
+	return s.batchDao.UpdateOne(ctx, batch, map[string]any{
+		"status":        model.BatchStatusFailed,
+		"error_message": msg,
+		"failed_at":     time.Now(),
+	})
+}
+
+// finishBatch 保存结果文件并结束批量任务
+func (s *Service) finishBatch(ctx context.Context, batch *model.Batch, status model.BatchStatus) (err error) {
+	update := map[string]any{"status": status, "finalizing_at": time.Now()}
+	for kind, column := range map[string]string{"output": "output_file_id", "error": "error_file_id"} {
+		relPath := batchResultPath(batch.ID, kind)
+		size, sErr := s.storage.Size(ctx, relPath)
+		if errors.Is(sErr, fs.ErrNotExist) {
+			continue
+		} else if sErr != nil {
+			return sErr
+		}
+		file := &model.File{
+			AccountId: batch.AccountId,
+			Filename:  fmt.Sprintf("%s_%s.jsonl", batch.ObjectId(), kind),
+			Purpose:   model.FilePurposeBatchOutput,
+			Bytes:     size,
+			Path:      relPath,
+		}
+		if err = s.fileDao.Create(ctx, file); err != nil {
+			return
+		}
+		update[column] = file.ID
+	}
+	switch status {
+	case model.BatchStatusCompleted:
+		update["completed_at"] = time.Now()
+	case model.BatchStatusExpired:
+		update["expired_at"] = time.Now()
+	case model.BatchStatusCancelled:
+		update["cancelled_at"] = time.Now()
+	}
+	err = s.batchDao.UpdateOne(ctx, batch, update)
+	return
+}
+
+// batchResultPath 结果文件的相对存储路径，kind 为 output 或 error
+func batchResultPath(batchId int64, kind string) string {
+	return path.Join("batches", strconv.FormatInt(batchId, 10)+"_"+kind+".jsonl")
+}
+
+// appendBatchResult 追加一段结果，没有内容时不创建文件
+func (s *Service) appendBatchResult(ctx context.Context, relPath string, data []byte) error {
+	if len(data) == 0 {
+		return nil
+	}
+	return s.storage.Append(ctx, relPath, data)
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/fs"
+	"testing"
+
+	"go.uber.org/mock/gomock"
+
+	"github.com/modelgate/modelgate/internal/relay"
+	"github.com/modelgate/modelgate/internal/relay/model"
+)
+
+// memStorage 内存文件存储
+type memStorage map[string][]byte
+
+func (m memStorage) Create(ctx context.Context, path string, r io.Reader) (int64, error) {
+	data, err := io.ReadAll(r)
+	m[path] = data
+	return int64(len(data)), err
+}
+
+func (m memStorage) Append(ctx context.Context, path string, data []byte) error {
+	m[path] = append(m[path], data...)
+	return nil
+}
+
+func (m memStorage) Open(ctx context.Context, path string) (io.ReadCloser, error) {
+	data, ok := m[path]
+	if !ok {
+		return nil, fs.ErrNotExist
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (m memStorage) Size(ctx context.Context, path string) (int64, error) {
+	data, ok := m[path]
+	if !ok {
+		return 0, fs.ErrNotExist
+	}
+	return int64(len(data)), nil
+}
+
+func (m memStorage) Remove(ctx context.Context, path string) error {
+	delete(m, path)
+	return nil
+}
+
+func TestBatchDoneIds(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+	batchDao := relay.NewMockBatchDAO(ctl)
+	storage := memStorage{
+		batchResultPath(1, "output"): []byte(`{"custom_id":"a"}` + "\n"),
+		// 写入中断留下的不完整行
+		batchResultPath(1, "error"): []byte(`{"custom_id":"b","error":{}}` + "\n" + `{"custom_id":"c"`),
+	}
+	s := &Service{batchDao: batchDao, storage: storage}
+	// 计数保存前中断，按结果文件修正
+	batch := &model.Batch{}
+	batch.ID = 1
+	batchDao.EXPECT().UpdateOne(gomock.Any(), batch, map[string]any{"completed_count": int64(1), "failed_count": int64(1)}).Return(nil)
+
+	done, err := s.batchDoneIds(context.Background(), batch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(done) != 2 || !done["a"] || !done["b"] {
+		t.Fatalf("done: %v", done)
+	}
+	if batch.CompletedCount != 1 || batch.FailedCount != 1 {
+		t.Fatalf("counts: %d %d", batch.CompletedCount, batch.FailedCount)
+	}
+	// 补上换行，后续追加的结果不会拼到不完整行上
+	if data := storage[batchResultPath(1, "error")]; data[len(data)-1] != '\n' {
+		t.Fatalf("error file not terminated: %q", data)
+	}
+}
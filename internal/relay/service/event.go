@@ -0,0 +1,53 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/modelgate/modelgate/internal/config"
+	"github.com/modelgate/modelgate/internal/relay/model"
+)
+
+var eventClient = &http.Client{Timeout: 10 * time.Second}
+
+// sendEvent 记录事件并异步推送到配置的 Webhook，推送失败不影响请求
+func (s *Service) sendEvent(event *model.Event) {
+	event.Time = time.Now()
+	log.WithFields(log.Fields{"event": event.Type, "data": event.Data}).Warn(event.Message)
+	url := config.GetConfig().Notify.WebhookUrl
+	if url == "" {
+		return
+	}
+	go func() {
+		if err := postEvent(context.Background(), url, event); err != nil {
+			log.Errorf("send event %s error: %v", event.Type, err)
+		}
+	}()
+}
+
+func postEvent(ctx context.Context, url string, event *model.Event) (err error) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := eventClient.Do(req)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusBadRequest {
+		err = fmt.Errorf("webhook status: %d", resp.StatusCode)
+	}
+	return
+}
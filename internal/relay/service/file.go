@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/modelgate/modelgate/internal/relay/model"
+	"github.com/modelgate/modelgate/pkg/db"
+)
+
+// CreateFile 保存上传文件到文件存储
+func (s *Service) CreateFile(ctx context.Context, req *model.CreateFileRequest) (info *model.File, err error) {
+	relPath := path.Join(strconv.FormatInt(req.AccountId, 10), uuid.New().String())
+	size, err := s.storage.Create(ctx, relPath, req.Content)
+	if err != nil {
+		return
+	}
+	info = &model.File{
+		AccountId: req.AccountId,
+		Filename:  req.Filename,
+		Purpose:   req.Purpose,
+		Bytes:     size,
+		Path:      relPath,
+	}
+	if err = s.fileDao.Create(ctx, info); err != nil {
+		_ = s.storage.Remove(ctx, relPath)
+	}
+	return
+}
+
+// GetFile 获取账户的文件
+func (s *Service) GetFile(ctx context.Context, accountId int64, id int64) (info *model.File, err error) {
+	info, err = s.fileDao.FindOne(ctx, &model.FileFilter{ID: db.Eq(id), AccountId: db.Eq(accountId)})
+	if db.IsRecordNotFound(err) {
+		err = fmt.Errorf("file not found: %s", model.FileIdPrefix+strconv.FormatInt(id, 10))
+	}
+	return
+}
+
+// GetFileList 按 ID 倒序返回账户的文件
+func (s *Service) GetFileList(ctx context.Context, req *model.GetFileListRequest) (list []*model.File, err error) {
+	f := &model.FileFilter{
+		ID:        db.Lt(req.After, db.OmitIfZero[int64]()),
+		AccountId: db.Eq(req.AccountId),
+		Purpose:   db.Eq(req.Purpose, db.OmitIfZero[model.FilePurpose]()),
+	}
+	list, err = s.fileDao.Find(ctx, f, db.WithPaging(1, req.Limit), db.WithOrder("-id", nil))
+	return
+}
+
+// OpenFileContent 打开文件内容，调用方负责关闭
+func (s *Service) OpenFileContent(ctx context.Context, accountId int64, id int64) (info *model.File, content io.ReadCloser, err error) {
+	if info, err = s.GetFile(ctx, accountId, id); err != nil {
+		return
+	}
+	content, err = s.storage.Open(ctx, info.Path)
+	return
+}
+
+// DeleteFile 删除文件记录和文件内容
+func (s *Service) DeleteFile(ctx context.Context, accountId int64, id int64) (err error) {
+	info, err := s.GetFile(ctx, accountId, id)
+	if err != nil {
+		return
+	}
+	if _, err = s.fileDao.Delete(ctx, &model.FileFilter{ID: db.Eq(info.ID)}); err != nil {
+		return
+	}
+	if rErr := s.storage.Remove(ctx, info.Path); rErr != nil {
+		log.Errorf("remove file %s error: %v", info.Path, rErr)
+	}
+	return
+}
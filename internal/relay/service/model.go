@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -9,8 +10,8 @@ import (
 	log "github.com/sirupsen/logrus"
 
 	"github.com/modelgate/modelgate/internal/relay/model"
+	"github.com/modelgate/modelgate/internal/runtime/core"
 	"github.com/modelgate/modelgate/pkg/db"
-	"github.com/modelgate/modelgate/pkg/utils"
 )
 
 func (s *Service) CreateModel(ctx context.Context, req *model.CreateModelRequest) (info *model.Model, err error) {
@@ -22,6 +23,18 @@ func (s *Service) CreateModel(ctx context.Context, req *model.CreateModelRequest
 		err = fmt.Errorf("provider not enabled")
 		return
 	}
+	retryPolicy, err := normalizeRetryPolicy(req.Model.RetryPolicy)
+	if err != nil {
+		return
+	}
+	lbStrategy, err := s.createLbStrategy(ctx, req.Model.Code, req.Model.LbStrategy)
+	if err != nil {
+		return
+	}
+	pipeline, err := normalizePipeline(req.Model.Pipeline)
+	if err != nil {
+		return
+	}
 	info = &model.Model{
 		ProviderId:   provider.ID,
 		ProviderCode: provider.Code,
@@ -31,6 +44,12 @@ func (s *Service) CreateModel(ctx context.Context, req *model.CreateModelRequest
 		Priority:     int(req.Model.Priority),
 		Weight:       int(req.Model.Weight),
 		Status:       model.ModelStatus(req.Model.Status),
+
+		ContextWindow:   req.Model.ContextWindow,
+		MaxOutputTokens: req.Model.MaxOutputTokens,
+		RetryPolicy:     retryPolicy,
+		LbStrategy:      lbStrategy,
+		Pipeline:        pipeline,
 	}
 	err = s.modelDao.Create(ctx, info)
 	return
@@ -73,14 +92,72 @@ func (s *Service) UpdateModel(ctx context.Context, req *model.UpdateModelRequest
 	if lo.Contains(req.UpdateMask, "status") {
 		update["status"] = req.Model.Status
 	}
+	if lo.Contains(req.UpdateMask, "context_window") {
+		update["context_window"] = req.Model.ContextWindow
+	}
+	if lo.Contains(req.UpdateMask, "max_output_tokens") {
+		update["max_output_tokens"] = req.Model.MaxOutputTokens
+	}
+	if lo.Contains(req.UpdateMask, "retry_policy") {
+		var retryPolicy string
+		if retryPolicy, err = normalizeRetryPolicy(req.Model.RetryPolicy); err != nil {
+			return
+		}
+		update["retry_policy"] = retryPolicy
+	}
+	if lo.Contains(req.UpdateMask, "pipeline") {
+		var pipeline string
+		if pipeline, err = normalizePipeline(req.Model.Pipeline); err != nil {
+			return
+		}
+		update["pipeline"] = pipeline
+	}
+	var lbStrategy model.LbStrategy
+	if lo.Contains(req.UpdateMask, "lb_strategy") {
+		if lbStrategy, err = parseLbStrategy(req.Model.LbStrategy); err != nil {
+			return
+		}
+		update["lb_strategy"] = lbStrategy
+	}
 	if len(update) == 0 {
 		err = fmt.Errorf("no fields to update")
 		return
 	}
+	code := lo.Ternary(lo.Contains(req.UpdateMask, "code"), req.Model.Code, info.Code)
 	err = s.modelDao.UpdateOne(ctx, info, update)
+	if err != nil || lbStrategy == "" {
+		return
+	}
+	// 负载均衡策略按模型组配置，同步到同一模型代码的所有模型
+	_, err = s.modelDao.Update(ctx, &model.ModelFilter{Code: db.Eq(code)}, map[string]any{"lb_strategy": lbStrategy})
+	return
+}
+
+// parseLbStrategy 校验负载均衡策略，为空时使用权重策略
+func parseLbStrategy(value string) (strategy model.LbStrategy, err error) {
+	strategy = lo.CoalesceOrEmpty(model.LbStrategy(value), model.LbStrategyWeighted)
+	if !lo.Contains(model.AllLbStrategies, strategy) {
+		err = fmt.Errorf("invalid lb strategy: %s", value)
+	}
 	return
 }
 
+// createLbStrategy 新模型未指定策略时沿用模型组已有的策略
+func (s *Service) createLbStrategy(ctx context.Context, code, value string) (strategy model.LbStrategy, err error) {
+	if value != "" {
+		return parseLbStrategy(value)
+	}
+	group, err := s.modelDao.FindOne(ctx, &model.ModelFilter{Code: db.Eq(code)})
+	if db.IsDbError(err) {
+		return
+	}
+	err = nil
+	if group != nil {
+		return group.LbStrategy, nil
+	}
+	return model.LbStrategyWeighted, nil
+}
+
 func (s *Service) DeleteModels(ctx context.Context, req *model.DeleteModelsRequest) (err error) {
 	_, err = s.modelDao.Delete(ctx, &model.ModelFilter{IDs: db.In(req.Ids)})
 	return
@@ -110,13 +187,82 @@ func (s *Service) GetModelList(ctx context.Context, req *model.GetModelListReque
 	return
 }
 
-// ResolveModel 解析模型
-func (s *Service) ResolveModel(ctx context.Context, provider string, modelCode string) (info *model.ResolvedModel, err error) {
-	modelInfo, err := s.pickModel(ctx, provider, modelCode)
+// GetAvailableModelList 可用模型列表：已启用的模型和供应商、有生效中的价格，且在 API Key 权限范围内
+func (s *Service) GetAvailableModelList(ctx context.Context, scope *model.ApiKeyScope) (list []*model.AvailableModel, err error) {
+	models, err := s.modelDao.Find(ctx, &model.ModelFilter{Status: db.Eq(model.ModelStatusEnabled)}, db.WithOrder("priority", nil))
+	if err != nil {
+		return
+	}
+	providers, err := s.providerDao.Find(ctx, &model.ProviderFilter{Status: db.Eq(model.EnableStatusEnabled)})
+	if err != nil {
+		return
+	}
+	enabledProviders := lo.SliceToMap(providers, func(item *model.Provider) (int64, bool) {
+		return item.ID, true
+	})
+	now := time.Now()
+	pricings, err := s.modelPricingDao.Find(ctx, &model.ModelPricingFilter{
+		EffectiveFrom: db.Lte(now),
+		EffectiveTo:   db.Gt(now),
+	})
+	if err != nil {
+		return
+	}
+	priced := lo.SliceToMap(pricings, func(item *model.ModelPricing) (string, bool) {
+		return item.ProviderCode + "/" + item.ModelCode, true
+	})
+
+	// 同一 Code 取优先级最高的模型，保持列表顺序稳定
+	seen := make(map[string]bool)
+	for _, item := range models {
+		if seen[item.Code] || !enabledProviders[item.ProviderId] || !scope.AllowModel(item.Code) {
+			continue
+		}
+		if !priced[item.ProviderCode+"/"+item.GetActualCode()] {
+			continue
+		}
+		seen[item.Code] = true
+		list = append(list, &model.AvailableModel{
+			Code:            item.Code,
+			ActualCode:      item.GetActualCode(),
+			Name:            item.Name,
+			ProviderCode:    item.ProviderCode,
+			ContextWindow:   item.ContextWindow,
+			MaxOutputTokens: item.MaxOutputTokens,
+			CreatedAt:       item.CreatedAt,
+		})
+	}
+	return
+}
+
+// ResolveModel 解析模型，按优先级从高到低选择，当前优先级没有可用的模型或 API Key 时使用下一优先级
+func (s *Service) ResolveModel(ctx context.Context, req *model.ResolveModelRequest) (info *model.ResolvedModel, err error) {
+	list, err := s.findModels(ctx, req)
 	if err != nil {
 		return
 	}
-	log.Infof("picked model, provide: %s, model, %s", modelInfo.ProviderCode, modelInfo.Code)
+	var capacityErr error
+	for len(list) > 0 {
+		modelInfo := s.pickModel(ctx, list)
+		log.Infof("picked model, provide: %s, model, %s", modelInfo.ProviderCode, modelInfo.Code)
+		info, err = s.resolveModel(ctx, modelInfo, req.ExcludeApiKeyIds)
+		if err == nil {
+			return
+		}
+		log.Warnf("resolve model %d error, try next: %v", modelInfo.ID, err)
+		if errors.Is(err, model.ErrNoCapacity) {
+			capacityErr = err
+		}
+		list = lo.Without(list, modelInfo)
+	}
+	// 有模型只是暂时没有容量时，返回容量不足，客户端可以稍后重试
+	if capacityErr != nil {
+		err = capacityErr
+	}
+	return
+}
+
+func (s *Service) resolveModel(ctx context.Context, modelInfo *model.Model, excludeApiKeyIds []int64) (info *model.ResolvedModel, err error) {
 	providerInfo, err := s.providerDao.FindOneByID(ctx, modelInfo.ProviderId)
 	if err != nil {
 		return
@@ -125,7 +271,7 @@ func (s *Service) ResolveModel(ctx context.Context, provider string, modelCode s
 		err = fmt.Errorf("provider not enabled")
 		return
 	}
-	keyInfo, err := s.pickProviderApiKey(ctx, modelInfo.ProviderId)
+	keyInfo, err := s.pickProviderApiKey(ctx, modelInfo.ProviderId, excludeApiKeyIds, modelInfo.LbStrategy)
 	if err != nil {
 		return
 	}
@@ -140,6 +286,22 @@ func (s *Service) ResolveModel(ctx context.Context, provider string, modelCode s
 	if err != nil {
 		return
 	}
+	unitPriceRules, err := modelPrice.GetUnitPriceRules()
+	if err != nil {
+		return
+	}
+	retryPolicy, err := resolveRetryPolicy(providerInfo, modelInfo)
+	if err != nil {
+		return
+	}
+	pipeline, err := resolvePipeline(providerInfo, modelInfo)
+	if err != nil {
+		return
+	}
+	transport, err := resolveTransport(providerInfo.Transport)
+	if err != nil {
+		return
+	}
 	info = &model.ResolvedModel{
 		// 模型
 		ModelId:      modelInfo.ID,
@@ -147,7 +309,9 @@ func (s *Service) ResolveModel(ctx context.Context, provider string, modelCode s
 		ProviderId:   modelInfo.ProviderId,
 		ProviderCode: modelInfo.ProviderCode,
 		// 供应商
-		BaseUrl: providerInfo.BaseUrl,
+		BaseUrl:        providerInfo.BaseUrl,
+		ProviderConfig: providerInfo.Config,
+		Transport:      transport,
 		// 供应商ApiKey
 		ApiKeyId:        keyInfo.ID,
 		ApiKeyEncrypted: keyInfo.KeyEncrypted,
@@ -155,47 +319,91 @@ func (s *Service) ResolveModel(ctx context.Context, provider string, modelCode s
 		InputPrice:        modelPrice.InputPrice,
 		InputCachePrice:   modelPrice.InputCachePrice,
 		OutputPrice:       modelPrice.OutputPrice,
+		AudioInputPrice:   modelPrice.AudioInputPrice,
+		AudioOutputPrice:  modelPrice.AudioOutputPrice,
 		TokenNum:          modelPrice.TokenNum,
 		PointsPerCurrency: modelPrice.PointsPerCurrency,
+		PricingMode:       modelPrice.PricingMode,
+		UnitPrice:         modelPrice.UnitPrice,
+		UnitPriceRules:    unitPriceRules,
+		BatchDiscount:     modelPrice.BatchDiscount,
+		// 重试策略
+		RetryPolicy: retryPolicy,
+		Pipeline:    pipeline,
 	}
 	return
 }
 
-// pickModel 按照权重随机选择一个模型
-func (s *Service) pickModel(ctx context.Context, providerCode string, modelCode string) (info *model.Model, err error) {
+// resolveRetryPolicy 模型的重试策略覆盖供应商的重试策略
+func resolveRetryPolicy(providerInfo *model.Provider, modelInfo *model.Model) (policy core.RetryPolicy, err error) {
+	providerPolicy, err := core.ParseRetryPolicy(providerInfo.RetryPolicy)
+	if err != nil {
+		return
+	}
+	modelPolicy, err := core.ParseRetryPolicy(modelInfo.RetryPolicy)
+	if err != nil {
+		return
+	}
+	return policy.Merge(providerPolicy).Merge(modelPolicy), nil
+}
+
+// resolvePipeline 模型配置了流水线时替换供应商的流水线，不逐项合并
+func resolvePipeline(providerInfo *model.Provider, modelInfo *model.Model) (pipeline core.Pipeline, err error) {
+	p, err := core.ParsePipeline(modelInfo.Pipeline)
+	if err != nil || !p.IsZero() {
+		return lo.FromPtr(p), err
+	}
+	if p, err = core.ParsePipeline(providerInfo.Pipeline); err != nil {
+		return
+	}
+	return *p, nil
+}
+
+// findModels 按优先级排序的可用模型，不包含已排除的模型
+func (s *Service) findModels(ctx context.Context, req *model.ResolveModelRequest) (list []*model.Model, err error) {
 	f := &model.ModelFilter{
-		ProviderCode: db.Eq(providerCode, db.OmitIfZero[string]()),
-		Code:         db.Eq(modelCode, db.OmitIfZero[string]()),
+		IDs:          db.NotIn(req.ExcludeModelIds, db.OmitIfZero[[]int64]()),
+		ProviderCode: db.Eq(req.ProviderCode, db.OmitIfZero[string]()),
+		Code:         db.Eq(req.ModelCode, db.OmitIfZero[string]()),
 		Status:       db.Eq(model.ModelStatusEnabled),
 	}
-	var list []*model.Model
 	list, err = s.modelDao.Find(ctx, f, db.WithOrder("priority", nil))
 	if err != nil {
 		return
 	}
 	if len(list) == 0 {
-		err = fmt.Errorf("model not found，provider: %s, model: %s", providerCode, modelCode)
-		return
+		err = fmt.Errorf("model not found，provider: %s, model: %s", req.ProviderCode, req.ModelCode)
 	}
+	return
+}
+
+// pickModel 在最高优先级的模型中按模型组的负载均衡策略选择一个，list 已按优先级排序
+func (s *Service) pickModel(ctx context.Context, list []*model.Model) *model.Model {
 	if len(list) == 1 {
-		return list[0], nil
+		return list[0]
 	}
 	minPriority := list[0].Priority
-	modelList := lo.FlatMap(list, func(info *model.Model, index int) []*model.Model {
-		if info.Priority == minPriority {
-			return []*model.Model{info}
-		} else {
-			return []*model.Model{}
-		}
+	modelList := lo.Filter(list, func(info *model.Model, _ int) bool {
+		return info.Priority == minPriority
 	})
-	return utils.PickByWeight(modelList), nil
+	return pickBalanced(ctx, s, groupLbStrategy(list), balanceKindModel, modelList, func(info *model.Model) int64 {
+		return info.ID
+	})
+}
+
+// groupLbStrategy 模型组的负载均衡策略，同一模型代码的模型共用，以优先级最高的模型为准
+func groupLbStrategy(list []*model.Model) model.LbStrategy {
+	return lo.CoalesceOrEmpty(lo.Map(list, func(info *model.Model, _ int) model.LbStrategy {
+		return info.LbStrategy
+	})...)
 }
 
-// pickProviderApiKey 按照权重随机选择一个API Key
-func (s *Service) pickProviderApiKey(ctx context.Context, providerId int64) (keyInfo *model.ProviderApiKey, err error) {
+// pickProviderApiKey 按模型的负载均衡策略选择一个未达到限流和额度上限的API Key，冷却到期的 Key 优先放行一个探测请求
+func (s *Service) pickProviderApiKey(ctx context.Context, providerId int64, excludeIds []int64, strategy model.LbStrategy) (keyInfo *model.ProviderApiKey, err error) {
 	f := &model.ProviderApiKeyFilter{
+		IDs:        db.NotIn(excludeIds, db.OmitIfZero[[]int64]()),
 		ProviderId: db.Eq(providerId),
-		Status:     db.Eq(model.ApiKeyStatusEnabled),
+		Statuses:   db.In([]model.ApiKeyStatus{model.ApiKeyStatusEnabled, model.ApiKeyStatusCooldown}),
 	}
 	var list []*model.ProviderApiKey
 	list, err = s.providerApiKeyDao.Find(ctx, f)
@@ -206,5 +414,24 @@ func (s *Service) pickProviderApiKey(ctx context.Context, providerId int64) (key
 		err = fmt.Errorf("provider api key not found，provider: %d", providerId)
 		return
 	}
-	return utils.PickByWeight(list), nil
+	enabled, cooling := lo.FilterReject(s.filterProviderApiKeyQuota(ctx, list), func(item *model.ProviderApiKey, _ int) bool {
+		return item.Status == model.ApiKeyStatusEnabled
+	})
+	now := time.Now()
+	for _, item := range cooling {
+		if item.CoolDownUntil != nil && !item.CoolDownUntil.After(now) && s.tryProbeProviderApiKey(ctx, item.ID) {
+			return item, nil
+		}
+	}
+	for len(enabled) > 0 {
+		item := pickBalanced(ctx, s, strategy, balanceKindProviderApiKey, enabled, func(info *model.ProviderApiKey) int64 {
+			return info.ID
+		})
+		if s.allowProviderApiKeyRate(ctx, item) {
+			return item, nil
+		}
+		enabled = lo.Without(enabled, item)
+	}
+	err = fmt.Errorf("%w, provider: %d", model.ErrNoCapacity, providerId)
+	return
 }
@@ -2,15 +2,22 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/samber/lo"
 
 	"github.com/modelgate/modelgate/internal/relay/model"
+	"github.com/modelgate/modelgate/internal/runtime/core"
 	"github.com/modelgate/modelgate/pkg/db"
 )
 
 func (s *Service) CreateModelPricing(ctx context.Context, req *model.CreateModelPricingRequest) (info *model.ModelPricing, err error) {
+	rules, err := normalizeUnitPriceRules(req.ModelPricing.UnitPriceRules)
+	if err != nil {
+		return
+	}
 	info = &model.ModelPricing{
 		ProviderCode:      req.ModelPricing.ProviderCode,
 		ModelCode:         req.ModelPricing.ModelCode,
@@ -20,9 +27,15 @@ func (s *Service) CreateModelPricing(ctx context.Context, req *model.CreateModel
 		InputPrice:        float64(req.ModelPricing.InputPrice),
 		InputCachePrice:   float64(req.ModelPricing.InputCachePrice),
 		OutputPrice:       float64(req.ModelPricing.OutputPrice),
+		AudioInputPrice:   float64(req.ModelPricing.AudioInputPrice),
+		AudioOutputPrice:  float64(req.ModelPricing.AudioOutputPrice),
 		Status:            model.EnableStatus(req.ModelPricing.Status),
 		EffectiveFrom:     req.ModelPricing.EffectiveFrom.AsTime(),
 		EffectiveTo:       req.ModelPricing.EffectiveTo.AsTime(),
+		PricingMode:       lo.Ternary(req.ModelPricing.PricingMode != "", model.PricingMode(req.ModelPricing.PricingMode), model.PricingModeToken),
+		UnitPrice:         float64(req.ModelPricing.UnitPrice),
+		UnitPriceRules:    rules,
+		BatchDiscount:     float64(req.ModelPricing.BatchDiscount),
 	}
 	err = s.modelPricingDao.Create(ctx, info)
 	return
@@ -58,6 +71,12 @@ func (s *Service) UpdateModelPricing(ctx context.Context, req *model.UpdateModel
 	if lo.Contains(req.UpdateMask, "output_price") {
 		update["output_price"] = req.ModelPricing.OutputPrice
 	}
+	if lo.Contains(req.UpdateMask, "audio_input_price") {
+		update["audio_input_price"] = req.ModelPricing.AudioInputPrice
+	}
+	if lo.Contains(req.UpdateMask, "audio_output_price") {
+		update["audio_output_price"] = req.ModelPricing.AudioOutputPrice
+	}
 	if lo.Contains(req.UpdateMask, "status") {
 		update["status"] = req.ModelPricing.Status
 	}
@@ -67,6 +86,22 @@ func (s *Service) UpdateModelPricing(ctx context.Context, req *model.UpdateModel
 	if lo.Contains(req.UpdateMask, "effective_to") {
 		update["effective_to"] = req.ModelPricing.EffectiveTo.AsTime()
 	}
+	if lo.Contains(req.UpdateMask, "pricing_mode") {
+		update["pricing_mode"] = req.ModelPricing.PricingMode
+	}
+	if lo.Contains(req.UpdateMask, "unit_price") {
+		update["unit_price"] = req.ModelPricing.UnitPrice
+	}
+	if lo.Contains(req.UpdateMask, "batch_discount") {
+		update["batch_discount"] = req.ModelPricing.BatchDiscount
+	}
+	if lo.Contains(req.UpdateMask, "unit_price_rules") {
+		var rules string
+		if rules, err = normalizeUnitPriceRules(req.ModelPricing.UnitPriceRules); err != nil {
+			return
+		}
+		update["unit_price_rules"] = rules
+	}
 	if len(update) == 0 {
 		err = fmt.Errorf("no fields to update")
 		return
@@ -105,3 +140,16 @@ func (s *Service) GetModelPricingList(ctx context.Context, req *model.GetModelPr
 	list, err = s.modelPricingDao.Find(ctx, f, options...)
 	return
 }
+
+// normalizeUnitPriceRules 单价规则为 JSON 数组，如 [{"size": "1024x1024", "quality": "hd", "price": 0.08}]
+func normalizeUnitPriceRules(rules string) (string, error) {
+	rules = strings.TrimSpace(rules)
+	if rules == "" {
+		return "[]", nil
+	}
+	var list []core.UnitPriceRule
+	if err := json.Unmarshal([]byte(rules), &list); err != nil {
+		return "", fmt.Errorf("invalid unit price rules, must be json array: %v", err)
+	}
+	return rules, nil
+}
@@ -2,12 +2,17 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/samber/lo"
 
+	"github.com/modelgate/modelgate/internal/config"
 	"github.com/modelgate/modelgate/internal/relay/model"
+	"github.com/modelgate/modelgate/internal/runtime/core"
 	"github.com/modelgate/modelgate/pkg/db"
+	"github.com/modelgate/modelgate/pkg/utils"
 )
 
 func (s *Service) CreateProvider(ctx context.Context, req *model.CreateProviderRequest) (info *model.Provider, err error) {
@@ -19,11 +24,31 @@ func (s *Service) CreateProvider(ctx context.Context, req *model.CreateProviderR
 		err = fmt.Errorf("provider already exists, code: %s", req.Provider.Code)
 		return
 	}
+	config, err := normalizeProviderConfig(req.Provider.Config)
+	if err != nil {
+		return
+	}
+	retryPolicy, err := normalizeRetryPolicy(req.Provider.RetryPolicy)
+	if err != nil {
+		return
+	}
+	transport, err := normalizeTransport(req.Provider.Transport, "")
+	if err != nil {
+		return
+	}
+	pipeline, err := normalizePipeline(req.Provider.Pipeline)
+	if err != nil {
+		return
+	}
 	info = &model.Provider{
-		Name:    req.Provider.Name,
-		Code:    req.Provider.Code,
-		BaseUrl: req.Provider.BaseUrl,
-		Status:  model.EnableStatus(req.Provider.Status),
+		Name:        req.Provider.Name,
+		Code:        req.Provider.Code,
+		BaseUrl:     req.Provider.BaseUrl,
+		Status:      model.EnableStatus(req.Provider.Status),
+		Config:      config,
+		RetryPolicy: retryPolicy,
+		Transport:   transport,
+		Pipeline:    pipeline,
 	}
 	err = s.providerDao.Create(ctx, info)
 	return
@@ -52,6 +77,35 @@ func (s *Service) UpdateProvider(ctx context.Context, req *model.UpdateProviderR
 	if lo.Contains(req.UpdateMask, "status") {
 		update["status"] = req.Provider.Status
 	}
+	if lo.Contains(req.UpdateMask, "config") {
+		var config string
+		if config, err = normalizeProviderConfig(req.Provider.Config); err != nil {
+			return
+		}
+		update["config"] = config
+	}
+	if lo.Contains(req.UpdateMask, "retry_policy") {
+		var retryPolicy string
+		if retryPolicy, err = normalizeRetryPolicy(req.Provider.RetryPolicy); err != nil {
+			return
+		}
+		update["retry_policy"] = retryPolicy
+	}
+	// 运行时发现配置变化后替换客户端，不需要重启
+	if lo.Contains(req.UpdateMask, "transport") {
+		var transport string
+		if transport, err = normalizeTransport(req.Provider.Transport, info.Transport); err != nil {
+			return
+		}
+		update["transport"] = transport
+	}
+	if lo.Contains(req.UpdateMask, "pipeline") {
+		var pipeline string
+		if pipeline, err = normalizePipeline(req.Provider.Pipeline); err != nil {
+			return
+		}
+		update["pipeline"] = pipeline
+	}
 	if len(update) == 0 {
 		err = fmt.Errorf("no fields to update")
 		return
@@ -100,3 +154,106 @@ func (s *Service) GetProviderList(ctx context.Context, req *model.GetProviderLis
 	list, err = s.providerDao.Find(ctx, f, options...)
 	return
 }
+
+// normalizeProviderConfig 校验供应商扩展配置，空值存为空对象
+func normalizeProviderConfig(config string) (string, error) {
+	config = strings.TrimSpace(config)
+	if config == "" {
+		return "{}", nil
+	}
+	var m map[string]any
+	if err := json.Unmarshal([]byte(config), &m); err != nil || m == nil {
+		return "", fmt.Errorf("invalid provider config, must be json object")
+	}
+	return config, nil
+}
+
+// normalizeRetryPolicy 校验重试策略，空值存为空对象
+func normalizeRetryPolicy(policy string) (string, error) {
+	policy = strings.TrimSpace(policy)
+	if policy == "" {
+		return "{}", nil
+	}
+	if _, err := core.ParseRetryPolicy(policy); err != nil {
+		return "", fmt.Errorf("invalid retry policy: %v", err)
+	}
+	return policy, nil
+}
+
+// normalizeTransport 校验传输配置能创建客户端，客户端私钥加密保存，空值存为空对象
+// 私钥为掩码时沿用 stored 中已保存的私钥
+func normalizeTransport(transport, stored string) (string, error) {
+	transport = strings.TrimSpace(transport)
+	if transport == "" {
+		return "{}", nil
+	}
+	cfg, err := core.ParseTransportConfig(transport)
+	if err == nil && cfg.ClientKey == core.MaskedClientKey {
+		var old *core.TransportConfig
+		if old, err = core.ParseTransportConfig(stored); err == nil {
+			err = decryptClientKey(old)
+			cfg.ClientKey = old.ClientKey
+		}
+	}
+	if err == nil {
+		_, err = cfg.NewClient()
+	}
+	if err != nil {
+		return "", fmt.Errorf("invalid transport: %v", err)
+	}
+	if cfg.ClientKey == "" && cfg.ClientKeyEncrypted == "" {
+		return transport, nil
+	}
+	// 不接受直接提交的密文
+	cfg.ClientKeyEncrypted = ""
+	if cfg.ClientKey != "" {
+		if cfg.ClientKeyEncrypted, err = utils.EncryptAESGCM([]byte(cfg.ClientKey), []byte(config.GetConfig().Secret.Key)); err != nil {
+			return "", err
+		}
+	}
+	cfg.ClientKey = ""
+	data, err := json.Marshal(cfg)
+	return string(data), err
+}
+
+// decryptClientKey 解密客户端私钥到 ClientKey
+func decryptClientKey(cfg *core.TransportConfig) error {
+	if cfg.ClientKeyEncrypted == "" {
+		return nil
+	}
+	key, err := utils.DecryptAESGCM(cfg.ClientKeyEncrypted, []byte(config.GetConfig().Secret.Key))
+	if err != nil {
+		return fmt.Errorf("decrypt client key error: %v", err)
+	}
+	cfg.ClientKey, cfg.ClientKeyEncrypted = string(key), ""
+	return nil
+}
+
+// resolveTransport 执行请求使用的传输配置，解密客户端私钥
+func resolveTransport(transport string) (string, error) {
+	cfg, err := core.ParseTransportConfig(transport)
+	if err != nil || cfg.ClientKeyEncrypted == "" {
+		return transport, nil
+	}
+	if err = decryptClientKey(cfg); err != nil {
+		return "", err
+	}
+	data, err := json.Marshal(cfg)
+	return string(data), err
+}
+
+// normalizePipeline 校验流水线引用的 hook 已注册，空值存为空对象
+func normalizePipeline(pipeline string) (string, error) {
+	pipeline = strings.TrimSpace(pipeline)
+	if pipeline == "" {
+		return "{}", nil
+	}
+	p, err := core.ParsePipeline(pipeline)
+	if err == nil {
+		err = core.ExecutorRegistry.ValidatePipeline(*p)
+	}
+	if err != nil {
+		return "", fmt.Errorf("invalid pipeline: %v", err)
+	}
+	return pipeline, nil
+}
@@ -42,6 +42,8 @@ func (s *Service) CreateProviderApiKey(ctx context.Context, req *model.CreatePro
 		KeyEncrypted: keyEncrypted,
 		Weight:       int(req.ProviderApiKey.Weight),
 		Status:       model.ApiKeyStatus(req.ProviderApiKey.Status),
+		QuoteLimit:   lo.EmptyableToPtr(req.ProviderApiKey.QuoteLimit),
+		RateLimit:    lo.EmptyableToPtr(int(req.ProviderApiKey.RateLimit)),
 	}
 	err = s.providerApiKeyDao.Create(ctx, info)
 	return
@@ -93,14 +95,31 @@ func (s *Service) UpdateProviderApiKey(ctx context.Context, req *model.UpdatePro
 	if lo.Contains(req.UpdateMask, "weight") {
 		update["weight"] = req.ProviderApiKey.Weight
 	}
-	if lo.Contains(req.UpdateMask, "status") {
+	// 0 表示不限
+	if lo.Contains(req.UpdateMask, "quote_limit") {
+		update["quote_limit"] = lo.EmptyableToPtr(req.ProviderApiKey.QuoteLimit)
+	}
+	if lo.Contains(req.UpdateMask, "rate_limit") {
+		update["rate_limit"] = lo.EmptyableToPtr(req.ProviderApiKey.RateLimit)
+	}
+	// 状态变化时清除熔断和自动禁用记录
+	if lo.Contains(req.UpdateMask, "status") && req.ProviderApiKey.Status != string(info.Status) {
 		update["status"] = req.ProviderApiKey.Status
+		update["faild_count"] = 0
+		update["cool_down_until"] = nil
+		update["disabled_reason"] = ""
 	}
 	if len(update) == 0 {
 		err = fmt.Errorf("no fields to update")
 		return
 	}
 	err = s.providerApiKeyDao.UpdateOne(ctx, info, update)
+	if err != nil {
+		return
+	}
+	if _, ok := update["status"]; ok {
+		err = s.resetProviderApiKeyBreaker(ctx, info.ID)
+	}
 	return
 }
 
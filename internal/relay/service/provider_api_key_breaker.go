@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/modelgate/modelgate/internal/relay/model"
+	"github.com/modelgate/modelgate/pkg/db"
+)
+
+const (
+	breakerFailureThreshold = 5                // 连续失败次数达到后熔断
+	breakerBaseCooldown     = 30 * time.Second // 首次冷却时间，之后每次熔断翻倍
+	breakerMaxCooldown      = 30 * time.Minute
+	breakerProbeTimeout     = time.Minute // 半开探测锁的过期时间，避免探测请求丢失后一直无法恢复
+	breakerStateTTL         = 24 * time.Hour
+)
+
+// breakerFailureScript 记录一次失败，达到阈值、429 或半开探测失败时熔断
+// 返回 {连续失败次数, 冷却结束时间(毫秒)}，未发生熔断时冷却结束时间为 0
+var breakerFailureScript = redis.NewScript(`
+	local key = KEYS[1]
+	local now = tonumber(ARGV[1])
+	local threshold = tonumber(ARGV[2])
+	local cooldown = tonumber(ARGV[3])
+	local max_cooldown = tonumber(ARGV[4])
+	local force = ARGV[5] == '1'
+
+	local fails = redis.call('HINCRBY', key, 'fails', 1)
+	local opens = tonumber(redis.call('HGET', key, 'opens') or '0')
+	local until_ms = tonumber(redis.call('HGET', key, 'until') or '0')
+	redis.call('PEXPIRE', key, tonumber(ARGV[6]))
+
+	-- 冷却中，熔断前发出的请求返回失败不再延长
+	if until_ms > now then
+		return {fails, 0}
+	end
+	if opens == 0 and fails < threshold and not force then
+		return {fails, 0}
+	end
+	for i = 1, opens do
+		cooldown = cooldown * 2
+		if cooldown >= max_cooldown then
+			break
+		end
+	end
+	until_ms = now + math.min(cooldown, max_cooldown)
+	redis.call('HSET', key, 'opens', opens + 1, 'until', until_ms)
+	redis.call('DEL', KEYS[2])
+	return {fails, until_ms}
+`)
+
+// breakerSuccessScript 记录一次成功并清除熔断状态，返回之前的熔断次数
+// 冷却中返回 -1，熔断前发出的请求成功不能提前恢复
+var breakerSuccessScript = redis.NewScript(`
+	local until_ms = tonumber(redis.call('HGET', KEYS[1], 'until') or '0')
+	if until_ms > tonumber(ARGV[1]) then
+		return -1
+	end
+	local opens = tonumber(redis.call('HGET', KEYS[1], 'opens') or '0')
+	redis.call('DEL', KEYS[1], KEYS[2])
+	return opens
+`)
+
+func breakerKeys(apiKeyId int64) []string {
+	id := strconv.FormatInt(apiKeyId, 10)
+	return []string{model.ProviderApiKeyBreakerPrefix + id, model.ProviderApiKeyProbePrefix + id}
+}
+
+// ReportProviderApiKeyResult 记录供应商 API Key 的调用结果，状态保存在 Redis 中由所有实例共享
+// 熔断和恢复时同步更新数据库中的状态，供选择 Key 和后台展示使用
+func (s *Service) ReportProviderApiKeyResult(ctx context.Context, req *model.ReportProviderApiKeyResultRequest) (err error) {
+	if req.Rejected != "" {
+		return s.disableProviderApiKey(ctx, req.ApiKeyId, req.Rejected)
+	}
+	keys := breakerKeys(req.ApiKeyId)
+	now := time.Now()
+	if req.Success {
+		var opens int64
+		opens, err = breakerSuccessScript.Run(ctx, s.redisClient, keys, now.UnixMilli()).Int64()
+		if err != nil || opens <= 0 {
+			return
+		}
+		log.Infof("provider api key %d recovered from cooldown", req.ApiKeyId)
+		_, err = s.providerApiKeyDao.Update(ctx, &model.ProviderApiKeyFilter{
+			ID:     db.Eq(req.ApiKeyId),
+			Status: db.Eq(model.ApiKeyStatusCooldown),
+		}, map[string]any{
+			"status":          model.ApiKeyStatusEnabled,
+			"faild_count":     0,
+			"cool_down_until": nil,
+		})
+		return
+	}
+
+	result, err := breakerFailureScript.Run(ctx, s.redisClient, keys,
+		now.UnixMilli(),
+		breakerFailureThreshold,
+		breakerBaseCooldown.Milliseconds(),
+		breakerMaxCooldown.Milliseconds(),
+		req.RateLimited,
+		(breakerMaxCooldown + breakerStateTTL).Milliseconds(),
+	).Int64Slice()
+	if err != nil || len(result) != 2 || result[1] == 0 {
+		return
+	}
+	fails, until := result[0], time.UnixMilli(result[1])
+	log.Warnf("provider api key %d cooldown until %s, failed count: %d", req.ApiKeyId, until.Format(time.DateTime), fails)
+	// 管理员手动禁用的 Key 保持禁用
+	_, err = s.providerApiKeyDao.Update(ctx, &model.ProviderApiKeyFilter{
+		ID:     db.Eq(req.ApiKeyId),
+		Status: db.NotEq(model.ApiKeyStatusDisabled),
+	}, map[string]any{
+		"status":          model.ApiKeyStatusCooldown,
+		"faild_count":     fails,
+		"cool_down_until": until,
+	})
+	return
+}
+
+// tryProbeProviderApiKey 冷却结束后只放行一个请求探测，成功则恢复，失败则重新冷却
+func (s *Service) tryProbeProviderApiKey(ctx context.Context, apiKeyId int64) bool {
+	ok, err := s.redisClient.SetNX(ctx, breakerKeys(apiKeyId)[1], 1, breakerProbeTimeout).Result()
+	if err != nil {
+		log.Errorf("acquire provider api key %d probe lock error: %v", apiKeyId, err)
+		return false
+	}
+	return ok
+}
+
+// resetProviderApiKeyBreaker 管理员修改状态后清除熔断状态
+func (s *Service) resetProviderApiKeyBreaker(ctx context.Context, apiKeyId int64) error {
+	return s.redisClient.Del(ctx, breakerKeys(apiKeyId)...).Err()
+}
+
+// disableProviderApiKey 上游拒绝该 Key 时禁用并通知，多个请求同时失败时只有一个会更新成功
+func (s *Service) disableProviderApiKey(ctx context.Context, apiKeyId int64, reason string) (err error) {
+	rows, err := s.providerApiKeyDao.Update(ctx, &model.ProviderApiKeyFilter{
+		ID:     db.Eq(apiKeyId),
+		Status: db.NotEq(model.ApiKeyStatusDisabled),
+	}, map[string]any{
+		"status":          model.ApiKeyStatusDisabled,
+		"disabled_reason": reason,
+		"cool_down_until": nil,
+	})
+	if err != nil || rows == 0 {
+		return
+	}
+	if err = s.resetProviderApiKeyBreaker(ctx, apiKeyId); err != nil {
+		log.Errorf("reset provider api key %d breaker error: %v", apiKeyId, err)
+	}
+	info, err := s.providerApiKeyDao.FindOneByID(ctx, apiKeyId)
+	if err != nil {
+		return
+	}
+	s.sendEvent(&model.Event{
+		Type:    model.EventProviderApiKeyDisabled,
+		Message: fmt.Sprintf("provider api key %s (%s) disabled: %s", info.Name, info.ProviderCode, reason),
+		Data: map[string]any{
+			"provider_api_key_id": info.ID,
+			"provider_code":       info.ProviderCode,
+			"name":                info.Name,
+			"key":                 fmt.Sprintf("%s...%s", info.KeyPrefix, info.KeySuffix),
+			"reason":              reason,
+		},
+	})
+	return
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/samber/lo"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/modelgate/modelgate/internal/relay/model"
+)
+
+// rateLimitScript 按秒计数，未超过限制时计数加一并返回 1
+var rateLimitScript = redis.NewScript(`
+	local count = tonumber(redis.call('GET', KEYS[1]) or '0')
+	if count >= tonumber(ARGV[1]) then
+		return 0
+	end
+	redis.call('INCR', KEYS[1])
+	redis.call('EXPIRE', KEYS[1], 2)
+	return 1
+`)
+
+// allowProviderApiKeyRate 所有实例共享每秒请求数，Redis 不可用时不限流
+func (s *Service) allowProviderApiKeyRate(ctx context.Context, info *model.ProviderApiKey) bool {
+	limit := lo.FromPtr(info.RateLimit)
+	if limit <= 0 {
+		return true
+	}
+	key := fmt.Sprintf("%s%d:%d", model.ProviderApiKeyRatePrefix, info.ID, time.Now().Unix())
+	ok, err := rateLimitScript.Run(ctx, s.redisClient, []string{key}, limit).Bool()
+	if err != nil {
+		log.Errorf("provider api key %d rate limit error: %v", info.ID, err)
+		return true
+	}
+	return ok
+}
+
+// filterProviderApiKeyQuota 去掉额度用完的 Key，已用额度包括缓存中尚未写入数据库的部分
+func (s *Service) filterProviderApiKeyQuota(ctx context.Context, list []*model.ProviderApiKey) []*model.ProviderApiKey {
+	limited := lo.Filter(list, func(item *model.ProviderApiKey, _ int) bool {
+		return lo.FromPtr(item.QuoteLimit) > 0
+	})
+	if len(limited) == 0 {
+		return list
+	}
+	keys := lo.Map(limited, func(item *model.ProviderApiKey, _ int) string {
+		return fmt.Sprintf("%s%d:%s", model.UsageProviderApiKeyPrefix, item.ID, model.MetricUsage)
+	})
+	cached := make(map[int64]int64, len(limited))
+	values, err := s.redisClient.MGet(ctx, keys...).Result()
+	if err != nil {
+		log.Errorf("get provider api key cached usage error: %v", err)
+	}
+	for i, v := range values {
+		if str, ok := v.(string); ok {
+			cached[limited[i].ID], _ = strconv.ParseInt(str, 10, 64)
+		}
+	}
+	return lo.Filter(list, func(item *model.ProviderApiKey, _ int) bool {
+		limit := lo.FromPtr(item.QuoteLimit)
+		return limit <= 0 || item.QuoteUsed+cached[item.ID] < limit
+	})
+}
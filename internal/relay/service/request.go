@@ -8,20 +8,31 @@ import (
 	"github.com/modelgate/modelgate/pkg/db"
 )
 
+// CreateRequest 第一次尝试创建请求记录，之后的尝试更新请求记录为当前的供应商和模型，每次尝试单独记录
 func (s *Service) CreateRequest(ctx context.Context, req *model.CreateRequestRequest) (m *model.Request, err error) {
-	request := model.Request{
-		RequestUUID:      req.RequestUUID,
-		AccountId:        req.AccountId,
-		AccountApiKeyId:  req.AccountApiKeyId,
-		ProviderId:       req.ProviderId,
-		ProviderApiKeyId: req.ProviderApiKeyId,
-		ModelId:          req.ModelId,
-		PromptTokens:     0,
-		CompletionTokens: 0,
-		TotalTokens:      0,
-		Status:           model.RequestStatusPending,
+	switch {
+	case req.AttemptNo > 1 && req.AttemptOnly:
+		// 对冲请求的尝试不修改请求，结束时以胜出方为准
+	case req.AttemptNo > 1:
+		_, err = s.requestDao.Update(ctx, &model.RequestFilter{RequestUUID: db.Eq(req.RequestUUID)}, map[string]any{
+			"provider_id":         req.ProviderId,
+			"provider_api_key_id": req.ProviderApiKeyId,
+			"model_id":            req.ModelId,
+		})
+	default:
+		err = s.requestDao.Create(ctx, &model.Request{
+			RequestUUID:      req.RequestUUID,
+			AccountId:        req.AccountId,
+			AccountApiKeyId:  req.AccountApiKeyId,
+			ProviderId:       req.ProviderId,
+			ProviderApiKeyId: req.ProviderApiKeyId,
+			ModelId:          req.ModelId,
+			PromptTokens:     0,
+			CompletionTokens: 0,
+			TotalTokens:      0,
+			Status:           model.RequestStatusPending,
+		})
 	}
-	err = s.requestDao.Create(ctx, &request)
 	if err != nil {
 		return
 	}
@@ -42,7 +53,7 @@ func (s *Service) CreateRequest(ctx context.Context, req *model.CreateRequestReq
 	if err != nil {
 		return
 	}
-	// 统计
+	// 统计，每次尝试计入对应供应商
 	s.AddRequestUsage(ctx, req.ProviderCode, model.MetricTotal, 1)
 	return
 }
@@ -60,18 +71,25 @@ func (s *Service) UpdateRequestCompleted(ctx context.Context, req *model.UpdateR
 	if req.ActualModel != "" {
 		update["actual_model"] = req.ActualModel
 	}
-	_, err = s.requestDao.Update(ctx, &model.RequestFilter{RequestUUID: db.Eq(req.RequestUUID)}, update)
-	if err != nil {
-		return
-	}
 	_, err = s.requestAttemptDao.Update(ctx, &model.RequestAttemptFilter{RequestUUID: db.Eq(req.RequestUUID), AttemptNo: db.Eq(req.AttemptNo)}, update)
 	if err != nil {
 		return
 	}
-	// 统计厂商请求成功、失败数量
-	if req.Status == model.RequestStatusSuccess {
+	if !req.AttemptOnly {
+		// 请求的模型以结束的尝试为准，对冲请求中被取消的一方不修改
+		update["provider_id"] = req.ProviderId
+		update["provider_api_key_id"] = req.ProviderApiKeyId
+		update["model_id"] = req.ModelId
+		_, err = s.requestDao.Update(ctx, &model.RequestFilter{RequestUUID: db.Eq(req.RequestUUID)}, update)
+		if err != nil {
+			return
+		}
+	}
+	// 统计厂商请求成功、失败数量，被取消的不计入
+	switch req.Status {
+	case model.RequestStatusSuccess:
 		s.AddRequestUsage(ctx, req.ProviderCode, model.MetricSuccess, 1)
-	} else {
+	case model.RequestStatusFailed:
 		s.AddRequestUsage(ctx, req.ProviderCode, model.MetricFailed, 1)
 	}
 	return
@@ -26,6 +26,9 @@ type Service struct {
 	ledgerDao           relay.LedgerDAO
 	relayUsageDao       relay.RelayUsageDAO
 	relayHourlyUsageDao relay.RelayHourlyUsageDAO
+	fileDao             relay.FileDAO
+	batchDao            relay.BatchDAO
+	storage             fileStorage
 	redisClient         *redis.Client
 }
 
@@ -42,6 +45,9 @@ func New(i do.Injector) (relay.Service, error) {
 		ledgerDao:           do.MustInvoke[relay.LedgerDAO](i),
 		relayUsageDao:       do.MustInvoke[relay.RelayUsageDAO](i),
 		relayHourlyUsageDao: do.MustInvoke[relay.RelayHourlyUsageDAO](i),
+		fileDao:             do.MustInvoke[relay.FileDAO](i),
+		batchDao:            do.MustInvoke[relay.BatchDAO](i),
+		storage:             newFileStorage(do.MustInvoke[relay.FileChunkDAO](i)),
 		redisClient:         do.MustInvoke[*redis.Client](i),
 	}, nil
 }
@@ -60,4 +66,9 @@ func Init(i do.Injector) {
 	leader.Run(context.Background(), func(leaderCtx context.Context) {
 		s.StartWorker(leaderCtx)
 	})
+	// 批量任务单独选主，避免与统计任务互相阻塞
+	batchLeader := utils.NewLeaderElector(redisClient, instanceID, model.WorkerKeyBatchLeader, time.Minute)
+	batchLeader.Run(context.Background(), func(leaderCtx context.Context) {
+		s.StartBatchWorker(leaderCtx)
+	})
 }
@@ -0,0 +1,203 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/samber/lo"
+
+	"github.com/modelgate/modelgate/internal/config"
+	"github.com/modelgate/modelgate/internal/relay"
+	"github.com/modelgate/modelgate/internal/relay/model"
+	"github.com/modelgate/modelgate/pkg/db"
+)
+
+const (
+	// defaultFileDir 默认文件存储目录
+	defaultFileDir = "data/files"
+	// fileStorageDB 文件保存在数据库中，多实例共享
+	fileStorageDB = "db"
+	// fileChunkSize 数据库存储的分块大小
+	fileChunkSize = 4 << 20
+)
+
+// fileStorage 文件内容存储，上传文件和批量任务结果都保存在这里
+// 多实例部署时任意实例都可能读写同一个文件，需要使用数据库存储或共享目录
+type fileStorage interface {
+	// Create 写入文件，已存在时覆盖
+	Create(ctx context.Context, path string, r io.Reader) (size int64, err error)
+	// Append 追加内容，文件不存在时创建
+	Append(ctx context.Context, path string, data []byte) error
+	// Open 打开文件，调用方负责关闭，文件不存在时返回 fs.ErrNotExist
+	Open(ctx context.Context, path string) (io.ReadCloser, error)
+	// Size 文件大小，文件不存在时返回 fs.ErrNotExist
+	Size(ctx context.Context, path string) (int64, error)
+	// Remove 删除文件，文件不存在时忽略
+	Remove(ctx context.Context, path string) error
+}
+
+// newFileStorage 按配置选择文件存储，默认使用本地目录
+func newFileStorage(fileChunkDao relay.FileChunkDAO) fileStorage {
+	if config.GetConfig().Batch.Storage == fileStorageDB {
+		return &dbStorage{dao: fileChunkDao}
+	}
+	return &localStorage{}
+}
+
+// localStorage 保存在本地目录，多实例部署时目录需要挂载为共享存储
+type localStorage struct{}
+
+func (localStorage) Create(ctx context.Context, path string, r io.Reader) (size int64, err error) {
+	p := storagePath(path)
+	if err = os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return
+	}
+	f, err := os.Create(p)
+	if err != nil {
+		return
+	}
+	if size, err = io.Copy(f, r); err != nil {
+		_ = f.Close()
+		_ = os.Remove(p)
+		return
+	}
+	err = f.Close()
+	return
+}
+
+func (localStorage) Append(ctx context.Context, path string, data []byte) (err error) {
+	p := storagePath(path)
+	if err = os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return
+	}
+	f, err := os.OpenFile(p, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	_, err = f.Write(data)
+	return
+}
+
+func (localStorage) Open(ctx context.Context, path string) (io.ReadCloser, error) {
+	return os.Open(storagePath(path))
+}
+
+func (localStorage) Size(ctx context.Context, path string) (int64, error) {
+	stat, err := os.Stat(storagePath(path))
+	if err != nil {
+		return 0, err
+	}
+	return stat.Size(), nil
+}
+
+func (localStorage) Remove(ctx context.Context, path string) error {
+	if err := os.Remove(storagePath(path)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// storagePath 文件在磁盘上的绝对路径
+func storagePath(relPath string) string {
+	dir := lo.CoalesceOrEmpty(config.GetConfig().Batch.FileDir, defaultFileDir)
+	if !filepath.IsAbs(dir) {
+		dir = config.GetPath(dir)
+	}
+	return filepath.Join(dir, relPath)
+}
+
+// dbStorage 按分块保存在数据库中，所有实例共享
+type dbStorage struct {
+	dao relay.FileChunkDAO
+}
+
+func (s *dbStorage) Create(ctx context.Context, path string, r io.Reader) (size int64, err error) {
+	if err = s.Remove(ctx, path); err != nil {
+		return
+	}
+	buf := make([]byte, fileChunkSize)
+	for seq := int64(0); ; seq++ {
+		n, rErr := io.ReadFull(r, buf)
+		if n > 0 {
+			if err = s.dao.Create(ctx, &model.FileChunk{Path: path, Seq: seq, Size: int64(n), Data: buf[:n]}); err != nil {
+				break
+			}
+			size += int64(n)
+		}
+		if rErr == io.EOF || rErr == io.ErrUnexpectedEOF {
+			return
+		}
+		if rErr != nil {
+			err = rErr
+			break
+		}
+	}
+	_ = s.Remove(ctx, path)
+	return 0, err
+}
+
+func (s *dbStorage) Append(ctx context.Context, path string, data []byte) (err error) {
+	seq, err := s.dao.Count(ctx, &model.FileChunkFilter{Path: db.Eq(path)})
+	if err != nil {
+		return
+	}
+	return s.dao.Create(ctx, &model.FileChunk{Path: path, Seq: seq, Size: int64(len(data)), Data: data})
+}
+
+func (s *dbStorage) Open(ctx context.Context, path string) (io.ReadCloser, error) {
+	if _, err := s.Size(ctx, path); err != nil {
+		return nil, err
+	}
+	return &chunkReader{ctx: ctx, dao: s.dao, path: path}, nil
+}
+
+func (s *dbStorage) Size(ctx context.Context, path string) (size int64, err error) {
+	total, err := s.dao.Count(ctx, &model.FileChunkFilter{Path: db.Eq(path)})
+	if err != nil {
+		return
+	}
+	if total == 0 {
+		err = fs.ErrNotExist
+		return
+	}
+	return s.dao.SumSize(ctx, path)
+}
+
+func (s *dbStorage) Remove(ctx context.Context, path string) (err error) {
+	_, err = s.dao.Delete(ctx, &model.FileChunkFilter{Path: db.Eq(path)})
+	return
+}
+
+// chunkReader 按序号逐块读取，避免一次加载整个文件
+type chunkReader struct {
+	ctx  context.Context
+	dao  relay.FileChunkDAO
+	path string
+	seq  int64
+	buf  []byte
+}
+
+func (r *chunkReader) Read(p []byte) (n int, err error) {
+	for len(r.buf) == 0 {
+		chunk, fErr := r.dao.FindOne(r.ctx, &model.FileChunkFilter{Path: db.Eq(r.path), Seq: db.Eq(r.seq)})
+		if db.IsRecordNotFound(fErr) {
+			return 0, io.EOF
+		} else if fErr != nil {
+			return 0, fErr
+		}
+		r.buf = chunk.Data
+		r.seq++
+	}
+	n = copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return
+}
+
+func (r *chunkReader) Close() error {
+	return nil
+}
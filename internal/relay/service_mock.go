@@ -11,6 +11,7 @@ package relay
 
 import (
 	context "context"
+	io "io"
 	reflect "reflect"
 
 	model "github.com/modelgate/modelgate/internal/relay/model"
@@ -84,6 +85,21 @@ func (mr *MockServiceMockRecorder) AddRequestUsage(ctx, providerCode, metric, va
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddRequestUsage", reflect.TypeOf((*MockService)(nil).AddRequestUsage), ctx, providerCode, metric, value)
 }
 
+// CancelBatch mocks base method.
+func (m *MockService) CancelBatch(ctx context.Context, accountId, id int64) (*model.Batch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelBatch", ctx, accountId, id)
+	ret0, _ := ret[0].(*model.Batch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelBatch indicates an expected call of CancelBatch.
+func (mr *MockServiceMockRecorder) CancelBatch(ctx, accountId, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelBatch", reflect.TypeOf((*MockService)(nil).CancelBatch), ctx, accountId, id)
+}
+
 // CreateAccount mocks base method.
 func (m *MockService) CreateAccount(ctx context.Context, req *model.CreateAccountRequest) (*model.Account, error) {
 	m.ctrl.T.Helper()
@@ -114,6 +130,36 @@ func (mr *MockServiceMockRecorder) CreateAccountApiKey(ctx, req any) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccountApiKey", reflect.TypeOf((*MockService)(nil).CreateAccountApiKey), ctx, req)
 }
 
+// CreateBatch mocks base method.
+func (m *MockService) CreateBatch(ctx context.Context, req *model.CreateBatchRequest) (*model.Batch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBatch", ctx, req)
+	ret0, _ := ret[0].(*model.Batch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateBatch indicates an expected call of CreateBatch.
+func (mr *MockServiceMockRecorder) CreateBatch(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBatch", reflect.TypeOf((*MockService)(nil).CreateBatch), ctx, req)
+}
+
+// CreateFile mocks base method.
+func (m *MockService) CreateFile(ctx context.Context, req *model.CreateFileRequest) (*model.File, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateFile", ctx, req)
+	ret0, _ := ret[0].(*model.File)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateFile indicates an expected call of CreateFile.
+func (mr *MockServiceMockRecorder) CreateFile(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFile", reflect.TypeOf((*MockService)(nil).CreateFile), ctx, req)
+}
+
 // CreateLedger mocks base method.
 func (m *MockService) CreateLedger(ctx context.Context, req *model.CreateLedgerRequest) (*model.Ledger, error) {
 	m.ctrl.T.Helper()
@@ -247,6 +293,20 @@ func (mr *MockServiceMockRecorder) DeleteAccounts(ctx, req any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccounts", reflect.TypeOf((*MockService)(nil).DeleteAccounts), ctx, req)
 }
 
+// DeleteFile mocks base method.
+func (m *MockService) DeleteFile(ctx context.Context, accountId, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFile", ctx, accountId, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteFile indicates an expected call of DeleteFile.
+func (mr *MockServiceMockRecorder) DeleteFile(ctx, accountId, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFile", reflect.TypeOf((*MockService)(nil).DeleteFile), ctx, accountId, id)
+}
+
 // DeleteLedgers mocks base method.
 func (m *MockService) DeleteLedgers(ctx context.Context, req *model.DeleteLedgersRequest) error {
 	m.ctrl.T.Helper()
@@ -331,6 +391,20 @@ func (mr *MockServiceMockRecorder) DeleteRequests(ctx, req any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRequests", reflect.TypeOf((*MockService)(nil).DeleteRequests), ctx, req)
 }
 
+// FinishRequestStats mocks base method.
+func (m *MockService) FinishRequestStats(ctx context.Context, req *model.RequestStatsRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishRequestStats", ctx, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FinishRequestStats indicates an expected call of FinishRequestStats.
+func (mr *MockServiceMockRecorder) FinishRequestStats(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishRequestStats", reflect.TypeOf((*MockService)(nil).FinishRequestStats), ctx, req)
+}
+
 // GetAccountApiKey mocks base method.
 func (m *MockService) GetAccountApiKey(ctx context.Context, apiKey string) (*model.AccountApiKey, error) {
 	m.ctrl.T.Helper()
@@ -378,6 +452,81 @@ func (mr *MockServiceMockRecorder) GetAccountList(ctx, req any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountList", reflect.TypeOf((*MockService)(nil).GetAccountList), ctx, req)
 }
 
+// GetAvailableModelList mocks base method.
+func (m *MockService) GetAvailableModelList(ctx context.Context, scope *model.ApiKeyScope) ([]*model.AvailableModel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAvailableModelList", ctx, scope)
+	ret0, _ := ret[0].([]*model.AvailableModel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAvailableModelList indicates an expected call of GetAvailableModelList.
+func (mr *MockServiceMockRecorder) GetAvailableModelList(ctx, scope any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAvailableModelList", reflect.TypeOf((*MockService)(nil).GetAvailableModelList), ctx, scope)
+}
+
+// GetBatch mocks base method.
+func (m *MockService) GetBatch(ctx context.Context, accountId, id int64) (*model.Batch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBatch", ctx, accountId, id)
+	ret0, _ := ret[0].(*model.Batch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBatch indicates an expected call of GetBatch.
+func (mr *MockServiceMockRecorder) GetBatch(ctx, accountId, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBatch", reflect.TypeOf((*MockService)(nil).GetBatch), ctx, accountId, id)
+}
+
+// GetBatchList mocks base method.
+func (m *MockService) GetBatchList(ctx context.Context, req *model.GetBatchListRequest) ([]*model.Batch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBatchList", ctx, req)
+	ret0, _ := ret[0].([]*model.Batch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBatchList indicates an expected call of GetBatchList.
+func (mr *MockServiceMockRecorder) GetBatchList(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBatchList", reflect.TypeOf((*MockService)(nil).GetBatchList), ctx, req)
+}
+
+// GetFile mocks base method.
+func (m *MockService) GetFile(ctx context.Context, accountId, id int64) (*model.File, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFile", ctx, accountId, id)
+	ret0, _ := ret[0].(*model.File)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFile indicates an expected call of GetFile.
+func (mr *MockServiceMockRecorder) GetFile(ctx, accountId, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFile", reflect.TypeOf((*MockService)(nil).GetFile), ctx, accountId, id)
+}
+
+// GetFileList mocks base method.
+func (m *MockService) GetFileList(ctx context.Context, req *model.GetFileListRequest) ([]*model.File, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFileList", ctx, req)
+	ret0, _ := ret[0].([]*model.File)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFileList indicates an expected call of GetFileList.
+func (mr *MockServiceMockRecorder) GetFileList(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFileList", reflect.TypeOf((*MockService)(nil).GetFileList), ctx, req)
+}
+
 // GetLedgerList mocks base method.
 func (m *MockService) GetLedgerList(ctx context.Context, req *model.GetLedgerListRequest) (int64, []*model.Ledger, error) {
 	m.ctrl.T.Helper()
@@ -520,19 +669,75 @@ func (mr *MockServiceMockRecorder) GetRequestList(ctx, req any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRequestList", reflect.TypeOf((*MockService)(nil).GetRequestList), ctx, req)
 }
 
+// OpenFileContent mocks base method.
+func (m *MockService) OpenFileContent(ctx context.Context, accountId, id int64) (*model.File, io.ReadCloser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OpenFileContent", ctx, accountId, id)
+	ret0, _ := ret[0].(*model.File)
+	ret1, _ := ret[1].(io.ReadCloser)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// OpenFileContent indicates an expected call of OpenFileContent.
+func (mr *MockServiceMockRecorder) OpenFileContent(ctx, accountId, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenFileContent", reflect.TypeOf((*MockService)(nil).OpenFileContent), ctx, accountId, id)
+}
+
+// ReportProviderApiKeyResult mocks base method.
+func (m *MockService) ReportProviderApiKeyResult(ctx context.Context, req *model.ReportProviderApiKeyResultRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReportProviderApiKeyResult", ctx, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReportProviderApiKeyResult indicates an expected call of ReportProviderApiKeyResult.
+func (mr *MockServiceMockRecorder) ReportProviderApiKeyResult(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReportProviderApiKeyResult", reflect.TypeOf((*MockService)(nil).ReportProviderApiKeyResult), ctx, req)
+}
+
 // ResolveModel mocks base method.
-func (m *MockService) ResolveModel(ctx context.Context, provider, modelCode string) (*model.ResolvedModel, error) {
+func (m *MockService) ResolveModel(ctx context.Context, req *model.ResolveModelRequest) (*model.ResolvedModel, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ResolveModel", ctx, provider, modelCode)
+	ret := m.ctrl.Call(m, "ResolveModel", ctx, req)
 	ret0, _ := ret[0].(*model.ResolvedModel)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ResolveModel indicates an expected call of ResolveModel.
-func (mr *MockServiceMockRecorder) ResolveModel(ctx, provider, modelCode any) *gomock.Call {
+func (mr *MockServiceMockRecorder) ResolveModel(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveModel", reflect.TypeOf((*MockService)(nil).ResolveModel), ctx, req)
+}
+
+// StartBatchWorker mocks base method.
+func (m *MockService) StartBatchWorker(ctx context.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "StartBatchWorker", ctx)
+}
+
+// StartBatchWorker indicates an expected call of StartBatchWorker.
+func (mr *MockServiceMockRecorder) StartBatchWorker(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartBatchWorker", reflect.TypeOf((*MockService)(nil).StartBatchWorker), ctx)
+}
+
+// StartRequestStats mocks base method.
+func (m *MockService) StartRequestStats(ctx context.Context, req *model.RequestStatsRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartRequestStats", ctx, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StartRequestStats indicates an expected call of StartRequestStats.
+func (mr *MockServiceMockRecorder) StartRequestStats(ctx, req any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveModel", reflect.TypeOf((*MockService)(nil).ResolveModel), ctx, provider, modelCode)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartRequestStats", reflect.TypeOf((*MockService)(nil).StartRequestStats), ctx, req)
 }
 
 // StartWorker mocks base method.
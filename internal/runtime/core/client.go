@@ -1,16 +1,169 @@
 package core
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
 	"net/http"
+	"net/url"
+	"strings"
+	"sync"
 	"time"
+
+	"github.com/samber/lo"
+	log "github.com/sirupsen/logrus"
 )
 
+const defaultHttpTimeout = 300 * time.Second
+
 // HttpClient 全局 HTTP 客户端
 var HttpClient = &http.Client{
-	Timeout: 300 * time.Second,
+	Timeout: defaultHttpTimeout,
 }
 
 // DefaultHttpClient 返回默认客户端（用于需要 *http.Client 的场景）
 func DefaultHttpClient() *http.Client {
 	return HttpClient
 }
+
+// TransportConfig 供应商的 HTTP 传输配置，未设置的字段使用默认值
+type TransportConfig struct {
+	ProxyUrl string `json:"proxy_url,omitempty"` // 代理地址，支持 http、https、socks5、socks5h
+
+	CACert             string `json:"ca_cert,omitempty"`              // PEM 格式 CA 证书，追加到系统证书
+	ClientCert         string `json:"client_cert,omitempty"`          // PEM 格式客户端证书，双向 TLS 使用
+	ClientKey          string `json:"client_key,omitempty"`           // PEM 格式客户端私钥，保存时加密到 ClientKeyEncrypted
+	ClientKeyEncrypted string `json:"client_key_encrypted,omitempty"` // 加密后的客户端私钥，使用前由调用方解密到 ClientKey
+	ServerName         string `json:"server_name,omitempty"`          // 校验证书使用的域名
+	InsecureSkipVerify bool   `json:"insecure_skip_verify,omitempty"` // 不校验证书
+
+	TimeoutMs        int64 `json:"timeout_ms,omitempty"`         // 整个请求的超时时间，包括读取流式响应，默认 300 秒
+	ConnectTimeoutMs int64 `json:"connect_timeout_ms,omitempty"` // 建立连接的超时时间
+	ReadTimeoutMs    int64 `json:"read_timeout_ms,omitempty"`    // 发送请求后等待响应头的超时时间
+	IdleTimeoutMs    int64 `json:"idle_timeout_ms,omitempty"`    // 空闲连接保留时间
+
+	MaxConns     int   `json:"max_conns,omitempty"`      // 最大连接数，0 表示不限
+	MaxIdleConns int   `json:"max_idle_conns,omitempty"` // 最大空闲连接数
+	HTTP2        *bool `json:"http2,omitempty"`          // 是否使用 HTTP/2，默认使用
+}
+
+// MaskedClientKey 返回给前端的客户端私钥掩码，更新时原样提交表示不修改
+const MaskedClientKey = "******"
+
+// ParseTransportConfig 解析传输配置 JSON，空值表示不配置
+func ParseTransportConfig(data string) (cfg *TransportConfig, err error) {
+	cfg = &TransportConfig{}
+	if strings.TrimSpace(data) == "" {
+		return
+	}
+	err = json.Unmarshal([]byte(data), cfg)
+	return
+}
+
+// IsZero 没有任何配置，使用全局客户端
+func (cfg *TransportConfig) IsZero() bool {
+	return *cfg == TransportConfig{}
+}
+
+// NewClient 按配置创建客户端，配置无效时返回错误
+func (cfg *TransportConfig) NewClient() (client *http.Client, err error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if cfg.ProxyUrl != "" {
+		var proxy *url.URL
+		if proxy, err = url.Parse(cfg.ProxyUrl); err != nil {
+			return
+		}
+		if !lo.Contains([]string{"http", "https", "socks5", "socks5h"}, proxy.Scheme) || proxy.Host == "" {
+			err = fmt.Errorf("unsupported proxy url: %s", cfg.ProxyUrl)
+			return
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+
+	tlsConfig := &tls.Config{
+		ServerName:         cfg.ServerName,
+		InsecureSkipVerify: cfg.InsecureSkipVerify,
+	}
+	if cfg.CACert != "" {
+		if tlsConfig.RootCAs, err = x509.SystemCertPool(); err != nil {
+			tlsConfig.RootCAs = x509.NewCertPool()
+		}
+		if !tlsConfig.RootCAs.AppendCertsFromPEM([]byte(cfg.CACert)) {
+			err = errors.New("invalid ca cert")
+			return
+		}
+	}
+	if cfg.ClientCert != "" || cfg.ClientKey != "" {
+		var cert tls.Certificate
+		if cert, err = tls.X509KeyPair([]byte(cfg.ClientCert), []byte(cfg.ClientKey)); err != nil {
+			return
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	transport.TLSClientConfig = tlsConfig
+
+	if cfg.ConnectTimeoutMs > 0 {
+		dialer := &net.Dialer{Timeout: time.Duration(cfg.ConnectTimeoutMs) * time.Millisecond, KeepAlive: 30 * time.Second}
+		transport.DialContext = dialer.DialContext
+	}
+	if cfg.ReadTimeoutMs > 0 {
+		transport.ResponseHeaderTimeout = time.Duration(cfg.ReadTimeoutMs) * time.Millisecond
+	}
+	if cfg.IdleTimeoutMs > 0 {
+		transport.IdleConnTimeout = time.Duration(cfg.IdleTimeoutMs) * time.Millisecond
+	}
+	if cfg.MaxConns > 0 {
+		transport.MaxConnsPerHost = cfg.MaxConns
+	}
+	if cfg.MaxIdleConns > 0 {
+		transport.MaxIdleConns = cfg.MaxIdleConns
+		transport.MaxIdleConnsPerHost = cfg.MaxIdleConns
+	}
+	// 设置 TLSNextProto 为空 map 时不使用 HTTP/2
+	transport.ForceAttemptHTTP2 = lo.FromPtrOr(cfg.HTTP2, true)
+	if !transport.ForceAttemptHTTP2 {
+		transport.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	}
+
+	timeout := defaultHttpTimeout
+	if cfg.TimeoutMs > 0 {
+		timeout = time.Duration(cfg.TimeoutMs) * time.Millisecond
+	}
+	client = &http.Client{Timeout: timeout, Transport: transport}
+	return
+}
+
+// providerClient 按传输配置创建的客户端
+type providerClient struct {
+	transport string
+	client    *http.Client
+}
+
+// providerClients 供应商 ID -> *providerClient
+var providerClients sync.Map
+
+// ProviderClient 供应商的 HTTP 客户端，按供应商缓存，传输配置修改后重新创建并关闭旧客户端的空闲连接
+// 没有配置或配置无效时使用全局客户端
+func ProviderClient(m *Model) *http.Client {
+	if v, ok := providerClients.Load(m.ProviderId); ok && v.(*providerClient).transport == m.Transport {
+		return v.(*providerClient).client
+	}
+	client := HttpClient
+	cfg, err := ParseTransportConfig(m.Transport)
+	if err == nil && !cfg.IsZero() {
+		client, err = cfg.NewClient()
+	}
+	if err != nil {
+		log.Errorf("provider %s transport config invalid, use default client: %v", m.ProviderCode, err)
+		client = HttpClient
+	}
+	if old, loaded := providerClients.Swap(m.ProviderId, &providerClient{transport: m.Transport, client: client}); loaded {
+		if oldClient := old.(*providerClient).client; oldClient != HttpClient {
+			oldClient.CloseIdleConnections()
+		}
+	}
+	return client
+}
@@ -0,0 +1,52 @@
+package core
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestTransportConfigNewClient(t *testing.T) {
+	cfg, err := ParseTransportConfig(`{"proxy_url":"socks5://127.0.0.1:1080","read_timeout_ms":2000,"max_conns":8,"http2":false}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := cfg.NewClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	transport := client.Transport.(*http.Transport)
+	req, _ := http.NewRequest(http.MethodGet, "https://api.example.com", nil)
+	if proxy, _ := transport.Proxy(req); proxy == nil || proxy.Scheme != "socks5" {
+		t.Fatalf("unexpected proxy: %v", proxy)
+	}
+	if transport.ResponseHeaderTimeout != 2*time.Second || transport.MaxConnsPerHost != 8 || transport.ForceAttemptHTTP2 {
+		t.Fatalf("unexpected transport: %+v", transport)
+	}
+	if client.Timeout != defaultHttpTimeout {
+		t.Fatalf("unexpected timeout: %v", client.Timeout)
+	}
+
+	for _, data := range []string{`{"proxy_url":"ftp://127.0.0.1"}`, `{"ca_cert":"invalid"}`} {
+		cfg, _ := ParseTransportConfig(data)
+		if _, err := cfg.NewClient(); err == nil {
+			t.Fatalf("%s: expected error", data)
+		}
+	}
+}
+
+func TestProviderClient(t *testing.T) {
+	m := &Model{ProviderId: -1}
+	if ProviderClient(m) != HttpClient {
+		t.Fatal("empty transport should use default client")
+	}
+	m.Transport = `{"timeout_ms":1000}`
+	client := ProviderClient(m)
+	if client == HttpClient || client.Timeout != time.Second || ProviderClient(m) != client {
+		t.Fatal("client should be created once per transport config")
+	}
+	m.Transport = `{"timeout_ms":2000}`
+	if next := ProviderClient(m); next == client || next.Timeout != 2*time.Second {
+		t.Fatal("client should be replaced after transport config changed")
+	}
+}
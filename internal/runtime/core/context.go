@@ -1,8 +1,11 @@
 package core
 
 import (
+	"context"
+	"encoding/json"
 	"net/http"
 	"sync"
+	"time"
 
 	"github.com/modelgate/modelgate/pkg/utils"
 )
@@ -16,10 +19,11 @@ type Context struct {
 	AttemptNo    int
 	RequestId    int64
 	UrlPath      string
-	IsAnthropic  bool
-	ProviderCode string
-	ModelCode    string
-	CurrentModel *Model // 模型
+	Protocol     Protocol // 入站协议
+	Endpoint     Endpoint // 接口类型
+	ProviderCode string   // 请求的供应商，为空表示不限
+	ModelCode    string   // 请求的模型
+	CurrentModel *Model   // 模型
 
 	PromptTokens     int // 输入Token数
 	CompletionTokens int // 输出Token数
@@ -30,8 +34,15 @@ type Context struct {
 	PreCost   int64 // 预先扣费
 	TotalCost int64 // 实际扣费
 
-	Header    http.Header
-	InputBody []byte // 统一输入
+	Header      http.Header
+	ContentType string // 请求体类型，为空表示 JSON，multipart 请求原样透传
+	InputBody   []byte // 统一输入
+
+	Units     int64   // 按量计费的数量，如图片张数、音频秒数、字符数
+	UnitPrice float64 // 按量计费的单价
+
+	StartedAt    time.Time // 本次尝试开始时间
+	FirstChunkAt time.Time // 收到第一个流式 chunk 的时间
 
 	// HTTP
 	HTTPRequest  *http.Request
@@ -39,10 +50,24 @@ type Context struct {
 	RawResponse  []byte
 
 	// 流
-	IsStream     bool
-	StreamWriter StreamWriter
+	IsStream      bool
+	StreamWriter  StreamWriter
+	StreamStarted bool // 已向客户端写入流式数据，之后不能再重试
+
+	IsBatch bool // 批量任务，按模型的批量折扣计费
+
+	// 二进制响应，如语音合成的音频，直接写给客户端
+	BinaryWriter BinaryWriter
+
+	// 客户端消息连接，如 Realtime 的 WebSocket
+	ClientConn MessageConn
+	// Settle 会话中按已有用量结算，余额不足时返回错误，由计费 hook 设置
+	Settle func(ctx context.Context, c *Context) error
 
-	LastErr error
+	LastErr   error
+	Cancelled bool // 客户端断开，本次尝试被取消
+
+	hedge *hedgeAttempt // 对冲请求中的一次尝试
 }
 
 // Pool Context 对象池
@@ -71,6 +96,8 @@ func (ctx *Context) Reset() {
 	ctx.AttemptNo = 0
 	ctx.RequestId = 0
 	ctx.UrlPath = ""
+	ctx.Protocol = ""
+	ctx.Endpoint = ""
 	ctx.ProviderCode = ""
 	ctx.ModelCode = ""
 	ctx.CurrentModel = nil
@@ -81,11 +108,60 @@ func (ctx *Context) Reset() {
 	ctx.PreCost = 0
 	ctx.TotalCost = 0
 	ctx.Header = nil
+	ctx.ContentType = ""
+	ctx.Units = 0
+	ctx.UnitPrice = 0
 	ctx.InputBody = nil
+	ctx.StartedAt = time.Time{}
+	ctx.FirstChunkAt = time.Time{}
 	ctx.HTTPRequest = nil
 	ctx.HTTPResponse = nil
 	ctx.RawResponse = nil
 	ctx.IsStream = false
 	ctx.StreamWriter = nil
+	ctx.StreamStarted = false
+	ctx.BinaryWriter = nil
+	ctx.ClientConn = nil
+	ctx.Settle = nil
+	ctx.IsBatch = false
 	ctx.LastErr = nil
+	ctx.Cancelled = false
+	ctx.hedge = nil
+}
+
+// resetAttempt 重试前清除上一次尝试的响应和用量
+func (ctx *Context) resetAttempt() {
+	ctx.CompletionTokens = 0
+	ctx.Usage = nil
+	ctx.ActualModel = ""
+	ctx.FirstChunkAt = time.Time{}
+	ctx.HTTPRequest = nil
+	ctx.HTTPResponse = nil
+	ctx.RawResponse = nil
+	ctx.LastErr = nil
+	ctx.Cancelled = false
+}
+
+// RequestBody 发送给上游的请求体，model 设置为当前尝试的模型，故障转移到其他模型时不会使用客户端请求的模型名
+func (ctx *Context) RequestBody() ([]byte, error) {
+	if len(ctx.InputBody) == 0 || ctx.CurrentModel == nil {
+		return ctx.InputBody, nil
+	}
+	if utils.IsMultipart(ctx.ContentType) {
+		return utils.MultipartSetField(ctx.ContentType, ctx.InputBody, "model", ctx.CurrentModel.ModelCode)
+	}
+	body := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(ctx.InputBody, &body); err != nil {
+		return nil, err
+	}
+	body["model"], _ = json.Marshal(ctx.CurrentModel.ModelCode)
+	return json.Marshal(body)
+}
+
+// GetContentType 请求体类型，默认 JSON
+func (ctx *Context) GetContentType() string {
+	if ctx.ContentType == "" {
+		return "application/json"
+	}
+	return ctx.ContentType
 }
@@ -0,0 +1,35 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"testing"
+
+	"github.com/modelgate/modelgate/pkg/utils"
+)
+
+func TestRequestBody(t *testing.T) {
+	c := &Context{CurrentModel: &Model{ModelCode: "gpt-4o-2024-08-06"}, InputBody: []byte(`{"model":"gpt-4o","stream":true}`)}
+	body, err := c.RequestBody()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var req map[string]any
+	if err = json.Unmarshal(body, &req); err != nil || req["model"] != "gpt-4o-2024-08-06" || req["stream"] != true {
+		t.Fatalf("unexpected body: %s", body)
+	}
+
+	buf := new(bytes.Buffer)
+	writer := multipart.NewWriter(buf)
+	_ = writer.WriteField("model", "whisper")
+	_ = writer.Close()
+	c.ContentType, c.InputBody = writer.FormDataContentType(), buf.Bytes()
+	c.CurrentModel.ModelCode = "whisper-1"
+	if body, err = c.RequestBody(); err != nil {
+		t.Fatal(err)
+	}
+	if values, _ := utils.MultipartFormValues(c.ContentType, body); values["model"] != "whisper-1" {
+		t.Fatalf("unexpected values: %v", values)
+	}
+}
@@ -0,0 +1,58 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Endpoint 接口类型
+type Endpoint string
+
+const (
+	EndpointChat       Endpoint = "chat"       // 对话补全
+	EndpointEmbeddings Endpoint = "embeddings" // 向量
+	EndpointImages     Endpoint = "images"     // 图片生成、编辑
+	EndpointAudio      Endpoint = "audio"      // 语音转写、翻译
+	EndpointSpeech     Endpoint = "speech"     // 语音合成
+	EndpointRerank     Endpoint = "rerank"     // 重排序
+	EndpointRealtime   Endpoint = "realtime"   // 实时语音会话，WebSocket
+
+	EndpointCountTokens Endpoint = "count_tokens" // 计算输入 Token
+)
+
+// IsChat 是否为对话补全，未指定时默认为对话补全
+func (e Endpoint) IsChat() bool {
+	return e == "" || e == EndpointChat
+}
+
+// IsFree 是否为免费的元数据接口，不记录请求、不计费
+func (e Endpoint) IsFree() bool {
+	return e == EndpointCountTokens
+}
+
+// ErrEndpointNotSupported 供应商不支持该接口
+func ErrEndpointNotSupported(provider string, endpoint Endpoint) error {
+	return fmt.Errorf("provider %s does not support %s", provider, endpoint)
+}
+
+// PathEndpoint 根据请求路径判断接口类型
+func PathEndpoint(path string) Endpoint {
+	switch {
+	case path == "/v1/embeddings":
+		return EndpointEmbeddings
+	case strings.HasPrefix(path, "/v1/images/"):
+		return EndpointImages
+	case path == "/v1/audio/speech":
+		return EndpointSpeech
+	case strings.HasPrefix(path, "/v1/audio/"):
+		return EndpointAudio
+	case path == "/v1/rerank":
+		return EndpointRerank
+	case path == "/v1/realtime":
+		return EndpointRealtime
+	case strings.HasSuffix(path, "/v1/messages/count_tokens"):
+		return EndpointCountTokens
+	default:
+		return EndpointChat
+	}
+}
@@ -2,7 +2,10 @@ package core
 
 import (
 	"context"
+	"slices"
+	"time"
 
+	"github.com/samber/lo"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -25,16 +28,18 @@ func NewExecutor(h Handler, hooks ...Hook) Executor {
 // Execute 执行
 func (e *executor) Execute(ctx context.Context, c *Context) (err error) {
 	// hooks before
-	e.callHooksBefore(ctx, c)
+	hooks := e.pipeline(c)
+	e.callHooksBefore(ctx, c, hooks)
 
 	// execute
-	err = e.execute(ctx, c)
+	err = c.settleHedge(e.execute(ctx, c))
 	if err != nil {
 		c.LastErr = err
+		c.Cancelled = ctx.Err() != nil
 	}
 
 	// hooks after（反向）
-	e.callHooksAfter(ctx, c)
+	e.callHooksAfter(ctx, c, hooks)
 	return
 }
 
@@ -43,8 +48,9 @@ func (e *executor) execute(ctx context.Context, c *Context) (err error) {
 	log.Debugf("provider %s, model: %s before request...", e.handler.Provider(), c.CurrentModel.ModelCode)
 	if err = e.handler.BeforeRequest(ctx, c); err != nil {
 		log.Errorf("provider %s before error: %v", e.handler.Provider(), err)
-		return
+		return &LocalError{Err: err}
 	}
+	c.bindHedgeRequest()
 
 	// do request
 	log.Debugf("provider %s, model: %s do request...", e.handler.Provider(), c.CurrentModel.ModelCode)
@@ -66,8 +72,22 @@ type baseExecutor struct {
 	hooks []Hook
 }
 
-func (e *baseExecutor) callHooksBefore(ctx context.Context, c *Context) {
-	for _, h := range e.hooks {
+// pipeline 按当前模型的流水线组装本次尝试的 hooks，故障转移到其他模型时重新组装
+// 流水线无效时使用默认的 hooks，避免请求不记录、不计费
+func (e *baseExecutor) pipeline(c *Context) []Hook {
+	if c.CurrentModel == nil || c.CurrentModel.Pipeline.IsZero() {
+		return e.hooks
+	}
+	hooks, err := ExecutorRegistry.BuildHooks(c.CurrentModel.ModelId, c.CurrentModel.Pipeline, e.hooks)
+	if err != nil {
+		log.Errorf("model %s pipeline invalid, use default hooks: %v", c.CurrentModel.ModelCode, err)
+		return e.hooks
+	}
+	return hooks
+}
+
+func (e *baseExecutor) callHooksBefore(ctx context.Context, c *Context, hooks []Hook) {
+	for _, h := range hooks {
 		log.Debugf("hook %s before request...", h.Name())
 		if hErr := h.Before(ctx, c); hErr != nil {
 			log.Errorf("hook %s before error: %v", h.Name(), hErr)
@@ -75,11 +95,13 @@ func (e *baseExecutor) callHooksBefore(ctx context.Context, c *Context) {
 	}
 }
 
-func (e *baseExecutor) callHooksAfter(ctx context.Context, c *Context) {
-	for i := len(e.hooks) - 1; i >= 0; i-- {
-		log.Debugf("hook %s after request...", e.hooks[i].Name())
-		if hErr := e.hooks[i].After(ctx, c); hErr != nil {
-			log.Errorf("hook %s after error: %v", e.hooks[i].Name(), hErr)
+// callHooksAfter 客户端断开后仍要完成记录和结算，不使用已取消的 ctx
+func (e *baseExecutor) callHooksAfter(ctx context.Context, c *Context, hooks []Hook) {
+	ctx = context.WithoutCancel(ctx)
+	for i := len(hooks) - 1; i >= 0; i-- {
+		log.Debugf("hook %s after request...", hooks[i].Name())
+		if hErr := hooks[i].After(ctx, c); hErr != nil {
+			log.Errorf("hook %s after error: %v", hooks[i].Name(), hErr)
 		}
 	}
 }
@@ -91,31 +113,149 @@ func newBaseExecutor(hooks []Hook) *baseExecutor {
 }
 
 type retryExecutor struct {
-	base  Executor
-	retry int
+	base Executor
+	opts Options
 }
 
-// NewRetryExecutor 创建重试执行器，retry <= 0 时返回原执行器
-func NewRetryExecutor(base Executor, retry int) Executor {
-	if retry <= 0 {
+// NewRetryExecutor 创建重试执行器，opts.Retry <= 0 时返回原执行器
+func NewRetryExecutor(base Executor, opts Options) Executor {
+	if opts.Retry <= 0 {
 		return base
 	}
 	return &retryExecutor{
-		base:  base,
-		retry: retry,
+		base: base,
+		opts: opts,
 	}
 }
 
-// Execute 执行并重试
+// Execute 执行并重试，失败后故障转移到其他模型或供应商 API Key，每次尝试单独记录
+// 重试次数使用模型的重试策略，没有其他可用模型时按退避时间等待后重试当前模型
 func (r *retryExecutor) Execute(ctx context.Context, c *Context) (err error) {
-	for i := 0; i <= r.retry; i++ {
-		c.AttemptNo = i + 1
-		c.LastErr = nil
-		err = r.base.Execute(ctx, c)
+	exec, provider := r.base, c.CurrentModel.ProviderCode
+	retries := c.CurrentModel.RetryPolicy.Retries(r.opts.Retry)
+	var excludeModelIds, excludeApiKeyIds []int64
+	attemptNo := 0
+	for i := 0; ; i++ {
+		attemptNo++
+		c.AttemptNo = attemptNo
+		c.resetAttempt()
+		tried := []*Model{c.CurrentModel}
+		if hedgeDelay := c.CurrentModel.RetryPolicy.HedgeDelay(); hedgeDelay > 0 && c.hedgeable() && r.opts.Failover != nil {
+			var hedged *Model
+			hedged, exec, err = r.executeHedged(ctx, c, exec, provider, hedgeDelay, excludeModelIds, excludeApiKeyIds)
+			provider = c.CurrentModel.ProviderCode
+			if hedged != nil {
+				attemptNo++
+				tried = append(tried, hedged)
+			}
+		} else {
+			err = exec.Execute(ctx, c)
+		}
 		if err == nil {
 			return nil
 		}
-		log.Warnf("executor attempt %d failed: %v", c.AttemptNo, err)
+		log.Warnf("executor attempt %d failed, provider: %s, model: %s, error: %v",
+			c.AttemptNo, c.CurrentModel.ProviderCode, c.CurrentModel.ModelCode, err)
+		if i >= retries || !isRetryable(ctx, c) {
+			return
+		}
+		delay := c.CurrentModel.RetryPolicy.Backoff(i+1, c.HTTPResponse)
+		for _, m := range tried {
+			excludeModelIds = append(excludeModelIds, m.ModelId)
+			excludeApiKeyIds = append(excludeApiKeyIds, m.ApiKeyId)
+		}
+		if next, nextExec := r.failover(ctx, c, provider, excludeModelIds, excludeApiKeyIds); next != nil {
+			// 换了模型或 API Key，不需要等待
+			c.CurrentModel, delay = next, 0
+			if nextExec != nil {
+				exec, provider = nextExec, next.ProviderCode
+			}
+		}
+		if sErr := sleep(ctx, delay); sErr != nil {
+			log.Warnf("stop retry: %v", sErr)
+			return
+		}
+	}
+}
+
+// executeHedged 对冲执行：当前尝试在 delay 内没有响应时，向其他 API Key 或模型再发一个请求，使用先响应的一个并取消另一个
+// 两次尝试分别记录，被取消的一方不计费。结束后 c 为胜出的尝试，都没有响应时为第一次尝试，返回第二次尝试的模型和 c 对应的执行器
+func (r *retryExecutor) executeHedged(ctx context.Context, c *Context, exec Executor, provider string, delay time.Duration,
+	excludeModelIds, excludeApiKeyIds []int64) (hedged *Model, used Executor, err error) {
+	type result struct {
+		c    *Context
+		exec Executor
+	}
+	group := &hedgeGroup{}
+	results := make(chan result, 2)
+	run := func(e Executor, attempt *Context) {
+		go func() {
+			_ = e.Execute(ctx, attempt)
+			results <- result{c: attempt, exec: e}
+		}()
+	}
+	run(exec, group.fork(ctx, c))
+	pending := 1
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	var res result
+	for pending > 0 {
+		select {
+		case <-timer.C:
+			if group.claimed() {
+				continue
+			}
+			// 只排除当前 API Key，可以对冲到同一模型的其他 Key
+			excludes := append(slices.Clone(excludeApiKeyIds), c.CurrentModel.ApiKeyId)
+			next, nextExec := r.failover(ctx, c, provider, slices.Clone(excludeModelIds), excludes)
+			if next == nil {
+				continue
+			}
+			log.Infof("hedge after %s, provider: %s, model: %s, api key: %d", delay, next.ProviderCode, next.ModelCode, next.ApiKeyId)
+			attempt := group.fork(ctx, c)
+			attempt.AttemptNo, attempt.CurrentModel = c.AttemptNo+1, next
+			run(lo.CoalesceOrEmpty(nextExec, exec), attempt)
+			hedged = next
+			pending++
+		case done := <-results:
+			pending--
+			// 胜出方可能在流式响应中途失败，仍以胜出方为准
+			if group.won(done.c.hedge) || (!group.claimed() && done.c.AttemptNo == c.AttemptNo) {
+				res = done
+			}
+		}
+	}
+	*c = *res.c
+	c.hedge = nil
+	return hedged, res.exec, c.LastErr
+}
+
+// failover 重新解析模型，跨供应商时返回对应的执行器，没有其他可用模型时继续重试当前模型
+func (r *retryExecutor) failover(ctx context.Context, c *Context, provider string, excludeModelIds, excludeApiKeyIds []int64) (*Model, Executor) {
+	if r.opts.Failover == nil {
+		return nil, nil
+	}
+	for {
+		next, err := r.opts.Failover(ctx, c, excludeModelIds, excludeApiKeyIds)
+		if err != nil {
+			log.Warnf("no failover model, retry current model: %v", err)
+			return nil, nil
+		}
+		if next.ProviderCode == provider {
+			log.Infof("failover to model: %s, api key: %d", next.ModelCode, next.ApiKeyId)
+			return next, nil
+		}
+		// 不重试，重试由当前执行器控制
+		opts := r.opts
+		opts.Retry = 0
+		exec, err := ExecutorRegistry.Get(next.ProviderCode, opts)
+		if err == nil {
+			log.Infof("failover to provider: %s, model: %s", next.ProviderCode, next.ModelCode)
+			return next, exec
+		}
+		// 供应商不支持该接口，排除后继续
+		log.Warnf("failover provider %s unavailable: %v", next.ProviderCode, err)
+		excludeModelIds = append(excludeModelIds, next.ModelId)
 	}
-	return err
 }
@@ -0,0 +1,297 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/samber/lo"
+)
+
+type fakeExecutor struct {
+	fail map[int64]int // ApiKeyId -> 返回的状态码
+	hops []int64
+}
+
+func (e *fakeExecutor) Execute(ctx context.Context, c *Context) error {
+	e.hops = append(e.hops, c.CurrentModel.ApiKeyId)
+	if code, ok := e.fail[c.CurrentModel.ApiKeyId]; ok {
+		c.HTTPResponse = &http.Response{StatusCode: code}
+		return errors.New("upstream error")
+	}
+	return nil
+}
+
+func TestRetryExecutorFailover(t *testing.T) {
+	other := &fakeExecutor{}
+	ExecutorRegistry.Register("test-failover", func(opts Options) (Executor, error) {
+		return other, nil
+	})
+	base := &fakeExecutor{fail: map[int64]int{1: http.StatusInternalServerError, 2: http.StatusTooManyRequests}}
+	models := []*Model{
+		{ModelId: 10, ProviderCode: "test", ApiKeyId: 2},
+		{ModelId: 20, ProviderCode: "test-failover", ApiKeyId: 3},
+	}
+	exec := NewRetryExecutor(base, Options{
+		Retry: 3,
+		Failover: func(ctx context.Context, c *Context, excludeModelIds, excludeApiKeyIds []int64) (*Model, error) {
+			return models[len(excludeApiKeyIds)-1], nil
+		},
+	})
+	c := &Context{CurrentModel: &Model{ModelId: 1, ProviderCode: "test", ApiKeyId: 1}}
+	if err := exec.Execute(context.Background(), c); err != nil {
+		t.Fatal(err)
+	}
+	if len(base.hops) != 2 || len(other.hops) != 1 || c.AttemptNo != 3 || c.CurrentModel.ModelId != 20 {
+		t.Fatalf("unexpected hops: %v %v, attempt: %d", base.hops, other.hops, c.AttemptNo)
+	}
+
+	// 请求参数错误不重试
+	base.hops = nil
+	c = &Context{CurrentModel: &Model{ModelId: 1, ProviderCode: "test", ApiKeyId: 4}}
+	base.fail[4] = http.StatusBadRequest
+	if err := exec.Execute(context.Background(), c); err == nil || len(base.hops) != 1 {
+		t.Fatalf("unexpected retry: %v", base.hops)
+	}
+}
+
+// slowExecutor 按 API Key 延迟响应，对冲中被取消时提前返回
+type slowExecutor struct {
+	delay map[int64]time.Duration
+	mu    sync.Mutex
+	lost  []int64
+}
+
+func (e *slowExecutor) Execute(ctx context.Context, c *Context) (err error) {
+	select {
+	case <-time.After(e.delay[c.CurrentModel.ApiKeyId]):
+	case <-c.hedge.ctx.Done():
+		err = c.hedge.ctx.Err()
+	}
+	if err = c.settleHedge(err); err != nil {
+		c.LastErr = err
+	}
+	if c.HedgeLost() {
+		e.mu.Lock()
+		e.lost = append(e.lost, c.CurrentModel.ApiKeyId)
+		e.mu.Unlock()
+	}
+	return
+}
+
+func TestRetryExecutorHedge(t *testing.T) {
+	base := &slowExecutor{delay: map[int64]time.Duration{1: time.Second, 2: 10 * time.Millisecond}}
+	exec := NewRetryExecutor(base, Options{
+		Retry: 3,
+		Failover: func(ctx context.Context, c *Context, excludeModelIds, excludeApiKeyIds []int64) (*Model, error) {
+			return &Model{ModelId: 1, ProviderCode: "test", ApiKeyId: 2}, nil
+		},
+	})
+	policy := RetryPolicy{HedgeDelayMs: lo.ToPtr[int64](20)}
+	c := &Context{CurrentModel: &Model{ModelId: 1, ProviderCode: "test", ApiKeyId: 1, RetryPolicy: policy}}
+	start := time.Now()
+	if err := exec.Execute(context.Background(), c); err != nil {
+		t.Fatal(err)
+	}
+	if time.Since(start) > 500*time.Millisecond {
+		t.Fatal("slow attempt should be cancelled")
+	}
+	if c.AttemptNo != 2 || c.CurrentModel.ApiKeyId != 2 || c.Hedging() || len(base.lost) != 1 || base.lost[0] != 1 {
+		t.Fatalf("unexpected winner: attempt %d, api key %d, lost: %v", c.AttemptNo, c.CurrentModel.ApiKeyId, base.lost)
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{}.Merge(&RetryPolicy{BaseDelayMs: lo.ToPtr[int64](100), MaxDelayMs: lo.ToPtr[int64](1000)})
+	if d := policy.Backoff(3, nil); d < 200*time.Millisecond || d > 400*time.Millisecond {
+		t.Fatalf("unexpected backoff: %v", d)
+	}
+	if d := policy.Backoff(10, nil); d > time.Second {
+		t.Fatalf("backoff exceeds max delay: %v", d)
+	}
+	resp := &http.Response{Header: http.Header{"Retry-After": {"0.8"}}}
+	if d := policy.Backoff(1, resp); d != 800*time.Millisecond {
+		t.Fatalf("unexpected retry-after backoff: %v", d)
+	}
+	if policy.Retries(3) != 3 || policy.Merge(&RetryPolicy{MaxRetries: lo.ToPtr(0)}).Retries(3) != 0 {
+		t.Fatal("unexpected retries")
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	cases := []struct {
+		code   int
+		body   string
+		stream bool
+		want   bool
+	}{
+		{0, "", false, true},
+		{http.StatusOK, "", false, false},
+		{http.StatusOK, "", true, true},
+		{http.StatusBadRequest, "", false, false},
+		{http.StatusRequestTimeout, "", false, true},
+		{http.StatusTooManyRequests, `{"error":{"type":"rate_limit_exceeded"}}`, false, true},
+		{http.StatusTooManyRequests, `{"error":{"type":"insufficient_quota"}}`, false, false},
+		{http.StatusBadGateway, "", false, true},
+	}
+	for _, tc := range cases {
+		c := &Context{RawResponse: []byte(tc.body), IsStream: tc.stream}
+		if tc.code != 0 {
+			c.HTTPResponse = &http.Response{StatusCode: tc.code}
+		}
+		if got := isRetryable(context.Background(), c); got != tc.want {
+			t.Fatalf("status %d: got %v, want %v", tc.code, got, tc.want)
+		}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if isRetryable(ctx, &Context{}) {
+		t.Fatal("cancelled context should not retry")
+	}
+
+	// 发起上游请求前失败，如请求体转换失败，不重试
+	exec := NewExecutor(&fakeStreamHandler{before: errors.New("invalid body")})
+	c := &Context{CurrentModel: &Model{}}
+	if err := exec.Execute(context.Background(), c); !IsLocalError(err) || isRetryable(context.Background(), c) {
+		t.Fatalf("local error should not retry: %v", err)
+	}
+}
+
+func TestKeyRejectedReason(t *testing.T) {
+	cases := []struct {
+		code int
+		body string
+		want string
+	}{
+		{http.StatusOK, "", ""},
+		{http.StatusBadRequest, `{"error":{"type":"invalid_request_error","message":"bad"}}`, ""},
+		{http.StatusTooManyRequests, `{"error":{"type":"rate_limit_exceeded"}}`, ""},
+		{http.StatusTooManyRequests, `{"error":{"type":"insufficient_quota","code":"insufficient_quota","message":"exceeded quota"}}`, "429 insufficient_quota: exceeded quota"},
+		{http.StatusUnauthorized, `{"error":{"type":"invalid_request_error","code":"invalid_api_key","message":"Incorrect API key"}}`, "401 invalid_api_key: Incorrect API key"},
+		{http.StatusUnauthorized, `{"type":"error","error":{"type":"authentication_error","message":"invalid x-api-key"}}`, "401 authentication_error: invalid x-api-key"},
+		{http.StatusBadRequest, `{"error":{"code":400,"message":"API key not valid","status":"INVALID_ARGUMENT","details":[{"reason":"API_KEY_INVALID"}]}}`, "400 api_key_invalid: API key not valid"},
+		{http.StatusForbidden, "", "403 Forbidden"},
+	}
+	for _, tc := range cases {
+		if got := KeyRejectedReason(&http.Response{StatusCode: tc.code}, []byte(tc.body)); got != tc.want {
+			t.Fatalf("status %d: got %q, want %q", tc.code, got, tc.want)
+		}
+	}
+}
+
+type fakeStreamHandler struct {
+	Handler
+	streams [][]error // 每次尝试 Recv 的返回，nil 表示一个数据 chunk
+	before  error     // BeforeRequest 的返回
+}
+
+func (h *fakeStreamHandler) Provider() string { return "test" }
+
+func (h *fakeStreamHandler) BeforeRequest(ctx context.Context, c *Context) error { return h.before }
+
+func (h *fakeStreamHandler) DoStream(ctx context.Context, c *Context) (Stream, error) {
+	c.HTTPResponse = &http.Response{StatusCode: http.StatusOK}
+	s := &fakeStream{errs: h.streams[0]}
+	h.streams = h.streams[1:]
+	return s, nil
+}
+
+type fakeStream struct {
+	errs []error
+}
+
+func (s *fakeStream) Recv() (*StreamChunk, error) {
+	if len(s.errs) == 0 {
+		return &StreamChunk{Finish: true}, io.EOF
+	}
+	err := s.errs[0]
+	s.errs = s.errs[1:]
+	if err != nil {
+		return nil, err
+	}
+	return &StreamChunk{Data: "{}"}, nil
+}
+
+func (s *fakeStream) Close() error { return nil }
+
+// writeHook 模拟写入客户端
+type writeHook struct {
+	fakeHook
+	chunks []*StreamChunk
+}
+
+func (h *writeHook) OnChunk(ctx context.Context, c *Context, chunk *StreamChunk) error {
+	c.StreamStarted = c.StreamStarted || !chunk.Finish
+	h.chunks = append(h.chunks, chunk)
+	return nil
+}
+
+func (h *writeHook) OnError(ctx context.Context, c *Context, err error) {
+	if c.StreamStarted {
+		h.chunks = append(h.chunks, ErrorChunk(c.Protocol, err))
+	}
+}
+
+type fakeHook struct{}
+
+func (fakeHook) Name() string                                                  { return "fake" }
+func (fakeHook) Before(ctx context.Context, c *Context) error                  { return nil }
+func (fakeHook) After(ctx context.Context, c *Context) error                   { return nil }
+func (fakeHook) OnChunk(ctx context.Context, c *Context, _ *StreamChunk) error { return nil }
+func (fakeHook) OnError(ctx context.Context, c *Context, err error)            {}
+
+func TestStreamRetryBeforeFirstChunk(t *testing.T) {
+	reset := errors.New("connection reset")
+	hook := &writeHook{}
+	handler := &fakeStreamHandler{streams: [][]error{{reset}, {nil, reset}, {nil}}}
+	exec := NewRetryExecutor(NewStreamExecutor(handler, hook), Options{Retry: 3})
+	policy := RetryPolicy{BaseDelayMs: lo.ToPtr[int64](0)}
+	c := &Context{Protocol: ProtocolAnthropic, IsStream: true, CurrentModel: &Model{ProviderCode: "test", RetryPolicy: policy}}
+	err := exec.Execute(context.Background(), c)
+	// 第一次在第一个 chunk 之前失败，重试；第二次已经写入数据，不再重试
+	if err != reset || c.AttemptNo != 2 || len(handler.streams) != 1 {
+		t.Fatalf("unexpected result: %v, attempt: %d", err, c.AttemptNo)
+	}
+	last := hook.chunks[len(hook.chunks)-1]
+	if len(hook.chunks) != 2 || last.Event != "error" || !strings.Contains(last.Data, "connection reset") {
+		t.Fatalf("unexpected chunks: %+v", hook.chunks)
+	}
+}
+
+// cancelHook 第一个 chunk 后模拟客户端断开，记录 After 收到的 ctx 是否已取消
+type cancelHook struct {
+	writeHook
+	cancel   context.CancelFunc
+	afterErr error
+}
+
+func (h *cancelHook) OnChunk(ctx context.Context, c *Context, chunk *StreamChunk) error {
+	h.cancel()
+	return h.writeHook.OnChunk(ctx, c, chunk)
+}
+
+func (h *cancelHook) After(ctx context.Context, c *Context) error {
+	h.afterErr = ctx.Err()
+	return nil
+}
+
+func TestStreamClientCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	hook := &cancelHook{cancel: cancel}
+	handler := &fakeStreamHandler{streams: [][]error{{nil, nil, nil}}}
+	exec := NewRetryExecutor(NewStreamExecutor(handler, hook), Options{Retry: 3})
+	c := &Context{CurrentModel: &Model{ProviderCode: "test"}}
+	err := exec.Execute(ctx, c)
+	if !errors.Is(err, context.Canceled) || !c.Cancelled || c.AttemptNo != 1 {
+		t.Fatalf("unexpected result: %v, cancelled: %v, attempt: %d", err, c.Cancelled, c.AttemptNo)
+	}
+	// 一个数据 chunk 和错误事件，之后不再读取
+	if len(hook.chunks) != 2 || hook.afterErr != nil {
+		t.Fatalf("unexpected chunks: %d, after ctx error: %v", len(hook.chunks), hook.afterErr)
+	}
+}
@@ -0,0 +1,129 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrHedgeLost 对冲请求中另一次尝试先响应，本次尝试被取消
+var ErrHedgeLost = errors.New("hedge lost: another attempt responded first")
+
+// hedgeGroup 同一次对冲的所有尝试，先响应的胜出，其他未结束的尝试被取消
+type hedgeGroup struct {
+	mu       sync.Mutex
+	winner   *hedgeAttempt
+	attempts []*hedgeAttempt
+}
+
+// hedgeAttempt 对冲中的一次尝试，只有上游请求使用可取消的 ctx，hooks 仍使用原 ctx 完成记录和结算
+type hedgeAttempt struct {
+	group  *hedgeGroup
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   bool // 已结束，不会再被取消
+	lost   bool // 另一次尝试胜出，本次被取消
+}
+
+// fork 复制 c 作为一次对冲尝试
+func (g *hedgeGroup) fork(ctx context.Context, c *Context) *Context {
+	a := &hedgeAttempt{group: g}
+	a.ctx, a.cancel = context.WithCancel(ctx)
+	g.mu.Lock()
+	g.attempts = append(g.attempts, a)
+	g.mu.Unlock()
+
+	attempt := *c
+	attempt.hedge = a
+	return &attempt
+}
+
+// claimed 已有尝试胜出
+func (g *hedgeGroup) claimed() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.winner != nil
+}
+
+// won a 是否胜出
+func (g *hedgeGroup) won(a *hedgeAttempt) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.winner == a
+}
+
+// claim 成为胜出方并取消其他未结束的尝试，已有其他胜出方时返回 false
+func (a *hedgeAttempt) claim() bool {
+	a.group.mu.Lock()
+	defer a.group.mu.Unlock()
+	return a.claimLocked()
+}
+
+func (a *hedgeAttempt) claimLocked() bool {
+	g := a.group
+	if g.winner == nil && !a.lost {
+		g.winner = a
+		for _, o := range g.attempts {
+			if o != a && !o.done {
+				o.lost = true
+				o.cancel()
+			}
+		}
+	}
+	return g.winner == a
+}
+
+// settle 结束尝试，成功时成为胜出方，被取消时返回 ErrHedgeLost
+func (a *hedgeAttempt) settle(err error) error {
+	a.group.mu.Lock()
+	defer a.group.mu.Unlock()
+	if err == nil && !a.claimLocked() {
+		a.lost = true
+	}
+	a.done = true
+	a.cancel()
+	if a.lost {
+		return ErrHedgeLost
+	}
+	return err
+}
+
+// HedgeLost 对冲请求中被取消的一方，不计费也不计入 API Key 的失败
+func (c *Context) HedgeLost() bool {
+	if c.hedge == nil {
+		return false
+	}
+	c.hedge.group.mu.Lock()
+	defer c.hedge.group.mu.Unlock()
+	return c.hedge.lost
+}
+
+// Hedging 对冲请求中的一次尝试
+func (c *Context) Hedging() bool {
+	return c.hedge != nil
+}
+
+// hedgeable 二进制响应和消息连接直接写给客户端，不能同时发出两个请求
+func (c *Context) hedgeable() bool {
+	return !c.Hedging() && c.BinaryWriter == nil && c.ClientConn == nil
+}
+
+// bindHedgeRequest 上游请求使用对冲尝试的 ctx，被取消时立即中断
+func (c *Context) bindHedgeRequest() {
+	if c.hedge != nil && c.HTTPRequest != nil {
+		c.HTTPRequest = c.HTTPRequest.WithContext(c.hedge.ctx)
+	}
+}
+
+// claimHedge 流式响应在写入第一个 chunk 前确定胜出方
+func (c *Context) claimHedge() bool {
+	return c.hedge == nil || c.hedge.claim()
+}
+
+// settleHedge 结束对冲尝试，不是对冲请求时原样返回
+func (c *Context) settleHedge(err error) error {
+	if c.hedge == nil {
+		return err
+	}
+	return c.hedge.settle(err)
+}
@@ -1,11 +1,18 @@
 package core
 
+import "encoding/json"
+
 const (
 	ProviderCodeAnthropic string = "anthropic" // Anthropic
 	ProviderCodeDeepSeek  string = "deepseek"  // DeepSeek
 	ProviderCodeOpenAI    string = "openai"    // OpenAI
 	ProviderCodeZhipu     string = "zhipu"     // 智谱 - bigmodel
 	ProviderCodeMinimax   string = "minimax"   // MiniMax
+	ProviderCodeGemini    string = "gemini"    // Google Gemini
+	ProviderCodeAzure     string = "azure"     // Azure OpenAI
+	ProviderCodeBedrock   string = "bedrock"   // AWS Bedrock
+	ProviderCodeCohere    string = "cohere"    // Cohere
+	ProviderCodeJina      string = "jina"      // Jina AI
 )
 
 var AllProviderCodeList = []string{
@@ -14,6 +21,11 @@ var AllProviderCodeList = []string{
 	ProviderCodeOpenAI,
 	ProviderCodeZhipu,
 	ProviderCodeMinimax,
+	ProviderCodeGemini,
+	ProviderCodeAzure,
+	ProviderCodeBedrock,
+	ProviderCodeCohere,
+	ProviderCodeJina,
 }
 
 // Model 模型
@@ -23,14 +35,65 @@ type Model struct {
 	ProviderId      int64  // 提供商 ID
 	ProviderCode    string // 提供商Code
 	BaseUrl         string // 基础URL
+	ProviderConfig  string // 供应商扩展配置（JSON）
+	Transport       string // 供应商 HTTP 传输配置（JSON）
 	ApiKeyId        int64  // 提供商 API Key ID
 	ApiKeyEncrypted string // 加密后的 API Key
 
 	InputPrice        float64 // 输入价格
 	InputCachePrice   float64 // 输入缓存价格
 	OutputPrice       float64 // 输出价格
+	AudioInputPrice   float64 // 音频输入价格，0 表示与文本相同
+	AudioOutputPrice  float64 // 音频输出价格，0 表示与文本相同
 	TokenNum          int64   // Token 数量
 	PointsPerCurrency int64   // 每个货币点数
+
+	PricingMode    string          // 计费方式
+	UnitPrice      float64         // 按量计费默认单价
+	UnitPriceRules []UnitPriceRule // 按量计费单价规则
+	BatchDiscount  float64         // 批量任务折扣，0 表示不打折
+
+	RetryPolicy RetryPolicy // 重试策略，模型的配置覆盖供应商的配置
+	Pipeline    Pipeline    // hook 流水线，为空时使用执行器默认的 hooks
+}
+
+// 计费方式
+const (
+	PricingModeToken      = "token"       // 按 token
+	PricingModeImage      = "image"       // 按图片张数
+	PricingModeSecond     = "second"      // 按音频秒数
+	PricingModeCharacter  = "character"   // 按字符数
+	PricingModeSearchUnit = "search_unit" // 按搜索单元
+)
+
+// UnitPriceRule 按量计费单价规则，条件为空表示不限
+type UnitPriceRule struct {
+	Size    string  `json:"size,omitempty"`    // 图片尺寸，如 1024x1024
+	Quality string  `json:"quality,omitempty"` // 图片质量，如 standard、hd
+	Price   float64 `json:"price"`             // 单价
+}
+
+// IsUnitPricing 是否按量计费
+func (m *Model) IsUnitPricing() bool {
+	return m.PricingMode != "" && m.PricingMode != PricingModeToken
+}
+
+// GetUnitPrice 按顺序匹配第一条规则，没有匹配时使用默认单价
+func (m *Model) GetUnitPrice(size, quality string) float64 {
+	for _, rule := range m.UnitPriceRules {
+		if (rule.Size == "" || rule.Size == size) && (rule.Quality == "" || rule.Quality == quality) {
+			return rule.Price
+		}
+	}
+	return m.UnitPrice
+}
+
+// ParseProviderConfig 解析供应商扩展配置
+func (m *Model) ParseProviderConfig(v any) error {
+	if m.ProviderConfig == "" {
+		return nil
+	}
+	return json.Unmarshal([]byte(m.ProviderConfig), v)
 }
 
 // Usage 使用情况
@@ -39,4 +102,7 @@ type Usage struct {
 	PromptCachedTokens int64
 	CompletionTokens   int64
 	TotalTokens        int64
+
+	PromptAudioTokens     int64 // 输入中的音频 Token，包含在 PromptTokens 中
+	CompletionAudioTokens int64 // 输出中的音频 Token，包含在 CompletionTokens 中
 }
@@ -0,0 +1,255 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/samber/lo"
+)
+
+// PipelineDefault 流水线中表示执行器默认 hooks 的名称
+const PipelineDefault = "default"
+
+// 内置 hook 名称
+const (
+	HookRequest        = "request"
+	HookStreamWrite    = "stream_write"
+	HookOpenAIToken    = "openai_token"
+	HookEmbeddingToken = "embedding_token"
+	HookImage          = "image"
+	HookAudio          = "audio"
+	HookRerank         = "rerank"
+	HookBilling        = "billing"
+)
+
+// DefaultPipeline 接口默认的 hooks，按顺序执行 Before，反向执行 After
+// 免费接口不记录请求、不计费
+func DefaultPipeline(opts Options) []string {
+	switch opts.Endpoint {
+	case EndpointCountTokens:
+		return nil
+	case EndpointEmbeddings:
+		return []string{HookRequest, HookEmbeddingToken, HookBilling}
+	case EndpointImages:
+		return []string{HookRequest, HookImage, HookBilling}
+	case EndpointAudio, EndpointSpeech:
+		return []string{HookRequest, HookAudio, HookBilling}
+	case EndpointRerank:
+		return []string{HookRequest, HookRerank, HookBilling}
+	case EndpointRealtime:
+		// 会话结束时按累计用量结算
+		return []string{HookRequest, HookBilling}
+	}
+	if opts.IsStream {
+		return []string{HookRequest, HookStreamWrite, HookOpenAIToken, HookBilling}
+	}
+	return []string{HookRequest, HookOpenAIToken, HookBilling}
+}
+
+// builtinHooks 各接口默认流水线中的 hook，只能通过 default 引用
+var builtinHooks = []string{HookRequest, HookStreamWrite, HookOpenAIToken, HookEmbeddingToken, HookImage, HookAudio, HookRerank, HookBilling}
+
+// requiredHooks 不能去掉的内置 hook：去掉 stream_write 客户端收不到数据，去掉 request 计费没有请求 ID、熔断和负载均衡没有反馈
+var requiredHooks = []string{HookRequest, HookStreamWrite}
+
+// HookFactory 按配置创建 hook
+type HookFactory func(config json.RawMessage) (Hook, error)
+
+// HookSpec 流水线中的一个 hook
+type HookSpec struct {
+	Name     string          `json:"name"`               // 注册的 hook 名称，default 表示执行器默认的 hooks
+	Config   json.RawMessage `json:"config,omitempty"`   // hook 配置
+	Disabled bool            `json:"disabled,omitempty"` // 从默认的 hooks 中去掉，如内部模型不计费
+}
+
+// Pipeline hook 流水线，供应商和模型分别配置，模型的配置优先
+// 自定义 hook 只能加在 default 前后，未列出 default 时加在默认的 hooks 之后；内置 hook 只能通过 disabled 去掉，request、stream_write 不能去掉
+type Pipeline struct {
+	Hooks []HookSpec `json:"hooks,omitempty"`
+}
+
+// ParsePipeline 解析流水线 JSON，空值表示使用默认的 hooks
+func ParsePipeline(data string) (p *Pipeline, err error) {
+	p = &Pipeline{}
+	if strings.TrimSpace(data) == "" {
+		return
+	}
+	err = json.Unmarshal([]byte(data), p)
+	return
+}
+
+// IsZero 没有配置，使用默认的 hooks
+func (p Pipeline) IsZero() bool {
+	return len(p.Hooks) == 0
+}
+
+// Validate 校验流水线结构：名称不重复，default 最多一个，内置 hook 不能单独启用，只有计费和用量相关的可以去掉
+func (p Pipeline) Validate() error {
+	var names []string
+	for _, spec := range p.Hooks {
+		if spec.Name == "" {
+			return fmt.Errorf("hook name required")
+		}
+		if slices.Contains(names, spec.Name) {
+			return fmt.Errorf("hook %s duplicated", spec.Name)
+		}
+		names = append(names, spec.Name)
+		if spec.Name == PipelineDefault {
+			if spec.Disabled {
+				return fmt.Errorf("hook %s can not be disabled", PipelineDefault)
+			}
+			continue
+		}
+		if spec.Disabled && slices.Contains(requiredHooks, spec.Name) {
+			return fmt.Errorf("hook %s can not be disabled", spec.Name)
+		}
+		if !spec.Disabled && slices.Contains(builtinHooks, spec.Name) {
+			return fmt.Errorf("builtin hook %s is included by %s, only disabled is allowed", spec.Name, PipelineDefault)
+		}
+	}
+	return nil
+}
+
+// RegisterHook 注册 hook，名称与 Hook.Name 一致
+func (r *Registry) RegisterHook(name string, fn HookFactory) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.hooks[name]; ok {
+		panic(fmt.Errorf("hook %s already registered", name))
+	}
+	r.hooks[name] = fn
+}
+
+// NewExecutor 按接口默认的流水线创建执行器，供应商只需要提供 handler
+// 语音合成边读边写、实时会话按会话结算，都不重试；免费接口只透传一次
+func (r *Registry) NewExecutor(h Handler, opts Options) (Executor, error) {
+	hooks, err := r.DefaultHooks(opts)
+	if err != nil {
+		return nil, err
+	}
+	if opts.IsStream && opts.Endpoint.IsChat() {
+		return NewRetryExecutor(NewStreamExecutor(h, hooks...), opts), nil
+	}
+	base := NewExecutor(h, hooks...)
+	switch opts.Endpoint {
+	case EndpointSpeech, EndpointRealtime, EndpointCountTokens:
+		return base, nil
+	}
+	return NewRetryExecutor(base, opts), nil
+}
+
+// DefaultHooks 按名称查找接口默认流水线中的 hooks
+func (r *Registry) DefaultHooks(opts Options) (hooks []Hook, err error) {
+	for _, name := range DefaultPipeline(opts) {
+		var h Hook
+		if h, err = r.hook(nil, HookSpec{Name: name}); err != nil {
+			return
+		}
+		hooks = append(hooks, h)
+	}
+	return
+}
+
+// BuildHooks 按流水线组装 hooks，default 展开为执行器默认的 hooks 并去掉 disabled 的 hook
+// 创建的 hook 按模型缓存，模型 ID 为 0 时不缓存
+func (r *Registry) BuildHooks(modelId int64, p Pipeline, defaults []Hook) (hooks []Hook, err error) {
+	if p.IsZero() {
+		return defaults, nil
+	}
+	if err = p.Validate(); err != nil {
+		return
+	}
+	cache := r.pipelineCache(modelId, p)
+	var disabled []string
+	var specs []HookSpec
+	for _, spec := range p.Hooks {
+		if spec.Disabled {
+			disabled = append(disabled, spec.Name)
+		} else {
+			specs = append(specs, spec)
+		}
+	}
+	if !lo.ContainsBy(specs, func(spec HookSpec) bool { return spec.Name == PipelineDefault }) {
+		specs = append([]HookSpec{{Name: PipelineDefault}}, specs...)
+	}
+	for _, spec := range specs {
+		if spec.Name == PipelineDefault {
+			hooks = append(hooks, lo.Filter(defaults, func(h Hook, _ int) bool {
+				return !lo.Contains(disabled, h.Name())
+			})...)
+			continue
+		}
+		var h Hook
+		if h, err = r.hook(cache, spec); err != nil {
+			return
+		}
+		hooks = append(hooks, h)
+	}
+	return
+}
+
+// builtPipeline 按一个模型的流水线配置创建的 hooks
+type builtPipeline struct {
+	config string   // 流水线配置，修改后整体替换，旧的 hooks 随之释放
+	hooks  sync.Map // hook 名称 -> Hook
+}
+
+// pipelineCache 模型流水线的 hook 缓存，配置修改后重新创建
+func (r *Registry) pipelineCache(modelId int64, p Pipeline) *sync.Map {
+	if modelId == 0 {
+		return nil
+	}
+	data, _ := json.Marshal(p)
+	if v, ok := r.built.Load(modelId); ok && v.(*builtPipeline).config == string(data) {
+		return &v.(*builtPipeline).hooks
+	}
+	b := &builtPipeline{config: string(data)}
+	r.built.Store(modelId, b)
+	return &b.hooks
+}
+
+// hook 按名称和配置创建 hook，cache 不为空时同一流水线中只创建一次
+func (r *Registry) hook(cache *sync.Map, spec HookSpec) (Hook, error) {
+	if cache != nil {
+		if h, ok := cache.Load(spec.Name); ok {
+			return h.(Hook), nil
+		}
+	}
+	r.mu.RLock()
+	fn, ok := r.hooks[spec.Name]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("hook %s not found", spec.Name)
+	}
+	h, err := fn(spec.Config)
+	if err != nil {
+		return nil, fmt.Errorf("hook %s config invalid: %w", spec.Name, err)
+	}
+	if cache != nil {
+		cache.Store(spec.Name, h)
+	}
+	return h, nil
+}
+
+// ValidatePipeline 校验流水线结构，引用的 hook 都已注册且配置有效
+func (r *Registry) ValidatePipeline(p Pipeline) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	for _, spec := range p.Hooks {
+		if spec.Name == PipelineDefault {
+			continue
+		}
+		r.mu.RLock()
+		_, ok := r.hooks[spec.Name]
+		r.mu.RUnlock()
+		if !ok {
+			return fmt.Errorf("hook %s not found", spec.Name)
+		}
+	}
+	_, err := r.BuildHooks(0, p, nil)
+	return err
+}
@@ -0,0 +1,96 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/samber/lo"
+)
+
+type namedHook struct {
+	fakeHook
+	name string
+}
+
+func (h *namedHook) Name() string { return h.name }
+
+func TestBuildHooks(t *testing.T) {
+	r := &Registry{m: map[string]NewExecutorFunc{}, hooks: map[string]HookFactory{}}
+	r.RegisterHook("audit", func(config json.RawMessage) (Hook, error) {
+		var cfg struct {
+			Name string `json:"name"`
+		}
+		if err := json.Unmarshal(config, &cfg); err != nil || cfg.Name == "" {
+			return nil, errors.New("name required")
+		}
+		return &namedHook{name: cfg.Name}, nil
+	})
+	for _, name := range builtinHooks {
+		h := &namedHook{name: name}
+		r.RegisterHook(name, func(json.RawMessage) (Hook, error) { return h, nil })
+	}
+	defaults, err := r.DefaultHooks(Options{IsStream: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	stream := []string{"request", "stream_write", "openai_token", "billing"}
+
+	tests := []struct {
+		name     string
+		pipeline string
+		want     []string
+		wantErr  bool
+	}{
+		{"empty", ``, stream, false},
+		{"disable billing", `{"hooks":[{"name":"billing","disabled":true}]}`, []string{"request", "stream_write", "openai_token"}, false},
+		{"custom before default", `{"hooks":[{"name":"audit","config":{"name":"a"}},{"name":"default"}]}`, append([]string{"a"}, stream...), false},
+		{"custom after default", `{"hooks":[{"name":"audit","config":{"name":"a"}}]}`, append(slices.Clone(stream), "a"), false},
+		{"disable stream_write", `{"hooks":[{"name":"stream_write","disabled":true}]}`, nil, true},
+		{"disable request", `{"hooks":[{"name":"request","disabled":true}]}`, nil, true},
+		{"builtin only", `{"hooks":[{"name":"billing"}]}`, nil, true},
+		{"builtin twice", `{"hooks":[{"name":"default"},{"name":"billing"}]}`, nil, true},
+		{"duplicated", `{"hooks":[{"name":"audit","config":{"name":"a"}},{"name":"audit","config":{"name":"b"}}]}`, nil, true},
+		{"not registered", `{"hooks":[{"name":"unknown"}]}`, nil, true},
+		{"invalid config", `{"hooks":[{"name":"audit"}]}`, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := ParsePipeline(tt.pipeline)
+			if err != nil {
+				t.Fatal(err)
+			}
+			hooks, err := r.BuildHooks(0, *p, defaults)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			got := lo.Map(hooks, func(h Hook, _ int) string { return h.Name() })
+			if !tt.wantErr && !slices.Equal(got, tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if err := r.ValidatePipeline(Pipeline{Hooks: []HookSpec{{Name: "unknown", Disabled: true}}}); err == nil {
+		t.Fatal("disabled hook should be registered")
+	}
+
+	// 同一模型的 hook 只创建一次，配置修改后替换
+	build := func(config string) Hook {
+		p, _ := ParsePipeline(`{"hooks":[{"name":"audit","config":{"name":"` + config + `"}}]}`)
+		hooks, err := r.BuildHooks(1, *p, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return hooks[0]
+	}
+	if first := build("a"); build("a") != first || build("b").Name() != "b" {
+		t.Fatal("hooks should be cached per model config")
+	}
+	n := 0
+	r.built.Range(func(_, _ any) bool { n++; return true })
+	if n != 1 {
+		t.Fatalf("cached pipelines: %d, want 1", n)
+	}
+}
@@ -0,0 +1,52 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Protocol 接口协议
+type Protocol string
+
+const (
+	ProtocolOpenAI    Protocol = "openai"    // OpenAI Chat Completions
+	ProtocolAnthropic Protocol = "anthropic" // Anthropic Messages
+
+	ProtocolOpenAIResponses Protocol = "openai_responses" // OpenAI Responses
+	ProtocolGemini          Protocol = "gemini"           // Google Gemini generateContent，仅作为入站协议
+)
+
+// PathProtocol 根据请求路径判断入站协议
+func PathProtocol(path string) Protocol {
+	switch {
+	case strings.HasPrefix(path, "/v1/relay/anthropic/"):
+		return ProtocolAnthropic
+	case path == "/v1/responses":
+		return ProtocolOpenAIResponses
+	case strings.HasPrefix(path, "/v1beta/models/"):
+		return ProtocolGemini
+	default:
+		return ProtocolOpenAI
+	}
+}
+
+// TranslateFunc 将上游协议为 native 的处理器包装为入站协议 inbound 的处理器
+type TranslateFunc func(h Handler, native, inbound Protocol) (Handler, error)
+
+var translateFunc TranslateFunc
+
+// RegisterTranslator 注册协议转换
+func RegisterTranslator(fn TranslateFunc) {
+	translateFunc = fn
+}
+
+// Translate 协议一致时原样返回处理器，否则进行协议转换
+func Translate(h Handler, native, inbound Protocol) (Handler, error) {
+	if inbound == "" || inbound == native {
+		return h, nil
+	}
+	if translateFunc == nil {
+		return nil, fmt.Errorf("provider %s does not support %s protocol", h.Provider(), inbound)
+	}
+	return translateFunc(h, native, inbound)
+}
@@ -1,6 +1,7 @@
 package core
 
 import (
+	"context"
 	"fmt"
 	"sync"
 )
@@ -8,14 +9,22 @@ import (
 type NewExecutorFunc func(opts Options) (Executor, error)
 
 type Options struct {
-	IsStream    bool
-	IsAnthropic bool
-	Retry       int // 重试次数，0 表示不重试
+	IsStream bool
+	Protocol Protocol // 入站协议
+	Endpoint Endpoint // 接口类型，不同接口使用不同的执行链
+	Retry    int      // 重试次数，0 表示不重试
+	Failover Failover // 故障转移，为空时重试当前模型
 }
 
+// Failover 按请求的供应商和模型重新解析，排除已失败的模型和供应商 API Key
+type Failover func(ctx context.Context, c *Context, excludeModelIds, excludeApiKeyIds []int64) (*Model, error)
+
+// Registry 执行器和 hook 的注册表
 type Registry struct {
-	mu sync.RWMutex
-	m  map[string]NewExecutorFunc
+	mu    sync.RWMutex
+	m     map[string]NewExecutorFunc
+	hooks map[string]HookFactory
+	built sync.Map // 模型 ID -> *builtPipeline
 }
 
 func (r *Registry) Register(provider string, fn NewExecutorFunc) {
@@ -29,8 +38,8 @@ func (r *Registry) Register(provider string, fn NewExecutorFunc) {
 
 func (r *Registry) Get(provider string, opts Options) (Executor, error) {
 	r.mu.RLock()
-	defer r.mu.RUnlock()
 	fn, ok := r.m[provider]
+	r.mu.RUnlock()
 	if !ok {
 		return nil, fmt.Errorf("provider %s not found", provider)
 	}
@@ -38,5 +47,6 @@ func (r *Registry) Get(provider string, opts Options) (Executor, error) {
 }
 
 var ExecutorRegistry = &Registry{
-	m: make(map[string]NewExecutorFunc),
+	m:     make(map[string]NewExecutorFunc),
+	hooks: make(map[string]HookFactory),
 }
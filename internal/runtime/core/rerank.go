@@ -0,0 +1,32 @@
+package core
+
+import "encoding/json"
+
+// RerankRequest Cohere、Jina 兼容的重排序请求
+type RerankRequest struct {
+	Model           string            `json:"model"`
+	Query           string            `json:"query"`
+	Documents       []json.RawMessage `json:"documents"`
+	TopN            *int              `json:"top_n,omitempty"`
+	ReturnDocuments *bool             `json:"return_documents,omitempty"`
+}
+
+// DocumentTexts 文档可以是字符串或包含 text 字段的对象
+func (r *RerankRequest) DocumentTexts() []string {
+	texts := make([]string, 0, len(r.Documents))
+	for _, doc := range r.Documents {
+		var text string
+		if json.Unmarshal(doc, &text) != nil {
+			var obj struct {
+				Text string `json:"text"`
+			}
+			if json.Unmarshal(doc, &obj) == nil && obj.Text != "" {
+				text = obj.Text
+			} else {
+				text = string(doc)
+			}
+		}
+		texts = append(texts, text)
+	}
+	return texts
+}
@@ -0,0 +1,224 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/samber/lo"
+)
+
+const (
+	defaultRetryBaseDelay = 500 * time.Millisecond
+	defaultRetryMaxDelay  = 10 * time.Second
+)
+
+// RetryPolicy 重试策略，供应商和模型分别配置，未设置的字段使用上一级的配置
+type RetryPolicy struct {
+	MaxRetries  *int   `json:"max_retries,omitempty"`   // 最大重试次数，0 表示不重试
+	BaseDelayMs *int64 `json:"base_delay_ms,omitempty"` // 首次重试等待时间，之后按指数增长
+	MaxDelayMs  *int64 `json:"max_delay_ms,omitempty"`  // 最长等待时间，也是 Retry-After 的上限
+
+	HedgeDelayMs *int64 `json:"hedge_delay_ms,omitempty"` // 对冲等待时间，超过后向其他 API Key 或模型再发一个请求，0 表示不对冲
+}
+
+// ParseRetryPolicy 解析重试策略 JSON，空值表示不配置
+func ParseRetryPolicy(data string) (p *RetryPolicy, err error) {
+	p = &RetryPolicy{}
+	if strings.TrimSpace(data) == "" {
+		return
+	}
+	err = json.Unmarshal([]byte(data), p)
+	return
+}
+
+// Merge 用 o 中已设置的字段覆盖
+func (p RetryPolicy) Merge(o *RetryPolicy) RetryPolicy {
+	if o == nil {
+		return p
+	}
+	if o.MaxRetries != nil {
+		p.MaxRetries = o.MaxRetries
+	}
+	if o.BaseDelayMs != nil {
+		p.BaseDelayMs = o.BaseDelayMs
+	}
+	if o.MaxDelayMs != nil {
+		p.MaxDelayMs = o.MaxDelayMs
+	}
+	if o.HedgeDelayMs != nil {
+		p.HedgeDelayMs = o.HedgeDelayMs
+	}
+	return p
+}
+
+// HedgeDelay 对冲等待时间，未配置时不对冲
+func (p RetryPolicy) HedgeDelay() time.Duration {
+	if p.HedgeDelayMs == nil {
+		return 0
+	}
+	return max(time.Duration(*p.HedgeDelayMs)*time.Millisecond, 0)
+}
+
+// Retries 重试次数，未配置时使用 def
+func (p RetryPolicy) Retries(def int) int {
+	if p.MaxRetries == nil {
+		return def
+	}
+	return max(*p.MaxRetries, 0)
+}
+
+// Backoff 第 attempt 次失败后的等待时间，指数退避加随机抖动，上游返回 Retry-After 时不小于该值
+func (p RetryPolicy) Backoff(attempt int, resp *http.Response) time.Duration {
+	base, maxDelay := defaultRetryBaseDelay, defaultRetryMaxDelay
+	if p.BaseDelayMs != nil {
+		base = time.Duration(*p.BaseDelayMs) * time.Millisecond
+	}
+	if p.MaxDelayMs != nil {
+		maxDelay = time.Duration(*p.MaxDelayMs) * time.Millisecond
+	}
+	delay := maxDelay
+	if attempt < 31 {
+		delay = min(base<<(attempt-1), maxDelay)
+	}
+	// 在 [delay/2, delay] 之间随机，避免同时重试
+	if delay > 0 {
+		delay = delay/2 + rand.N(delay/2+1)
+	}
+	if after := retryAfter(resp); after > delay {
+		delay = min(after, maxDelay)
+	}
+	return delay
+}
+
+// retryAfter 解析上游 Retry-After，支持 retry-after-ms、秒数和 HTTP 日期
+func retryAfter(resp *http.Response) time.Duration {
+	if resp == nil {
+		return 0
+	}
+	if v := resp.Header.Get("Retry-After-Ms"); v != "" {
+		if ms, err := strconv.ParseFloat(v, 64); err == nil && ms > 0 {
+			return time.Duration(ms * float64(time.Millisecond))
+		}
+	}
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return 0
+	}
+	if seconds, err := strconv.ParseFloat(v, 64); err == nil {
+		return max(time.Duration(seconds*float64(time.Second)), 0)
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return max(time.Until(t), 0)
+	}
+	return 0
+}
+
+// LocalError 发起上游请求前的本地错误，如请求体转换失败、配置或凭证解析失败
+// 换 API Key 或供应商重试也不会成功，也不是 API Key 的问题
+type LocalError struct {
+	Err error
+}
+
+func (e *LocalError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *LocalError) Unwrap() error {
+	return e.Err
+}
+
+// IsLocalError 错误发生在发起上游请求之前
+func IsLocalError(err error) bool {
+	var e *LocalError
+	return errors.As(err, &e)
+}
+
+// isRetryable 网络错误、408、429、5xx 可重试；其他 4xx 是请求本身的问题，余额不足重试也不会成功，客户端取消时不再重试
+// 流式响应已经向客户端写入数据后不能重试；成功响应只在流式首个 chunk 前中断时重试，解析响应失败重试也不会成功
+// 发起上游请求前的本地错误不重试
+func isRetryable(ctx context.Context, c *Context) bool {
+	if ctx.Err() != nil || c.StreamStarted || IsLocalError(c.LastErr) {
+		return false
+	}
+	if c.HTTPResponse == nil {
+		return true
+	}
+	switch code := c.HTTPResponse.StatusCode; {
+	case code < http.StatusBadRequest:
+		return c.IsStream
+	case code == http.StatusTooManyRequests:
+		return !isQuotaExceeded(c.RawResponse)
+	case code == http.StatusRequestTimeout, code >= http.StatusInternalServerError:
+		return true
+	}
+	return false
+}
+
+// isQuotaExceeded 供应商账户额度或余额不足，如 OpenAI 的 insufficient_quota
+func isQuotaExceeded(body []byte) bool {
+	return bytes.Contains(body, []byte("insufficient_quota")) || bytes.Contains(bytes.ToLower(body), []byte("insufficient balance"))
+}
+
+// upstreamError OpenAI、Anthropic、Gemini 错误响应的公共字段
+type upstreamError struct {
+	Error struct {
+		Type    string `json:"type"`
+		Code    any    `json:"code"` // OpenAI 为字符串，Gemini 为状态码
+		Message string `json:"message"`
+		Status  string `json:"status"`
+	} `json:"error"`
+}
+
+// KeyRejectedReason 上游因 API Key 无效、无权限或余额不足拒绝请求时返回原因，否则返回空
+// 如 401、403、OpenAI 的 insufficient_quota、invalid_api_key，Anthropic 的 authentication_error、permission_error
+func KeyRejectedReason(resp *http.Response, body []byte) string {
+	if resp == nil || resp.StatusCode < http.StatusBadRequest {
+		return ""
+	}
+	var e upstreamError
+	_ = json.Unmarshal(body, &e)
+	code, _ := e.Error.Code.(string)
+	var kind string
+	switch {
+	case isQuotaExceeded(body):
+		kind = "insufficient_quota"
+	case lo.Contains([]string{"authentication_error", "permission_error", "invalid_api_key"}, e.Error.Type):
+		kind = e.Error.Type
+	case code == "invalid_api_key":
+		kind = code
+	case bytes.Contains(body, []byte("API_KEY_INVALID")):
+		kind = "api_key_invalid"
+	case resp.StatusCode == http.StatusUnauthorized, resp.StatusCode == http.StatusForbidden:
+		kind = lo.CoalesceOrEmpty(e.Error.Type, e.Error.Status, http.StatusText(resp.StatusCode))
+	default:
+		return ""
+	}
+	reason := fmt.Sprintf("%d %s: %s", resp.StatusCode, kind, e.Error.Message)
+	if r := []rune(reason); len(r) > 255 {
+		reason = string(r[:255])
+	}
+	return strings.TrimSuffix(reason, ": ")
+}
+
+// sleep 等待 d，ctx 取消时提前返回
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -1,5 +1,10 @@
 package core
 
+import (
+	"encoding/json"
+	"net/http"
+)
+
 // Stream 流式处理
 type Stream interface {
 	Recv() (*StreamChunk, error)
@@ -8,13 +13,48 @@ type Stream interface {
 
 // StreamChunk 流式处理chunk
 type StreamChunk struct {
+	Event  string // SSE 事件名，Anthropic 协议使用
 	Data   string
 	Finish bool
 }
 
+// ErrorChunk 流式响应开始后出错时发送给客户端的错误事件，格式与入站协议一致
+func ErrorChunk(protocol Protocol, err error) *StreamChunk {
+	var chunk StreamChunk
+	var v any
+	switch protocol {
+	case ProtocolAnthropic:
+		chunk.Event = "error"
+		v = map[string]any{"type": "error", "error": map[string]any{"type": "api_error", "message": err.Error()}}
+	case ProtocolOpenAIResponses:
+		chunk.Event = "error"
+		v = map[string]any{"type": "error", "code": "server_error", "message": err.Error(), "param": nil}
+	case ProtocolGemini:
+		v = map[string]any{"error": map[string]any{"code": http.StatusInternalServerError, "message": err.Error(), "status": "INTERNAL"}}
+	default:
+		v = map[string]any{"error": map[string]any{"type": "server_error", "message": err.Error(), "code": nil}}
+	}
+	data, _ := json.Marshal(v)
+	chunk.Data = string(data)
+	return &chunk
+}
+
 // StreamWriter 流式写入器
 type StreamWriter interface {
 	Open() error
 	Write(chunk *StreamChunk) error
 	Close() error
 }
+
+// BinaryWriter 二进制响应写入器，上游响应体边读边写
+type BinaryWriter interface {
+	WriteHeader(statusCode int, header http.Header)
+	Write(p []byte) (int, error)
+}
+
+// MessageConn 双向消息连接，消息类型与 WebSocket 一致
+type MessageConn interface {
+	ReadMessage() (messageType int, data []byte, err error)
+	WriteMessage(messageType int, data []byte) error
+	Close() error
+}
@@ -20,54 +20,72 @@ func NewStreamExecutor(h Handler, hooks ...Hook) Executor {
 	}
 }
 
-// Execute 执行流式处理
-func (e *streamExecutor) Execute(ctx context.Context, c *Context) error {
+// Execute 执行流式处理，出错时先通知 hooks 再执行 After，记录失败或取消并结算
+func (e *streamExecutor) Execute(ctx context.Context, c *Context) (err error) {
 	// hooks before
-	e.callHooksBefore(ctx, c)
+	hooks := e.pipeline(c)
+	e.callHooksBefore(ctx, c, hooks)
 
+	err = c.settleHedge(e.execute(ctx, c, hooks))
+	if err != nil {
+		c.LastErr = err
+		c.Cancelled = ctx.Err() != nil
+		e.callOnError(ctx, c, hooks, err)
+	}
+
+	// hooks after（反向）
+	e.callHooksAfter(ctx, c, hooks)
+	return
+}
+
+func (e *streamExecutor) execute(ctx context.Context, c *Context, hooks []Hook) (err error) {
 	// before request
 	log.Debugf("provider %s, model: %s, before request...", e.handler.Provider(), c.CurrentModel.ModelCode)
-	err := e.handler.BeforeRequest(ctx, c)
-	if err != nil {
+	if err = e.handler.BeforeRequest(ctx, c); err != nil {
 		log.Errorf("provider %s before request error: %v", e.handler.Provider(), err)
-		return err
+		return &LocalError{Err: err}
 	}
+	c.bindHedgeRequest()
 
 	// do request
 	log.Debugf("provider %s, model: %s, do request...", e.handler.Provider(), c.CurrentModel.ModelCode)
 	stream, err := e.handler.DoStream(ctx, c)
 	if err != nil {
 		log.Errorf("provider %s do stream error: %v", e.handler.Provider(), err)
-		return err
+		return
 	}
 	defer stream.Close()
 
 	for {
+		// 客户端断开后停止读取，上游请求随 ctx 取消
+		if err = ctx.Err(); err != nil {
+			return
+		}
 		chunk, sErr := stream.Recv()
 		if sErr != nil && sErr != io.EOF {
-			e.callOnError(ctx, c, sErr)
+			log.Errorf("provider %s recv stream error: %v", e.handler.Provider(), sErr)
 			return sErr
 		}
+		// 对冲请求中另一次尝试已经开始写入
+		if !c.claimHedge() {
+			return ErrHedgeLost
+		}
 
-		for _, h := range e.hooks {
-			if err := h.OnChunk(ctx, c, chunk); err != nil {
+		for _, h := range hooks {
+			if err = h.OnChunk(ctx, c, chunk); err != nil {
 				log.Errorf("hook %s on chunk error: %v", h.Name(), err)
-				return err
+				return
 			}
 		}
 
 		if sErr == io.EOF {
-			break
+			return nil
 		}
 	}
-
-	// hooks after（反向）
-	e.callHooksAfter(ctx, c)
-	return nil
 }
 
-func (e *streamExecutor) callOnError(ctx context.Context, c *Context, err error) {
-	for _, h := range e.hooks {
+func (e *streamExecutor) callOnError(ctx context.Context, c *Context, hooks []Hook, err error) {
+	for _, h := range hooks {
 		h.OnError(ctx, c, err)
 	}
 }
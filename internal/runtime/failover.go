@@ -0,0 +1,29 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+
+	"github.com/modelgate/modelgate/internal/relay"
+	"github.com/modelgate/modelgate/internal/relay/model"
+	"github.com/modelgate/modelgate/internal/runtime/core"
+)
+
+// newFailover 按请求的供应商和模型重新解析，可能切换到其他供应商或下一优先级
+func newFailover(service relay.Service) core.Failover {
+	return func(ctx context.Context, c *core.Context, excludeModelIds, excludeApiKeyIds []int64) (*core.Model, error) {
+		if c.ModelCode == "" {
+			return nil, errors.New("request model is empty")
+		}
+		info, err := service.ResolveModel(ctx, &model.ResolveModelRequest{
+			ProviderCode:     c.ProviderCode,
+			ModelCode:        c.ModelCode,
+			ExcludeModelIds:  excludeModelIds,
+			ExcludeApiKeyIds: excludeApiKeyIds,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return info.ToCoreModel(), nil
+	}
+}
@@ -0,0 +1,104 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"math"
+	"unicode/utf8"
+
+	"github.com/samber/do/v2"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/modelgate/modelgate/internal/runtime/core"
+	"github.com/modelgate/modelgate/pkg/utils"
+)
+
+// defaultAudioByteRate 无法解析音频头时按 128kbps 估算时长
+const defaultAudioByteRate = 128 * 1000 / 8
+
+// AudioHook 计算语音接口按量计费的数量和单价：转写、翻译按音频秒数，语音合成按字符数
+type AudioHook struct {
+}
+
+var _ core.Hook = (*AudioHook)(nil)
+
+func NewAudioHook(i do.Injector) (*AudioHook, error) {
+	return &AudioHook{}, nil
+}
+
+func (h *AudioHook) Name() string {
+	return core.HookAudio
+}
+
+// Before 执行前，转写按上传文件估算时长，实际时长由供应商处理器根据响应更新
+func (h *AudioHook) Before(ctx context.Context, c *core.Context) (err error) {
+	if c.CurrentModel == nil {
+		err = errors.New("model info is nil")
+		return
+	}
+	c.UnitPrice = c.CurrentModel.GetUnitPrice("", "")
+	if c.Endpoint == core.EndpointSpeech {
+		var reqBody struct {
+			Input string `json:"input"`
+		}
+		if err = json.Unmarshal(c.InputBody, &reqBody); err != nil {
+			return
+		}
+		c.Units = int64(utf8.RuneCountInString(reqBody.Input))
+		if c.PromptTokens, err = countTokenText(c.CurrentModel.ModelCode, reqBody.Input); err != nil {
+			return
+		}
+		log.Infof("speech characters: %d, unit price: %v", c.Units, c.UnitPrice)
+		return
+	}
+	if !utils.IsMultipart(c.ContentType) {
+		err = errors.New("audio request must be multipart/form-data")
+		return
+	}
+	_, data, err := utils.MultipartFile(c.ContentType, c.InputBody, "file")
+	if err != nil {
+		return
+	}
+	c.Units = int64(math.Ceil(audioSeconds(data)))
+	log.Infof("audio estimated seconds: %d, unit price: %v", c.Units, c.UnitPrice)
+	return
+}
+
+// audioSeconds 估算音频时长，WAV 按头部字节率计算，其他格式按默认码率估算
+func audioSeconds(data []byte) float64 {
+	if len(data) >= 12 && bytes.Equal(data[0:4], []byte("RIFF")) && bytes.Equal(data[8:12], []byte("WAVE")) {
+		var byteRate, dataSize uint32
+		for pos := 12; pos+8 <= len(data); {
+			id, size := string(data[pos:pos+4]), binary.LittleEndian.Uint32(data[pos+4:pos+8])
+			switch {
+			case id == "fmt " && pos+20 <= len(data):
+				byteRate = binary.LittleEndian.Uint32(data[pos+16 : pos+20])
+			case id == "data":
+				dataSize = min(size, uint32(len(data)-pos-8))
+			}
+			if dataSize > 0 || int(size) > len(data) {
+				break
+			}
+			pos += 8 + int(size) + int(size%2)
+		}
+		if byteRate > 0 && dataSize > 0 {
+			return float64(dataSize) / float64(byteRate)
+		}
+	}
+	return float64(len(data)) / defaultAudioByteRate
+}
+
+// After 执行后
+func (h *AudioHook) After(ctx context.Context, c *core.Context) (err error) {
+	return
+}
+
+func (h *AudioHook) OnChunk(ctx context.Context, c *core.Context, chunk *core.StreamChunk) (err error) {
+	return
+}
+
+func (h *AudioHook) OnError(ctx context.Context, c *core.Context, err error) {
+}
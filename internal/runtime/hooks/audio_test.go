@@ -0,0 +1,29 @@
+package hooks
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+func TestAudioSeconds(t *testing.T) {
+	// 16kHz 单声道 16bit，2 秒
+	const byteRate, dataSize = 32000, 64000
+	buf := new(bytes.Buffer)
+	buf.WriteString("RIFF")
+	_ = binary.Write(buf, binary.LittleEndian, uint32(36+dataSize))
+	buf.WriteString("WAVEfmt ")
+	for _, v := range []any{uint32(16), uint16(1), uint16(1), uint32(16000), uint32(byteRate), uint16(2), uint16(16)} {
+		_ = binary.Write(buf, binary.LittleEndian, v)
+	}
+	buf.WriteString("data")
+	_ = binary.Write(buf, binary.LittleEndian, uint32(dataSize))
+	buf.Write(make([]byte, dataSize))
+
+	if got := audioSeconds(buf.Bytes()); got != 2 {
+		t.Fatalf("wav: got %v, want 2", got)
+	}
+	if got := audioSeconds(make([]byte, defaultAudioByteRate*3)); got != 3 {
+		t.Fatalf("mp3: got %v, want 3", got)
+	}
+}
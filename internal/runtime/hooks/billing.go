@@ -3,8 +3,10 @@ package hooks
 import (
 	"context"
 	"math"
+	"net/http"
 
 	"github.com/samber/do/v2"
+	"github.com/samber/lo"
 	log "github.com/sirupsen/logrus"
 
 	"github.com/modelgate/modelgate/internal/relay"
@@ -12,6 +14,9 @@ import (
 	"github.com/modelgate/modelgate/internal/runtime/core"
 )
 
+// realtimeReserveTokens Realtime 会话预扣的输出 token 数，会话中始终保留这部分余额
+const realtimeReserveTokens = 4096
+
 type BillingHook struct {
 	service relay.Service
 }
@@ -25,14 +30,28 @@ func NewBillingHook(i do.Injector) (*BillingHook, error) {
 }
 
 func (h *BillingHook) Name() string {
-	return "billing"
+	return core.HookBilling
 }
 
 // Before 预扣款
 func (h *BillingHook) Before(ctx context.Context, c *core.Context) (err error) {
 	modelInfo := c.CurrentModel
-	cost := int64(math.Ceil(modelInfo.InputPrice * float64(c.PromptTokens) * float64(modelInfo.PointsPerCurrency) / float64(modelInfo.TokenNum)))
+	// Realtime 会话在升级连接前已预扣，会话中每次响应结束时结算
+	if c.Endpoint == core.EndpointRealtime {
+		c.Settle = h.settleSession
+		return
+	}
+	var cost int64
+	if modelInfo.IsUnitPricing() {
+		cost = unitCost(c)
+	} else {
+		cost = tokenCost(modelInfo, modelInfo.InputPrice, int64(c.PromptTokens))
+	}
+	cost = batchCost(c, cost)
 	log.Infof("prepay cost: %d", cost)
+	if cost <= 0 {
+		return
+	}
 
 	_, err = h.service.DeductBalance(ctx, c.AccountId, cost, c.RequestId, model.LedgerTypeConsume, "reserve")
 	if err != nil {
@@ -45,24 +64,15 @@ func (h *BillingHook) Before(ctx context.Context, c *core.Context) (err error) {
 // After 扣款
 func (h *BillingHook) After(ctx context.Context, c *core.Context) (err error) {
 	modelInfo := c.CurrentModel
-
-	var promptTokens int64
-	var promptCacheTokens int64
-	var completionTokens int64
-	if c.Usage != nil {
-		promptTokens = int64(c.Usage.PromptTokens)
-		promptCacheTokens = int64(c.Usage.PromptCachedTokens)
-		completionTokens = int64(c.Usage.CompletionTokens)
-	} else {
-		promptTokens = int64(c.PromptTokens)
-		completionTokens = int64(c.CompletionTokens)
+	// 对冲请求中被取消的一方不计费，退回预扣
+	if c.HedgeLost() {
+		if c.PreCost > 0 {
+			_, err = h.service.AddBalance(ctx, c.AccountId, c.PreCost, c.RequestId, model.LedgerTypeRefund, "hedge")
+		}
+		return
 	}
 
-	totalCost := int64(math.Ceil(modelInfo.InputPrice * float64(promptTokens) * float64(modelInfo.PointsPerCurrency) / float64(modelInfo.TokenNum)))
-	if promptCacheTokens > 0 {
-		totalCost += int64(math.Ceil(modelInfo.InputCachePrice * float64(promptCacheTokens) * float64(modelInfo.PointsPerCurrency) / float64(modelInfo.TokenNum)))
-	}
-	totalCost += int64(math.Ceil(modelInfo.OutputPrice * float64(completionTokens) * float64(modelInfo.PointsPerCurrency) / float64(modelInfo.TokenNum)))
+	totalCost := usageCost(c)
 	log.Infof("total cost: %d", totalCost)
 
 	if v := totalCost - c.PreCost; v > 0 {
@@ -84,6 +94,104 @@ func (h *BillingHook) After(ctx context.Context, c *core.Context) (err error) {
 	return
 }
 
+// usageCost 按已有用量计算费用
+func usageCost(c *core.Context) int64 {
+	modelInfo := c.CurrentModel
+	var promptTokens int64
+	var promptCacheTokens int64
+	var completionTokens int64
+	var promptAudioTokens int64
+	var completionAudioTokens int64
+	if c.Usage != nil {
+		promptTokens = int64(c.Usage.PromptTokens)
+		promptCacheTokens = int64(c.Usage.PromptCachedTokens)
+		completionTokens = int64(c.Usage.CompletionTokens)
+		promptAudioTokens = c.Usage.PromptAudioTokens
+		completionAudioTokens = c.Usage.CompletionAudioTokens
+	} else {
+		promptTokens = int64(c.PromptTokens)
+		completionTokens = int64(c.CompletionTokens)
+	}
+	// 客户端断开时上游没有返回最终用量，输出按已收到的内容估算
+	if c.Cancelled {
+		completionTokens = max(completionTokens, int64(c.CompletionTokens))
+	}
+	// embeddings、rerank 只按输入价格计费
+	if c.Endpoint == core.EndpointEmbeddings || c.Endpoint == core.EndpointRerank {
+		promptCacheTokens, completionTokens = 0, 0
+	}
+
+	var totalCost int64
+	if modelInfo.IsUnitPricing() {
+		// 请求失败时没有生成结果，不计费，预扣全部退回
+		if !unitFailed(c) {
+			totalCost = unitCost(c)
+		}
+	} else {
+		totalCost = tokenCost(modelInfo, modelInfo.InputPrice, promptTokens-promptAudioTokens)
+		if promptCacheTokens > 0 {
+			totalCost += tokenCost(modelInfo, modelInfo.InputCachePrice, promptCacheTokens)
+		}
+		totalCost += tokenCost(modelInfo, modelInfo.OutputPrice, completionTokens-completionAudioTokens)
+		// 音频 Token 未配置音频价格时按文本价格计费
+		if promptAudioTokens > 0 {
+			totalCost += tokenCost(modelInfo, lo.CoalesceOrEmpty(modelInfo.AudioInputPrice, modelInfo.InputPrice), promptAudioTokens)
+		}
+		if completionAudioTokens > 0 {
+			totalCost += tokenCost(modelInfo, lo.CoalesceOrEmpty(modelInfo.AudioOutputPrice, modelInfo.OutputPrice), completionAudioTokens)
+		}
+	}
+	return batchCost(c, totalCost)
+}
+
+// RealtimeReserve Realtime 会话开始前需要预扣的点数
+func RealtimeReserve(m *core.Model) int64 {
+	return tokenCost(m, max(m.OutputPrice, m.AudioOutputPrice), realtimeReserveTokens)
+}
+
+// settleSession 按已有用量扣款，并保留下一次响应的预扣，余额不足时结束会话
+func (h *BillingHook) settleSession(ctx context.Context, c *core.Context) (err error) {
+	target := usageCost(c) + RealtimeReserve(c.CurrentModel)
+	if v := target - c.PreCost; v > 0 {
+		if _, err = h.service.DeductBalance(ctx, c.AccountId, v, c.RequestId, model.LedgerTypeConsume, "realtime"); err != nil {
+			return
+		}
+		c.PreCost = target
+	}
+	return
+}
+
+// tokenCost 按 token 计费：价格 * token 数 / 价格对应的 token 数
+func tokenCost(m *core.Model, price float64, tokens int64) int64 {
+	return int64(math.Ceil(price * float64(tokens) * float64(m.PointsPerCurrency) / float64(m.TokenNum)))
+}
+
+// unitCost 按量计费：数量 * 单价
+func unitCost(c *core.Context) int64 {
+	return int64(math.Ceil(c.UnitPrice * float64(c.Units) * float64(c.CurrentModel.PointsPerCurrency)))
+}
+
+// unitFailed 按量计费的请求没有成功结果，Units 仍是请求中的预估值
+// 上游已成功返回、客户端中途断开时，已返回的部分照常计费
+func unitFailed(c *core.Context) bool {
+	if c.LastErr == nil {
+		return false
+	}
+	if c.HTTPResponse == nil || c.HTTPResponse.StatusCode >= http.StatusBadRequest {
+		return true
+	}
+	return !c.Cancelled
+}
+
+// batchCost 批量任务按模型配置的折扣计费
+func batchCost(c *core.Context, cost int64) int64 {
+	discount := c.CurrentModel.BatchDiscount
+	if !c.IsBatch || discount <= 0 || discount >= 1 {
+		return cost
+	}
+	return int64(math.Ceil(float64(cost) * discount))
+}
+
 func (h *BillingHook) OnChunk(ctx context.Context, c *core.Context, chunk *core.StreamChunk) (err error) {
 	return
 }
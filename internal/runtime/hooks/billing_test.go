@@ -0,0 +1,62 @@
+package hooks
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"go.uber.org/mock/gomock"
+
+	"github.com/modelgate/modelgate/internal/relay"
+	"github.com/modelgate/modelgate/internal/relay/model"
+	"github.com/modelgate/modelgate/internal/runtime/core"
+)
+
+func TestBatchCost(t *testing.T) {
+	tests := []struct {
+		isBatch  bool
+		discount float64
+		cost     int64
+		want     int64
+	}{
+		{false, 0.5, 1000, 1000},
+		{true, 0, 1000, 1000},
+		{true, 1, 1000, 1000},
+		{true, 0.5, 1000, 500},
+		{true, 0.5, 3, 2},
+	}
+	for _, tt := range tests {
+		c := &core.Context{IsBatch: tt.isBatch, CurrentModel: &core.Model{BatchDiscount: tt.discount}}
+		if got := batchCost(c, tt.cost); got != tt.want {
+			t.Errorf("batchCost(%v, %v, %d) = %d, want %d", tt.isBatch, tt.discount, tt.cost, got, tt.want)
+		}
+	}
+}
+
+func TestBillingFailedImage(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+	service := relay.NewMockService(ctl)
+	h := &BillingHook{service: service}
+	c := &core.Context{
+		AccountId:    1,
+		RequestId:    2,
+		Endpoint:     core.EndpointImages,
+		CurrentModel: &core.Model{PricingMode: core.PricingModeImage, PointsPerCurrency: 100},
+		Units:        4,
+		UnitPrice:    0.5,
+		PreCost:      200,
+		HTTPResponse: &http.Response{StatusCode: http.StatusBadRequest},
+		LastErr:      errors.New("content policy violation"),
+	}
+	// 上游拒绝时不计费，预扣全部退回
+	service.EXPECT().AddBalance(gomock.Any(), int64(1), int64(200), int64(2), model.LedgerTypeRefund, "settle").Return(&model.Ledger{}, nil)
+	service.EXPECT().AddPointUsage(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), int64(0)).Return(nil)
+	if err := h.After(context.Background(), c); err != nil {
+		t.Fatal(err)
+	}
+	if c.TotalCost != 0 {
+		t.Fatalf("total cost: %d", c.TotalCost)
+	}
+}
@@ -0,0 +1,31 @@
+package hooks
+
+import (
+	"encoding/json"
+
+	"github.com/modelgate/modelgate/internal/runtime/core"
+)
+
+// EstimateInputTokens 本地估算输入 Token，上游不支持 count_tokens 时使用
+func EstimateInputTokens(c *core.Context) (num int, err error) {
+	text, err := inputTokensText(c)
+	if err != nil {
+		return
+	}
+	return countTokenText(c.CurrentModel.ModelCode, text)
+}
+
+// inputTokensText 输入文本，工具定义也计入输入
+func inputTokensText(c *core.Context) (string, error) {
+	text, err := (&OpenAITokenHook{}).promptText(c)
+	if err != nil {
+		return "", err
+	}
+	var reqBody struct {
+		Tools json.RawMessage `json:"tools"`
+	}
+	if err = json.Unmarshal(c.InputBody, &reqBody); err != nil {
+		return "", err
+	}
+	return text + string(reqBody.Tools), nil
+}
@@ -0,0 +1,21 @@
+package hooks
+
+import (
+	"testing"
+
+	"github.com/modelgate/modelgate/internal/runtime/core"
+)
+
+func TestInputTokensText(t *testing.T) {
+	c := &core.Context{
+		Protocol:  core.ProtocolAnthropic,
+		InputBody: []byte(`{"system":"Be brief.","messages":[{"role":"user","content":[{"type":"text","text":"Hello"}]}],"tools":[{"name":"get_weather"}]}`),
+	}
+	got, err := inputTokensText(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `Be brief.Hello[{"name":"get_weather"}]`; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,90 @@
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/samber/do/v2"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/modelgate/modelgate/internal/runtime/core"
+)
+
+// EmbeddingTokenHook 计算 Embeddings 输入 Token
+type EmbeddingTokenHook struct {
+}
+
+var _ core.Hook = (*EmbeddingTokenHook)(nil)
+
+func NewEmbeddingTokenHook(i do.Injector) (*EmbeddingTokenHook, error) {
+	return &EmbeddingTokenHook{}, nil
+}
+
+func (h *EmbeddingTokenHook) Name() string {
+	return core.HookEmbeddingToken
+}
+
+// Before 执行前
+func (h *EmbeddingTokenHook) Before(ctx context.Context, c *core.Context) (err error) {
+	if c.CurrentModel == nil {
+		err = errors.New("model info is nil")
+		return
+	}
+	var reqBody struct {
+		Input json.RawMessage `json:"input"`
+	}
+	if err = json.Unmarshal(c.InputBody, &reqBody); err != nil {
+		return
+	}
+	tokenNum, err := countEmbeddingInput(c.CurrentModel.ModelCode, reqBody.Input)
+	if err != nil {
+		return
+	}
+	c.PromptTokens = tokenNum
+	log.Info("embedding prompt token num: ", tokenNum)
+	return
+}
+
+// countEmbeddingInput input 可以是字符串、字符串数组、token 数组或 token 数组的数组
+func countEmbeddingInput(model string, input json.RawMessage) (num int, err error) {
+	var text string
+	if err = json.Unmarshal(input, &text); err == nil {
+		return countTokenText(model, text)
+	}
+	var tokens []int64
+	if err = json.Unmarshal(input, &tokens); err == nil {
+		return len(tokens), nil
+	}
+	var texts []string
+	if err = json.Unmarshal(input, &texts); err == nil {
+		for _, text := range texts {
+			var n int
+			if n, err = countTokenText(model, text); err != nil {
+				return
+			}
+			num += n
+		}
+		return
+	}
+	var tokenList [][]int64
+	if err = json.Unmarshal(input, &tokenList); err != nil {
+		return 0, errors.New("invalid embedding input")
+	}
+	for _, tokens := range tokenList {
+		num += len(tokens)
+	}
+	return
+}
+
+// After 执行后，usage 由供应商处理器解析
+func (h *EmbeddingTokenHook) After(ctx context.Context, c *core.Context) (err error) {
+	return
+}
+
+func (h *EmbeddingTokenHook) OnChunk(ctx context.Context, c *core.Context, chunk *core.StreamChunk) (err error) {
+	return
+}
+
+func (h *EmbeddingTokenHook) OnError(ctx context.Context, c *core.Context, err error) {
+}
@@ -0,0 +1,29 @@
+package hooks
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestCountEmbeddingInputTokens(t *testing.T) {
+	cases := []struct {
+		input string
+		want  int
+	}{
+		{`[1, 2, 3]`, 3},
+		{`[[1, 2], [3, 4, 5]]`, 5},
+		{`[]`, 0},
+	}
+	for _, tc := range cases {
+		got, err := countEmbeddingInput("text-embedding-3-small", json.RawMessage(tc.input))
+		if err != nil {
+			t.Fatalf("input %s: %v", tc.input, err)
+		}
+		if got != tc.want {
+			t.Fatalf("input %s: got %d, want %d", tc.input, got, tc.want)
+		}
+	}
+	if _, err := countEmbeddingInput("text-embedding-3-small", json.RawMessage(`{"a": 1}`)); err == nil {
+		t.Fatal("expected error for invalid input")
+	}
+}
@@ -0,0 +1,84 @@
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+
+	"github.com/samber/do/v2"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/modelgate/modelgate/internal/runtime/core"
+	"github.com/modelgate/modelgate/pkg/utils"
+)
+
+// ImageHook 解析图片生成参数，计算按张计费的数量和单价
+type ImageHook struct {
+}
+
+var _ core.Hook = (*ImageHook)(nil)
+
+func NewImageHook(i do.Injector) (*ImageHook, error) {
+	return &ImageHook{}, nil
+}
+
+func (h *ImageHook) Name() string {
+	return core.HookImage
+}
+
+// Before 执行前，按请求的张数预估，实际张数由供应商处理器根据响应更新
+func (h *ImageHook) Before(ctx context.Context, c *core.Context) (err error) {
+	if c.CurrentModel == nil {
+		err = errors.New("model info is nil")
+		return
+	}
+	params, err := parseImageParams(c)
+	if err != nil {
+		return
+	}
+	c.Units = params.N
+	c.UnitPrice = c.CurrentModel.GetUnitPrice(params.Size, params.Quality)
+	log.Infof("image n: %d, size: %s, quality: %s, unit price: %v", params.N, params.Size, params.Quality, c.UnitPrice)
+	return
+}
+
+type imageParams struct {
+	N       int64  `json:"n"`
+	Size    string `json:"size"`
+	Quality string `json:"quality"`
+}
+
+// parseImageParams 图片编辑为 multipart 请求，生成为 JSON 请求
+func parseImageParams(c *core.Context) (params imageParams, err error) {
+	if utils.IsMultipart(c.ContentType) {
+		var values map[string]string
+		if values, err = utils.MultipartFormValues(c.ContentType, c.InputBody); err != nil {
+			return
+		}
+		params.Size, params.Quality = values["size"], values["quality"]
+		if values["n"] != "" {
+			if params.N, err = strconv.ParseInt(values["n"], 10, 64); err != nil {
+				return
+			}
+		}
+	} else if err = json.Unmarshal(c.InputBody, &params); err != nil {
+		return
+	}
+	if params.N <= 0 {
+		params.N = 1
+	}
+	return
+}
+
+// After 执行后
+func (h *ImageHook) After(ctx context.Context, c *core.Context) (err error) {
+	return
+}
+
+func (h *ImageHook) OnChunk(ctx context.Context, c *core.Context, chunk *core.StreamChunk) (err error) {
+	return
+}
+
+func (h *ImageHook) OnError(ctx context.Context, c *core.Context, err error) {
+}
@@ -29,7 +29,7 @@ func NewOpenAITokenHook(i do.Injector) (*OpenAITokenHook, error) {
 }
 
 func (h *OpenAITokenHook) Name() string {
-	return "openai_token"
+	return core.HookOpenAIToken
 }
 
 // Before 执行前
@@ -38,18 +38,12 @@ func (h *OpenAITokenHook) Before(ctx context.Context, c *core.Context) (err erro
 		err = errors.New("model info is nil")
 		return
 	}
-	var reqBody struct {
-		Messages []openai.ChatCompletionMessage `json:"messages"`
-	}
-	if err = json.Unmarshal(c.InputBody, &reqBody); err != nil {
+	text, err := h.promptText(c)
+	if err != nil {
 		return
 	}
-	var text strings.Builder
-	for _, message := range reqBody.Messages {
-		text.WriteString(message.Content)
-	}
 	// providerId := c.ModelInfo.ProviderId
-	tokenNum, err := h.countTokenText(c.CurrentModel.ModelCode, text.String())
+	tokenNum, err := countTokenText(c.CurrentModel.ModelCode, text)
 	if err != nil {
 		return
 	}
@@ -59,6 +53,161 @@ func (h *OpenAITokenHook) Before(ctx context.Context, c *core.Context) (err erro
 	return
 }
 
+// promptText 提取输入文本，Anthropic 和 Responses 协议的 content 可能是内容块数组
+func (h *OpenAITokenHook) promptText(c *core.Context) (string, error) {
+	var text strings.Builder
+	if c.Protocol == core.ProtocolOpenAIResponses {
+		var reqBody struct {
+			Instructions string          `json:"instructions"`
+			Input        json.RawMessage `json:"input"`
+		}
+		if err := json.Unmarshal(c.InputBody, &reqBody); err != nil {
+			return "", err
+		}
+		text.WriteString(reqBody.Instructions)
+		var items []struct {
+			Content   json.RawMessage `json:"content"`
+			Arguments string          `json:"arguments"`
+			Output    json.RawMessage `json:"output"`
+		}
+		if err := json.Unmarshal(reqBody.Input, &items); err != nil {
+			text.WriteString(anthropicText(reqBody.Input))
+			return text.String(), nil
+		}
+		for _, item := range items {
+			text.WriteString(anthropicText(item.Content))
+			text.WriteString(item.Arguments)
+			text.WriteString(anthropicText(item.Output))
+		}
+		return text.String(), nil
+	}
+	if c.Protocol == core.ProtocolGemini {
+		var reqBody struct {
+			SystemInstruction *geminiContent  `json:"systemInstruction"`
+			Contents          []geminiContent `json:"contents"`
+		}
+		if err := json.Unmarshal(c.InputBody, &reqBody); err != nil {
+			return "", err
+		}
+		if reqBody.SystemInstruction != nil {
+			text.WriteString(reqBody.SystemInstruction.text())
+		}
+		for _, content := range reqBody.Contents {
+			text.WriteString(content.text())
+		}
+		return text.String(), nil
+	}
+	if c.Protocol == core.ProtocolAnthropic {
+		var reqBody struct {
+			System   json.RawMessage `json:"system"`
+			Messages []struct {
+				Content json.RawMessage `json:"content"`
+			} `json:"messages"`
+		}
+		if err := json.Unmarshal(c.InputBody, &reqBody); err != nil {
+			return "", err
+		}
+		text.WriteString(anthropicText(reqBody.System))
+		for _, message := range reqBody.Messages {
+			text.WriteString(anthropicText(message.Content))
+		}
+		return text.String(), nil
+	}
+	var reqBody struct {
+		Messages []openai.ChatCompletionMessage `json:"messages"`
+	}
+	if err := json.Unmarshal(c.InputBody, &reqBody); err != nil {
+		return "", err
+	}
+	for _, message := range reqBody.Messages {
+		text.WriteString(message.Content)
+	}
+	return text.String(), nil
+}
+
+// anthropicText content 既可以是字符串也可以是内容块数组
+func anthropicText(data json.RawMessage) string {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		return text
+	}
+	var blocks []struct {
+		Text string `json:"text"`
+	}
+	_ = json.Unmarshal(data, &blocks)
+	var b strings.Builder
+	for _, block := range blocks {
+		b.WriteString(block.Text)
+	}
+	return b.String()
+}
+
+// anthropicStreamEvent Anthropic 流式事件中与用量相关的字段
+type anthropicStreamEvent struct {
+	Type    string `json:"type"`
+	Message *struct {
+		Model string         `json:"model"`
+		Usage anthropicUsage `json:"usage"`
+	} `json:"message"`
+	Delta *struct {
+		Text string `json:"text"`
+	} `json:"delta"`
+	Usage *anthropicUsage `json:"usage"`
+}
+
+type anthropicUsage struct {
+	InputTokens          int64 `json:"input_tokens"`
+	CacheReadInputTokens int64 `json:"cache_read_input_tokens"`
+	OutputTokens         int64 `json:"output_tokens"`
+}
+
+// responsesStreamEvent Responses 流式事件中与用量相关的字段
+type responsesStreamEvent struct {
+	Type     string `json:"type"`
+	Delta    string `json:"delta"`
+	Response *struct {
+		Model string `json:"model"`
+		Usage *struct {
+			InputTokens        int64 `json:"input_tokens"`
+			InputTokensDetails struct {
+				CachedTokens int64 `json:"cached_tokens"`
+			} `json:"input_tokens_details"`
+			OutputTokens int64 `json:"output_tokens"`
+			TotalTokens  int64 `json:"total_tokens"`
+		} `json:"usage"`
+	} `json:"response"`
+}
+
+// geminiContent Gemini 内容中与 token 计算相关的字段
+type geminiContent struct {
+	Parts []struct {
+		Text string `json:"text"`
+	} `json:"parts"`
+}
+
+func (g *geminiContent) text() string {
+	var b strings.Builder
+	for _, part := range g.Parts {
+		b.WriteString(part.Text)
+	}
+	return b.String()
+}
+
+// geminiStreamEvent Gemini 流式事件中与用量相关的字段，usageMetadata 为累计值
+type geminiStreamEvent struct {
+	Candidates []struct {
+		Content geminiContent `json:"content"`
+	} `json:"candidates"`
+	UsageMetadata *struct {
+		PromptTokenCount        int64 `json:"promptTokenCount"`
+		CandidatesTokenCount    int64 `json:"candidatesTokenCount"`
+		ThoughtsTokenCount      int64 `json:"thoughtsTokenCount"`
+		CachedContentTokenCount int64 `json:"cachedContentTokenCount"`
+		TotalTokenCount         int64 `json:"totalTokenCount"`
+	} `json:"usageMetadata"`
+	ModelVersion string `json:"modelVersion"`
+}
+
 // After 执行后
 func (h *OpenAITokenHook) After(ctx context.Context, c *core.Context) (err error) {
 	if c.IsStream || c.Usage != nil {
@@ -74,7 +223,7 @@ func (h *OpenAITokenHook) After(ctx context.Context, c *core.Context) (err error
 	for _, item := range respData.Choices {
 		text.WriteString(item.Message.Content)
 	}
-	tokenNum, err := h.countTokenText(c.CurrentModel.ModelCode, text.String())
+	tokenNum, err := countTokenText(c.CurrentModel.ModelCode, text.String())
 	if err != nil {
 		return
 	}
@@ -86,6 +235,14 @@ func (h *OpenAITokenHook) OnChunk(ctx context.Context, c *core.Context, chunk *c
 	if chunk.Finish {
 		return
 	}
+	switch c.Protocol {
+	case core.ProtocolAnthropic:
+		return h.onAnthropicChunk(c, chunk)
+	case core.ProtocolOpenAIResponses:
+		return h.onResponsesChunk(c, chunk)
+	case core.ProtocolGemini:
+		return h.onGeminiChunk(c, chunk)
+	}
 	// 如果有的话，解析返回的usage
 	var respData openai.ChatCompletionChunk
 	if err = json.Unmarshal([]byte(chunk.Data), &respData); err != nil {
@@ -96,7 +253,7 @@ func (h *OpenAITokenHook) OnChunk(ctx context.Context, c *core.Context, chunk *c
 	// 计算token
 	if len(respData.Choices) > 0 {
 		var tokenNum int
-		tokenNum, err = h.countTokenText(c.CurrentModel.ModelCode, respData.Choices[0].Delta.Content)
+		tokenNum, err = countTokenText(c.CurrentModel.ModelCode, respData.Choices[0].Delta.Content)
 		if err != nil {
 			return
 		}
@@ -112,14 +269,121 @@ func (h *OpenAITokenHook) OnChunk(ctx context.Context, c *core.Context, chunk *c
 	return
 }
 
+// onAnthropicChunk 解析 Anthropic 流式事件：message_start 携带输入用量，message_delta 携带输出用量
+func (h *OpenAITokenHook) onAnthropicChunk(c *core.Context, chunk *core.StreamChunk) (err error) {
+	var event anthropicStreamEvent
+	if err = json.Unmarshal([]byte(chunk.Data), &event); err != nil {
+		log.Errorf("json unmarshal data %s, error: %v", chunk.Data, err)
+		return
+	}
+	switch event.Type {
+	case "message_start":
+		if event.Message == nil {
+			return
+		}
+		c.ActualModel = event.Message.Model
+		c.Usage = &core.Usage{
+			PromptTokens:       event.Message.Usage.InputTokens,
+			PromptCachedTokens: event.Message.Usage.CacheReadInputTokens,
+		}
+	case "content_block_delta":
+		if event.Delta == nil {
+			return
+		}
+		var tokenNum int
+		tokenNum, err = countTokenText(c.CurrentModel.ModelCode, event.Delta.Text)
+		if err != nil {
+			return
+		}
+		c.CompletionTokens += tokenNum
+	case "message_delta":
+		if event.Usage == nil {
+			return
+		}
+		if c.Usage == nil {
+			c.Usage = &core.Usage{}
+		}
+		if event.Usage.InputTokens > 0 {
+			c.Usage.PromptTokens = event.Usage.InputTokens
+		}
+		if event.Usage.CacheReadInputTokens > 0 {
+			c.Usage.PromptCachedTokens = event.Usage.CacheReadInputTokens
+		}
+		c.Usage.CompletionTokens = event.Usage.OutputTokens
+		c.Usage.TotalTokens = c.Usage.PromptTokens + c.Usage.PromptCachedTokens + c.Usage.CompletionTokens
+	}
+	return
+}
+
+// onResponsesChunk 解析 Responses 流式事件：增量文本估算输出 token，结束事件携带完整用量
+func (h *OpenAITokenHook) onResponsesChunk(c *core.Context, chunk *core.StreamChunk) (err error) {
+	var event responsesStreamEvent
+	if err = json.Unmarshal([]byte(chunk.Data), &event); err != nil {
+		log.Errorf("json unmarshal data %s, error: %v", chunk.Data, err)
+		return
+	}
+	switch event.Type {
+	case "response.output_text.delta", "response.function_call_arguments.delta":
+		var tokenNum int
+		tokenNum, err = countTokenText(c.CurrentModel.ModelCode, event.Delta)
+		if err != nil {
+			return
+		}
+		c.CompletionTokens += tokenNum
+	case "response.completed", "response.incomplete", "response.failed":
+		if event.Response == nil {
+			return
+		}
+		c.ActualModel = event.Response.Model
+		if usage := event.Response.Usage; usage != nil && usage.TotalTokens > 0 {
+			c.Usage = &core.Usage{
+				PromptTokens:       usage.InputTokens,
+				PromptCachedTokens: usage.InputTokensDetails.CachedTokens,
+				CompletionTokens:   usage.OutputTokens,
+				TotalTokens:        usage.TotalTokens,
+			}
+		}
+	}
+	return
+}
+
+// onGeminiChunk 解析 Gemini 流式事件：文本估算输出 token，usageMetadata 携带完整用量
+func (h *OpenAITokenHook) onGeminiChunk(c *core.Context, chunk *core.StreamChunk) (err error) {
+	var event geminiStreamEvent
+	if err = json.Unmarshal([]byte(chunk.Data), &event); err != nil {
+		log.Errorf("json unmarshal data %s, error: %v", chunk.Data, err)
+		return
+	}
+	if event.ModelVersion != "" {
+		c.ActualModel = event.ModelVersion
+	}
+	for _, candidate := range event.Candidates {
+		var tokenNum int
+		tokenNum, err = countTokenText(c.CurrentModel.ModelCode, candidate.Content.text())
+		if err != nil {
+			return
+		}
+		c.CompletionTokens += tokenNum
+	}
+	if usage := event.UsageMetadata; usage != nil && usage.TotalTokenCount > 0 {
+		c.Usage = &core.Usage{
+			PromptTokens:       usage.PromptTokenCount,
+			PromptCachedTokens: usage.CachedContentTokenCount,
+			CompletionTokens:   usage.CandidatesTokenCount + usage.ThoughtsTokenCount,
+			TotalTokens:        usage.TotalTokenCount,
+		}
+	}
+	return
+}
+
 func (h *OpenAITokenHook) OnError(ctx context.Context, c *core.Context, err error) {
 }
 
-func (h *OpenAITokenHook) countTokenText(model, text string) (num int, err error) {
+func countTokenText(model, text string) (num int, err error) {
 	if len(text) == 0 {
 		return
 	}
-	tt, err := h.getTokenEncoder(model)
+	tt, err := getTokenEncoder(model)
 	if err != nil {
 		return
 	}
@@ -127,7 +391,7 @@ func (h *OpenAITokenHook) countTokenText(model, text string) (num int, err error
 	return
 }
 
-func (h *OpenAITokenHook) getTokenEncoder(model string) (*tiktoken.Tiktoken, error) {
+func getTokenEncoder(model string) (*tiktoken.Tiktoken, error) {
 	locker.RLock()
 	tt, ok := tokenEncoderMap[model]
 	locker.RUnlock()
@@ -2,9 +2,13 @@ package hooks
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"time"
 
 	"github.com/samber/do/v2"
 	"github.com/samber/lo"
+	log "github.com/sirupsen/logrus"
 
 	"github.com/modelgate/modelgate/internal/relay"
 	"github.com/modelgate/modelgate/internal/relay/model"
@@ -26,11 +30,17 @@ func NewRequestHook(i do.Injector) (*RequestHook, error) {
 }
 
 func (h *RequestHook) Name() string {
-	return "request"
+	return core.HookRequest
 }
 
 // Before 执行前
 func (h *RequestHook) Before(ctx context.Context, c *core.Context) (err error) {
+	c.StartedAt = time.Now()
+	if c.CurrentModel.ApiKeyId != 0 {
+		if sErr := h.service.StartRequestStats(ctx, statsRequest(c)); sErr != nil {
+			log.Errorf("start request stats error: %v", sErr)
+		}
+	}
 	_, err = h.service.CreateRequest(ctx, &model.CreateRequestRequest{
 		RequestUUID:      c.RequestUUID,
 		AttemptNo:        c.AttemptNo,
@@ -44,6 +54,7 @@ func (h *RequestHook) Before(ctx context.Context, c *core.Context) (err error) {
 		CompletionTokens: 0,
 		TotalTokens:      0,
 		Status:           model.RequestStatusPending,
+		AttemptOnly:      c.Hedging(),
 	})
 	return
 }
@@ -51,10 +62,13 @@ func (h *RequestHook) Before(ctx context.Context, c *core.Context) (err error) {
 // After 执行后
 func (h *RequestHook) After(ctx context.Context, c *core.Context) (err error) {
 	var req = model.UpdateRequestCompletedRequest{
-		RequestUUID:  c.RequestUUID,
-		AttemptNo:    c.AttemptNo,
-		ProviderCode: c.CurrentModel.ProviderCode,
-		ActualModel:  lo.Ternary(c.ActualModel != "", c.ActualModel, c.CurrentModel.ModelCode),
+		RequestUUID:      c.RequestUUID,
+		AttemptNo:        c.AttemptNo,
+		ProviderCode:     c.CurrentModel.ProviderCode,
+		ProviderId:       c.CurrentModel.ProviderId,
+		ProviderApiKeyId: c.CurrentModel.ApiKeyId,
+		ModelId:          c.CurrentModel.ModelId,
+		ActualModel:      lo.Ternary(c.ActualModel != "", c.ActualModel, c.CurrentModel.ModelCode),
 	}
 	if c.Usage != nil {
 		req.PromptTokens = c.Usage.PromptTokens
@@ -65,21 +79,101 @@ func (h *RequestHook) After(ctx context.Context, c *core.Context) (err error) {
 		req.CompletionTokens = int64(c.CompletionTokens)
 		req.TotalTokens = int64(c.PromptTokens + c.CompletionTokens)
 	}
-	if c.LastErr != nil {
+	switch {
+	case c.HedgeLost():
+		// 只记录本次尝试，请求的结果以胜出方为准
+		req.Status = model.RequestStatusCancelled
+		req.ErrorMessage = c.LastErr.Error()
+		req.AttemptOnly = true
+	case c.Cancelled:
+		req.Status = model.RequestStatusCancelled
+		req.ErrorMessage = c.LastErr.Error()
+	case c.LastErr != nil:
 		req.Status = model.RequestStatusFailed
 		req.ErrorMessage = c.LastErr.Error()
 		if c.HTTPResponse != nil {
 			req.ErrorCode = c.HTTPResponse.StatusCode
 		}
-	} else {
+	default:
 		req.Status = model.RequestStatusSuccess
 	}
+	h.reportApiKeyResult(ctx, c)
 	err = h.service.UpdateRequestCompleted(ctx, &req)
 	return
 }
 
+// statsRequest 负载均衡统计，每次尝试单独计数
+func statsRequest(c *core.Context) *model.RequestStatsRequest {
+	return &model.RequestStatsRequest{
+		Member:   fmt.Sprintf("%s:%d", c.RequestUUID, c.AttemptNo),
+		ModelId:  c.CurrentModel.ModelId,
+		ApiKeyId: c.CurrentModel.ApiKeyId,
+	}
+}
+
+// reportApiKeyResult 上报供应商 Key 的调用结果用于熔断，并结束负载均衡统计
+func (h *RequestHook) reportApiKeyResult(ctx context.Context, c *core.Context) {
+	if c.CurrentModel.ApiKeyId == 0 {
+		return
+	}
+	result := apiKeyResult(c)
+	stats := statsRequest(c)
+	if result != nil {
+		stats.Failed = !result.Success
+		if result.Success {
+			stats.Latency = attemptLatency(c)
+		}
+	}
+	if err := h.service.FinishRequestStats(ctx, stats); err != nil {
+		log.Errorf("finish request stats error: %v", err)
+	}
+	if result == nil {
+		return
+	}
+	if err := h.service.ReportProviderApiKeyResult(ctx, result); err != nil {
+		log.Errorf("report provider api key %d result error: %v", result.ApiKeyId, err)
+	}
+}
+
+// apiKeyResult 上游拒绝该 Key 时禁用，网络错误、408、429、5xx 计为失败，其他 4xx 是请求本身的问题，与被取消的一样返回 nil
+// 发起上游请求前的本地错误与 Key 无关，也返回 nil
+func apiKeyResult(c *core.Context) *model.ReportProviderApiKeyResultRequest {
+	if c.Cancelled || c.HedgeLost() || core.IsLocalError(c.LastErr) {
+		return nil
+	}
+	req := &model.ReportProviderApiKeyResultRequest{ApiKeyId: c.CurrentModel.ApiKeyId, Success: c.LastErr == nil}
+	if c.LastErr == nil {
+		return req
+	}
+	if req.Rejected = core.KeyRejectedReason(c.HTTPResponse, c.RawResponse); req.Rejected != "" || c.HTTPResponse == nil {
+		return req
+	}
+	switch code := c.HTTPResponse.StatusCode; {
+	case code == http.StatusTooManyRequests:
+		req.RateLimited = true
+	case code == http.StatusRequestTimeout, code >= http.StatusInternalServerError:
+	case code >= http.StatusBadRequest:
+		return nil
+	}
+	return req
+}
+
+// attemptLatency 流式请求使用首个 chunk 的延迟，不受输出长度影响
+func attemptLatency(c *core.Context) time.Duration {
+	if c.StartedAt.IsZero() {
+		return 0
+	}
+	if !c.FirstChunkAt.IsZero() {
+		return c.FirstChunkAt.Sub(c.StartedAt)
+	}
+	return time.Since(c.StartedAt)
+}
+
 // OnChunk 流chunk
 func (h *RequestHook) OnChunk(ctx context.Context, c *core.Context, chunk *core.StreamChunk) (err error) {
+	if c.FirstChunkAt.IsZero() {
+		c.FirstChunkAt = time.Now()
+	}
 	return
 }
 
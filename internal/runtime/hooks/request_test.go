@@ -0,0 +1,60 @@
+package hooks
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/modelgate/modelgate/internal/relay/model"
+	"github.com/modelgate/modelgate/internal/runtime/core"
+)
+
+func TestApiKeyResult(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int // 0 表示没有响应
+		want   *model.ReportProviderApiKeyResultRequest
+	}{
+		{"success", nil, http.StatusOK, &model.ReportProviderApiKeyResultRequest{ApiKeyId: 1, Success: true}},
+		{"network error", errors.New("eof"), 0, &model.ReportProviderApiKeyResultRequest{ApiKeyId: 1}},
+		{"server error", errors.New("502"), http.StatusBadGateway, &model.ReportProviderApiKeyResultRequest{ApiKeyId: 1}},
+		{"rate limited", errors.New("429"), http.StatusTooManyRequests, &model.ReportProviderApiKeyResultRequest{ApiKeyId: 1, RateLimited: true}},
+		{"bad request", errors.New("400"), http.StatusBadRequest, nil},
+		{"unauthorized", errors.New("401"), http.StatusUnauthorized, &model.ReportProviderApiKeyResultRequest{ApiKeyId: 1, Rejected: "401 Unauthorized"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &core.Context{CurrentModel: &core.Model{ApiKeyId: 1}, LastErr: tt.err}
+			if tt.status != 0 {
+				c.HTTPResponse = &http.Response{StatusCode: tt.status}
+			}
+			if got := apiKeyResult(c); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+	c := &core.Context{CurrentModel: &core.Model{ApiKeyId: 1}, LastErr: context.Canceled, Cancelled: true}
+	if got := apiKeyResult(c); got != nil {
+		t.Fatalf("cancelled request should not be reported, got %+v", got)
+	}
+	c = &core.Context{CurrentModel: &core.Model{ApiKeyId: 1}, LastErr: &core.LocalError{Err: errors.New("invalid body")}}
+	if got := apiKeyResult(c); got != nil {
+		t.Fatalf("local error should not be reported, got %+v", got)
+	}
+}
+
+func TestAttemptLatency(t *testing.T) {
+	start := time.Now().Add(-time.Second)
+	c := &core.Context{StartedAt: start, FirstChunkAt: start.Add(200 * time.Millisecond)}
+	if got := attemptLatency(c); got != 200*time.Millisecond {
+		t.Fatalf("stream latency = %v, want 200ms", got)
+	}
+	c.FirstChunkAt = time.Time{}
+	if got := attemptLatency(c); got < time.Second {
+		t.Fatalf("latency = %v, want >= 1s", got)
+	}
+}
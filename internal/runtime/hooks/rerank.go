@@ -0,0 +1,82 @@
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/samber/do/v2"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/modelgate/modelgate/internal/runtime/core"
+)
+
+// documentsPerSearchUnit 一个搜索单元包含一次查询和最多 100 个文档
+const documentsPerSearchUnit = 100
+
+// RerankHook 估算重排序的输入 Token 和搜索单元
+type RerankHook struct {
+}
+
+var _ core.Hook = (*RerankHook)(nil)
+
+func NewRerankHook(i do.Injector) (*RerankHook, error) {
+	return &RerankHook{}, nil
+}
+
+func (h *RerankHook) Name() string {
+	return core.HookRerank
+}
+
+// Before 执行前，实际用量由供应商处理器根据响应更新
+func (h *RerankHook) Before(ctx context.Context, c *core.Context) (err error) {
+	if c.CurrentModel == nil {
+		err = errors.New("model info is nil")
+		return
+	}
+	var req core.RerankRequest
+	if err = json.Unmarshal(c.InputBody, &req); err != nil {
+		return
+	}
+	tokenNum, err := countRerankTokens(c.CurrentModel.ModelCode, req.Query, req.DocumentTexts())
+	if err != nil {
+		return
+	}
+	c.PromptTokens = tokenNum
+	c.Units = searchUnits(len(req.Documents))
+	c.UnitPrice = c.CurrentModel.GetUnitPrice("", "")
+	log.Infof("rerank prompt token num: %d, search units: %d", c.PromptTokens, c.Units)
+	return
+}
+
+// countRerankTokens 每个文档与查询组成一对输入模型
+func countRerankTokens(model, query string, documents []string) (num int, err error) {
+	queryNum, err := countTokenText(model, query)
+	if err != nil {
+		return
+	}
+	for _, doc := range documents {
+		var n int
+		if n, err = countTokenText(model, doc); err != nil {
+			return
+		}
+		num += queryNum + n
+	}
+	return
+}
+
+func searchUnits(documents int) int64 {
+	return int64(max(1, (documents+documentsPerSearchUnit-1)/documentsPerSearchUnit))
+}
+
+// After 执行后
+func (h *RerankHook) After(ctx context.Context, c *core.Context) (err error) {
+	return
+}
+
+func (h *RerankHook) OnChunk(ctx context.Context, c *core.Context, chunk *core.StreamChunk) (err error) {
+	return
+}
+
+func (h *RerankHook) OnError(ctx context.Context, c *core.Context, err error) {
+}
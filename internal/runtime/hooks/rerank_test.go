@@ -0,0 +1,12 @@
+package hooks
+
+import "testing"
+
+func TestSearchUnits(t *testing.T) {
+	cases := map[int]int64{0: 1, 1: 1, 100: 1, 101: 2, 250: 3}
+	for documents, want := range cases {
+		if got := searchUnits(documents); got != want {
+			t.Fatalf("documents %d: got %d, want %d", documents, got, want)
+		}
+	}
+}
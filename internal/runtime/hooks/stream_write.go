@@ -18,7 +18,7 @@ func NewStreamHook(i do.Injector) (*StreamWriteHook, error) {
 }
 
 func (h *StreamWriteHook) Name() string {
-	return "stream_write"
+	return core.HookStreamWrite
 }
 
 func (h *StreamWriteHook) Before(ctx context.Context, c *core.Context) error {
@@ -37,13 +37,21 @@ func (h *StreamWriteHook) After(ctx context.Context, c *core.Context) error {
 
 func (h *StreamWriteHook) OnChunk(ctx context.Context, c *core.Context, chunk *core.StreamChunk) error {
 	if c.StreamWriter != nil {
+		if !chunk.Finish {
+			c.StreamStarted = true
+		}
 		_ = c.StreamWriter.Write(chunk)
 	}
 	return nil
 }
 
+// OnError 还没有写入数据时由重试或接口层处理错误，已经开始写入时发送错误事件
 func (h *StreamWriteHook) OnError(ctx context.Context, c *core.Context, err error) {
-	if c.StreamWriter != nil {
-		_ = c.StreamWriter.Close()
+	if c.StreamWriter == nil {
+		return
+	}
+	if c.StreamStarted {
+		_ = c.StreamWriter.Write(core.ErrorChunk(c.Protocol, err))
 	}
+	_ = c.StreamWriter.Close()
 }
@@ -4,23 +4,23 @@ import (
 	"github.com/samber/do/v2"
 
 	"github.com/modelgate/modelgate/internal/runtime/core"
-	"github.com/modelgate/modelgate/internal/runtime/hooks"
 )
 
 func Init(i do.Injector) {
-	reqHook := do.MustInvoke[*hooks.RequestHook](i)
-	tokenHook := do.MustInvoke[*hooks.OpenAITokenHook](i)
-	billingHook := do.MustInvoke[*hooks.BillingHook](i)
-	streamWriteHook := do.MustInvoke[*hooks.StreamWriteHook](i)
-
 	handler := NewHandler(core.ProviderCodeAnthropic)
 
 	core.ExecutorRegistry.Register(core.ProviderCodeAnthropic, func(opts core.Options) (core.Executor, error) {
-		if opts.IsStream {
-			return core.NewStreamExecutor(handler, reqHook, streamWriteHook, tokenHook, billingHook), nil
-		} else {
-			base := core.NewExecutor(handler, reqHook, tokenHook, billingHook)
-			return core.NewRetryExecutor(base, opts.Retry), nil
+		// count_tokens 免费，直接透传
+		if opts.Endpoint == core.EndpointCountTokens && opts.Protocol == core.ProtocolAnthropic {
+			return core.ExecutorRegistry.NewExecutor(handler, opts)
+		}
+		if !opts.Endpoint.IsChat() {
+			return nil, core.ErrEndpointNotSupported(core.ProviderCodeAnthropic, opts.Endpoint)
+		}
+		h, err := core.Translate(handler, core.ProtocolAnthropic, opts.Protocol)
+		if err != nil {
+			return nil, err
 		}
+		return core.ExecutorRegistry.NewExecutor(h, opts)
 	})
 }
@@ -32,10 +32,18 @@ func (h *Handler) Provider() string {
 // BeforeRequest 构建请求参数
 func (h *Handler) BeforeRequest(ctx context.Context, c *core.Context) (err error) {
 	endpoint := c.CurrentModel.BaseUrl + "/v1/messages"
-	req, err := http.NewRequest(
+	if c.Endpoint == core.EndpointCountTokens {
+		endpoint += "/count_tokens"
+	}
+	body, err := c.RequestBody()
+	if err != nil {
+		return
+	}
+	req, err := http.NewRequestWithContext(
+		ctx,
 		"POST",
 		endpoint,
-		bytes.NewReader(c.InputBody),
+		bytes.NewReader(body),
 	)
 	if err != nil {
 		return
@@ -61,7 +69,7 @@ func (h *Handler) BeforeRequest(ctx context.Context, c *core.Context) (err error
 
 // DoRequest 发送请求，并处理结果
 func (h *Handler) DoRequest(ctx context.Context, c *core.Context) (err error) {
-	resp, err := core.HttpClient.Do(c.HTTPRequest)
+	resp, err := core.ProviderClient(c.CurrentModel).Do(c.HTTPRequest)
 	if err != nil {
 		return
 	}
@@ -94,6 +102,9 @@ func (h *Handler) DoRequest(ctx context.Context, c *core.Context) (err error) {
 
 // AfterResponse 处理响应结果
 func (h *Handler) AfterResponse(ctx context.Context, c *core.Context) (err error) {
+	if c.Endpoint == core.EndpointCountTokens {
+		return
+	}
 	var respData struct {
 		Model string `json:"model"`
 		Usage Usage  `json:"usage"`
@@ -115,17 +126,18 @@ func (h *Handler) AfterResponse(ctx context.Context, c *core.Context) (err error
 
 // DoStream 发送流式请求
 func (h *Handler) DoStream(ctx context.Context, c *core.Context) (stream core.Stream, err error) {
-	resp, err := core.HttpClient.Do(c.HTTPRequest)
+	resp, err := core.ProviderClient(c.CurrentModel).Do(c.HTTPRequest)
 	if err != nil {
 		return
 	}
 
+	c.HTTPResponse = resp
 	if resp.StatusCode != http.StatusOK {
 		defer resp.Body.Close()
 		b, _ := io.ReadAll(resp.Body)
+		c.RawResponse = b
 		return nil, fmt.Errorf("anthropic stream error: %s", b)
 	}
 
-	c.HTTPResponse = resp
 	return openai.NewStreamReceiver(resp.Body), nil
 }
@@ -1,5 +1,7 @@
 package anthropic
 
+import "encoding/json"
+
 const (
 	MessageStart      = "message_start"
 	MessageDelta      = "message_delta"
@@ -7,8 +9,96 @@ const (
 	ContentBlockStart = "content_block_start"
 	ContentBlockDelta = "content_block_delta"
 	ContentBlockStop  = "content_block_stop"
+	Ping              = "ping"
+	ErrorEvent        = "error"
+)
+
+const (
+	ContentTypeText       = "text"
+	ContentTypeImage      = "image"
+	ContentTypeToolUse    = "tool_use"
+	ContentTypeToolResult = "tool_result"
+	ContentTypeThinking   = "thinking"
+)
+
+const (
+	DeltaTypeText      = "text_delta"
+	DeltaTypeInputJson = "input_json_delta"
 )
 
+const (
+	StopReasonEndTurn      = "end_turn"
+	StopReasonMaxTokens    = "max_tokens"
+	StopReasonStopSequence = "stop_sequence"
+	StopReasonToolUse      = "tool_use"
+	StopReasonRefusal      = "refusal"
+)
+
+// Request Messages 请求
+type Request struct {
+	Model         string          `json:"model"`
+	System        json.RawMessage `json:"system,omitempty"` // 字符串或文本块数组
+	Messages      []Message       `json:"messages"`
+	MaxTokens     int64           `json:"max_tokens"`
+	Temperature   *float64        `json:"temperature,omitempty"`
+	TopP          *float64        `json:"top_p,omitempty"`
+	TopK          *int64          `json:"top_k,omitempty"`
+	StopSequences []string        `json:"stop_sequences,omitempty"`
+	Stream        bool            `json:"stream,omitempty"`
+	Tools         []Tool          `json:"tools,omitempty"`
+	ToolChoice    *ToolChoice     `json:"tool_choice,omitempty"`
+	Metadata      *Metadata       `json:"metadata,omitempty"`
+}
+
+// Message 对话消息
+type Message struct {
+	Role    string         `json:"role"`
+	Content MessageContent `json:"content"`
+}
+
+// MessageContent 消息内容，既可以是字符串也可以是内容块数组
+type MessageContent struct {
+	Text   string
+	Blocks []Content
+}
+
+func (m MessageContent) MarshalJSON() ([]byte, error) {
+	if m.Blocks != nil {
+		return json.Marshal(m.Blocks)
+	}
+	return json.Marshal(m.Text)
+}
+
+func (m *MessageContent) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '[' {
+		return json.Unmarshal(data, &m.Blocks)
+	}
+	return json.Unmarshal(data, &m.Text)
+}
+
+// GetBlocks 统一返回内容块
+func (m MessageContent) GetBlocks() []Content {
+	if m.Blocks != nil {
+		return m.Blocks
+	}
+	return []Content{{Type: ContentTypeText, Text: m.Text}}
+}
+
+type Tool struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	InputSchema json.RawMessage `json:"input_schema"`
+}
+
+type ToolChoice struct {
+	Type string `json:"type"` // auto / any / tool / none
+	Name string `json:"name,omitempty"`
+}
+
+type Metadata struct {
+	UserId string `json:"user_id,omitempty"`
+}
+
 type Response struct {
 	Id           string    `json:"id"`
 	Type         string    `json:"type"`
@@ -18,7 +108,7 @@ type Response struct {
 	StopReason   *string   `json:"stop_reason"`
 	StopSequence *string   `json:"stop_sequence"`
 	Usage        Usage     `json:"usage"`
-	Error        Error     `json:"error"`
+	Error        *Error    `json:"error,omitempty"`
 }
 
 type StreamResponse struct {
@@ -28,6 +118,7 @@ type StreamResponse struct {
 	ContentBlock *Content  `json:"content_block"`
 	Delta        *Delta    `json:"delta"`
 	Usage        *Usage    `json:"usage"`
+	Error        *Error    `json:"error"`
 }
 
 type Error struct {
@@ -40,17 +131,19 @@ type Content struct {
 	Text   string       `json:"text,omitempty"`
 	Source *ImageSource `json:"source,omitempty"`
 	// tool_calls
-	Id        string `json:"id,omitempty"`
-	Name      string `json:"name,omitempty"`
-	Input     any    `json:"input,omitempty"`
-	Content   string `json:"content,omitempty"`
-	ToolUseId string `json:"tool_use_id,omitempty"`
+	Id        string          `json:"id,omitempty"`
+	Name      string          `json:"name,omitempty"`
+	Input     any             `json:"input,omitempty"`
+	Content   json.RawMessage `json:"content,omitempty"` // tool_result 内容，字符串或内容块数组
+	ToolUseId string          `json:"tool_use_id,omitempty"`
+	IsError   bool            `json:"is_error,omitempty"`
 }
 
 type ImageSource struct {
-	Type      string `json:"type"`
-	MediaType string `json:"media_type"`
-	Data      string `json:"data"`
+	Type      string `json:"type"` // base64 / url
+	MediaType string `json:"media_type,omitempty"`
+	Data      string `json:"data,omitempty"`
+	Url       string `json:"url,omitempty"`
 }
 
 // Billing and rate-limit usage.
@@ -84,8 +177,8 @@ type ServerToolUse struct {
 }
 
 type Delta struct {
-	Type         string  `json:"type"`
-	Text         string  `json:"text"`
+	Type         string  `json:"type,omitempty"`
+	Text         string  `json:"text,omitempty"`
 	PartialJson  string  `json:"partial_json,omitempty"`
 	StopReason   *string `json:"stop_reason"`
 	StopSequence *string `json:"stop_sequence"`
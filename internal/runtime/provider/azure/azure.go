@@ -0,0 +1,26 @@
+package azure
+
+import (
+	"github.com/samber/do/v2"
+
+	"github.com/modelgate/modelgate/internal/runtime/core"
+)
+
+func Init(i do.Injector) {
+	handler := NewOpenAIHandler()
+
+	core.ExecutorRegistry.Register(core.ProviderCodeAzure, func(opts core.Options) (core.Executor, error) {
+		switch opts.Endpoint {
+		case core.EndpointEmbeddings, core.EndpointImages, core.EndpointAudio, core.EndpointSpeech:
+			return core.ExecutorRegistry.NewExecutor(handler, opts)
+		}
+		if !opts.Endpoint.IsChat() {
+			return nil, core.ErrEndpointNotSupported(core.ProviderCodeAzure, opts.Endpoint)
+		}
+		h, err := core.Translate(handler, core.ProtocolOpenAI, opts.Protocol)
+		if err != nil {
+			return nil, err
+		}
+		return core.ExecutorRegistry.NewExecutor(h, opts)
+	})
+}
@@ -0,0 +1,67 @@
+package azure
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/modelgate/modelgate/internal/config"
+	"github.com/modelgate/modelgate/internal/runtime/core"
+	"github.com/modelgate/modelgate/internal/runtime/provider/openai"
+	"github.com/modelgate/modelgate/pkg/utils"
+)
+
+// DefaultApiVersion 默认 api-version
+const DefaultApiVersion = "2024-10-21"
+
+// Config Azure OpenAI 供应商扩展配置，对应 Provider.Config
+type Config struct {
+	ApiVersion string `json:"api_version"` // api-version 参数
+}
+
+// OpenAIHandler Azure OpenAI 协议处理器，继承 openai.Handler
+// 仅覆写 BeforeRequest 以实现 Azure 特有的端点拼接：baseUrl/openai/deployments/{deployment}/<path>?api-version=xxx
+// 部署名称取模型的实际代码（Model.ActualCode）
+type OpenAIHandler struct {
+	*openai.Handler
+}
+
+func NewOpenAIHandler() *OpenAIHandler {
+	return &OpenAIHandler{
+		Handler: openai.NewHandler(core.ProviderCodeAzure),
+	}
+}
+
+func (h *OpenAIHandler) BeforeRequest(ctx context.Context, c *core.Context) (err error) {
+	var cfg Config
+	if err = c.CurrentModel.ParseProviderConfig(&cfg); err != nil {
+		return
+	}
+	apiVersion := cfg.ApiVersion
+	if apiVersion == "" {
+		apiVersion = DefaultApiVersion
+	}
+	baseUrl := strings.TrimRight(c.CurrentModel.BaseUrl, "/")
+	deployment := url.PathEscape(c.CurrentModel.ModelCode)
+	endpoint := baseUrl + "/openai/deployments/" + deployment + "/" + strings.TrimPrefix(c.UrlPath, "/v1/") +
+		"?api-version=" + url.QueryEscape(apiVersion)
+
+	log.Debugf("azure openai handler, model: %s, endpoint: %s", c.CurrentModel.ModelCode, endpoint)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(c.InputBody))
+	if err != nil {
+		return
+	}
+	apiKey, err := utils.DecryptAESGCM(c.CurrentModel.ApiKeyEncrypted, []byte(config.GetConfig().Secret.Key))
+	if err != nil {
+		return
+	}
+	req.Header.Set("api-key", string(apiKey))
+	req.Header.Set("Content-Type", c.GetContentType())
+	c.HTTPRequest = req
+	return
+}
@@ -0,0 +1,189 @@
+package bedrock
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"hash/crc32"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelgate/modelgate/internal/config"
+	"github.com/modelgate/modelgate/internal/runtime/core"
+	"github.com/modelgate/modelgate/pkg/utils"
+)
+
+const testSecretKey = "0123456789abcdef"
+
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "bedrock")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(dir)
+	cfgFile := filepath.Join(dir, "config.toml")
+	content := "[logger]\nlevel = \"info\"\n\n[secret]\nkey = \"" + testSecretKey + "\"\n"
+	if err = os.WriteFile(cfgFile, []byte(content), 0644); err != nil {
+		panic(err)
+	}
+	if _, err = config.NewConfig(cfgFile); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
+// 官方测试用例 get-vanilla
+func TestSignRequest(t *testing.T) {
+	req, _ := http.NewRequest("GET", "https://example.amazonaws.com/", nil)
+	cred := Credentials{AccessKeyId: "AKIDEXAMPLE", SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"}
+	now, _ := time.Parse(amzDateFormat, "20150830T123600Z")
+	signRequest(req, nil, cred, "us-east-1", "service", now)
+
+	expected := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, " +
+		"SignedHeaders=host;x-amz-date, Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31"
+	if got := req.Header.Get("Authorization"); got != expected {
+		t.Fatalf("unexpected authorization: %s", got)
+	}
+}
+
+func TestInvokeModel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/model/anthropic.claude-3-haiku-20240307-v1%3A0/invoke" {
+			t.Errorf("unexpected path: %s", r.URL.EscapedPath())
+		}
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/") ||
+			!strings.Contains(r.Header.Get("Authorization"), "/us-west-2/bedrock/aws4_request") {
+			t.Errorf("unexpected authorization: %s", r.Header.Get("Authorization"))
+		}
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if body["anthropic_version"] != AnthropicVersion || body["model"] != nil {
+			t.Errorf("unexpected body: %v", body)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"msg_1","type":"message","role":"assistant","content":[{"type":"text","text":"hi"}],"usage":{"input_tokens":12,"output_tokens":3}}`))
+	}))
+	defer server.Close()
+
+	c := newTestContext(t, server.URL, "anthropic.claude-3-haiku-20240307-v1:0", false)
+	handler := NewHandler()
+	ctx := context.Background()
+	if err := handler.BeforeRequest(ctx, c); err != nil {
+		t.Fatal(err)
+	}
+	if err := handler.DoRequest(ctx, c); err != nil {
+		t.Fatal(err)
+	}
+	if err := handler.AfterResponse(ctx, c); err != nil {
+		t.Fatal(err)
+	}
+	if c.Usage == nil || c.Usage.PromptTokens != 12 || c.Usage.CompletionTokens != 3 {
+		t.Fatalf("unexpected usage: %+v", c.Usage)
+	}
+}
+
+func TestInvokeModelWithResponseStream(t *testing.T) {
+	chunks := []string{
+		`{"generation":"Hel","prompt_token_count":8,"generation_token_count":1,"stop_reason":null}`,
+		`{"generation":"lo","prompt_token_count":null,"generation_token_count":2,"stop_reason":"stop","amazon-bedrock-invocationMetrics":{"inputTokenCount":8,"outputTokenCount":2}}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/invoke-with-response-stream") {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/vnd.amazon.eventstream")
+		for _, chunk := range chunks {
+			payload, _ := json.Marshal(map[string]string{"bytes": base64.StdEncoding.EncodeToString([]byte(chunk))})
+			_, _ = w.Write(encodeEventMessage(map[string]string{
+				":message-type": "event",
+				":event-type":   "chunk",
+				":content-type": "application/json",
+			}, payload))
+		}
+	}))
+	defer server.Close()
+
+	c := newTestContext(t, server.URL, "meta.llama3-8b-instruct-v1:0", true)
+	handler := NewHandler()
+	ctx := context.Background()
+	if err := handler.BeforeRequest(ctx, c); err != nil {
+		t.Fatal(err)
+	}
+	stream, err := handler.DoStream(ctx, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stream.Close()
+
+	var types []string
+	var text strings.Builder
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		var event struct {
+			Type  string `json:"type"`
+			Delta struct {
+				Text string `json:"text"`
+			} `json:"delta"`
+		}
+		if err = json.Unmarshal([]byte(chunk.Data), &event); err != nil {
+			t.Fatal(err)
+		}
+		types = append(types, event.Type)
+		text.WriteString(event.Delta.Text)
+	}
+	expected := "message_start,content_block_start,content_block_delta,content_block_delta,content_block_stop,message_delta,message_stop"
+	if strings.Join(types, ",") != expected || text.String() != "Hello" {
+		t.Fatalf("unexpected events: %v, text: %s", types, text.String())
+	}
+}
+
+func newTestContext(t *testing.T, baseUrl, modelCode string, stream bool) *core.Context {
+	key, err := utils.EncryptAESGCM([]byte("AKID:SECRET"), []byte(testSecretKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &core.Context{
+		RequestUUID: utils.NewUUIDv7(),
+		Protocol:    core.ProtocolAnthropic,
+		IsStream:    stream,
+		InputBody:   []byte(`{"model":"claude","max_tokens":100,"stream":true,"messages":[{"role":"user","content":"hello"}]}`),
+		CurrentModel: &core.Model{
+			ModelCode:       modelCode,
+			ProviderCode:    core.ProviderCodeBedrock,
+			BaseUrl:         baseUrl,
+			ProviderConfig:  `{"region":"us-west-2"}`,
+			ApiKeyEncrypted: key,
+		},
+	}
+}
+
+func encodeEventMessage(headers map[string]string, payload []byte) []byte {
+	var headerBytes []byte
+	for name, value := range headers {
+		headerBytes = append(headerBytes, byte(len(name)))
+		headerBytes = append(headerBytes, name...)
+		headerBytes = append(headerBytes, 7)
+		headerBytes = binary.BigEndian.AppendUint16(headerBytes, uint16(len(value)))
+		headerBytes = append(headerBytes, value...)
+	}
+	totalLen := 12 + len(headerBytes) + len(payload) + 4
+	msg := binary.BigEndian.AppendUint32(nil, uint32(totalLen))
+	msg = binary.BigEndian.AppendUint32(msg, uint32(len(headerBytes)))
+	msg = binary.BigEndian.AppendUint32(msg, crc32.ChecksumIEEE(msg))
+	msg = append(msg, headerBytes...)
+	msg = append(msg, payload...)
+	return binary.BigEndian.AppendUint32(msg, crc32.ChecksumIEEE(msg))
+}
@@ -0,0 +1,114 @@
+package bedrock
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/modelgate/modelgate/internal/runtime/provider/anthropic"
+)
+
+// GetModelFamily 根据模型 ID 判断模型系列，兼容跨区域推理配置（如 us.anthropic.xxx）
+func GetModelFamily(modelId string) ModelFamily {
+	switch {
+	case strings.Contains(modelId, "anthropic."):
+		return ModelFamilyAnthropic
+	case strings.Contains(modelId, "meta.llama"):
+		return ModelFamilyLlama
+	default:
+		return ""
+	}
+}
+
+// ConvertAnthropicRequest Claude 请求：去掉 model、stream，补充 anthropic_version
+func ConvertAnthropicRequest(data []byte) ([]byte, error) {
+	body := make(map[string]any)
+	if err := json.Unmarshal(data, &body); err != nil {
+		return nil, err
+	}
+	delete(body, "model")
+	delete(body, "stream")
+	body["anthropic_version"] = AnthropicVersion
+	return json.Marshal(body)
+}
+
+// ConvertLlamaRequest Messages 请求转换为 Llama 3 提示词模板
+func ConvertLlamaRequest(data []byte) ([]byte, error) {
+	var req MessagesRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		return nil, err
+	}
+	var prompt strings.Builder
+	prompt.WriteString("<|begin_of_text|>")
+	if system := textOf(req.System); system != "" {
+		writeLlamaTurn(&prompt, "system", system)
+	}
+	for _, msg := range req.Messages {
+		writeLlamaTurn(&prompt, msg.Role, textOf(msg.Content))
+	}
+	prompt.WriteString("<|start_header_id|>assistant<|end_header_id|>\n\n")
+
+	return json.Marshal(&LlamaRequest{
+		Prompt:      prompt.String(),
+		MaxGenLen:   req.MaxTokens,
+		Temperature: req.Temperature,
+		TopP:        req.TopP,
+	})
+}
+
+func writeLlamaTurn(prompt *strings.Builder, role, text string) {
+	prompt.WriteString("<|start_header_id|>" + role + "<|end_header_id|>\n\n")
+	prompt.WriteString(text)
+	prompt.WriteString("<|eot_id|>")
+}
+
+// textOf 提取文本内容，content 既可以是字符串也可以是内容块数组
+func textOf(data json.RawMessage) string {
+	if len(data) == 0 {
+		return ""
+	}
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		return text
+	}
+	var blocks []anthropic.Content
+	if err := json.Unmarshal(data, &blocks); err != nil {
+		return ""
+	}
+	var b strings.Builder
+	for _, block := range blocks {
+		if block.Type == "text" {
+			b.WriteString(block.Text)
+		}
+	}
+	return b.String()
+}
+
+// ConvertLlamaResponse Llama 响应转换为 Anthropic Messages 响应
+func ConvertLlamaResponse(data []byte, id, model string) ([]byte, error) {
+	var resp LlamaResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, fmt.Errorf("unmarshal llama response error: %v", err)
+	}
+	stopReason := convertLlamaStopReason(resp.StopReason)
+	return json.Marshal(map[string]any{
+		"id":            id,
+		"type":          "message",
+		"role":          "assistant",
+		"model":         model,
+		"content":       []map[string]any{{"type": "text", "text": resp.Generation}},
+		"stop_reason":   stopReason,
+		"stop_sequence": nil,
+		"usage": map[string]any{
+			"input_tokens":  resp.PromptTokenCount,
+			"output_tokens": resp.GenerationTokenCount,
+		},
+	})
+}
+
+func convertLlamaStopReason(reason *string) string {
+	if reason != nil && *reason == "length" {
+		return "max_tokens"
+	}
+	return "end_turn"
+}
@@ -0,0 +1,117 @@
+package bedrock
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+)
+
+// 单条消息最大 16MB，防止异常数据导致内存暴涨
+const maxEventStreamMessageSize = 16 * 1024 * 1024
+
+// eventMessage AWS event-stream 消息
+type eventMessage struct {
+	Headers map[string]string // 仅保留字符串类型的头
+	Payload []byte
+}
+
+// eventStreamDecoder 解析 AWS event-stream 二进制帧：
+// total length(4) | headers length(4) | prelude crc(4) | headers | payload | message crc(4)
+type eventStreamDecoder struct {
+	reader *bufio.Reader
+}
+
+func newEventStreamDecoder(r io.Reader) *eventStreamDecoder {
+	return &eventStreamDecoder{reader: bufio.NewReader(r)}
+}
+
+// Decode 读取一条消息，流结束时返回 io.EOF
+func (d *eventStreamDecoder) Decode() (msg *eventMessage, err error) {
+	prelude := make([]byte, 12)
+	if _, err = io.ReadFull(d.reader, prelude); err != nil {
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			err = fmt.Errorf("event stream prelude truncated")
+		}
+		return
+	}
+	totalLen := binary.BigEndian.Uint32(prelude[0:4])
+	headersLen := binary.BigEndian.Uint32(prelude[4:8])
+	if crc32.ChecksumIEEE(prelude[0:8]) != binary.BigEndian.Uint32(prelude[8:12]) {
+		err = fmt.Errorf("event stream prelude checksum mismatch")
+		return
+	}
+	if totalLen > maxEventStreamMessageSize || totalLen < 16 || headersLen > totalLen-16 {
+		err = fmt.Errorf("invalid event stream message length: %d", totalLen)
+		return
+	}
+
+	message := make([]byte, totalLen)
+	copy(message, prelude)
+	if _, err = io.ReadFull(d.reader, message[12:]); err != nil {
+		err = fmt.Errorf("event stream message truncated: %v", err)
+		return
+	}
+	crcOffset := totalLen - 4
+	if crc32.ChecksumIEEE(message[:crcOffset]) != binary.BigEndian.Uint32(message[crcOffset:]) {
+		err = fmt.Errorf("event stream message checksum mismatch")
+		return
+	}
+
+	headers, err := decodeEventHeaders(message[12 : 12+headersLen])
+	if err != nil {
+		return
+	}
+	msg = &eventMessage{
+		Headers: headers,
+		Payload: message[12+headersLen : crcOffset],
+	}
+	return
+}
+
+func decodeEventHeaders(data []byte) (headers map[string]string, err error) {
+	headers = make(map[string]string)
+	for len(data) > 0 {
+		nameLen := int(data[0])
+		if len(data) < 1+nameLen+1 {
+			return nil, fmt.Errorf("event stream header truncated")
+		}
+		name := string(data[1 : 1+nameLen])
+		valueType := data[1+nameLen]
+		data = data[2+nameLen:]
+
+		var size int
+		switch valueType {
+		case 0, 1: // bool true / false
+			size = 0
+		case 2: // byte
+			size = 1
+		case 3: // short
+			size = 2
+		case 4: // int
+			size = 4
+		case 5, 8: // long / timestamp
+			size = 8
+		case 9: // uuid
+			size = 16
+		case 6, 7: // bytes / string
+			if len(data) < 2 {
+				return nil, fmt.Errorf("event stream header truncated")
+			}
+			size = int(binary.BigEndian.Uint16(data[:2]))
+			data = data[2:]
+		default:
+			return nil, fmt.Errorf("unknown event stream header type: %d", valueType)
+		}
+		if len(data) < size {
+			return nil, fmt.Errorf("event stream header truncated")
+		}
+		if valueType == 7 {
+			headers[name] = string(data[:size])
+		}
+		data = data[size:]
+	}
+	return
+}
@@ -0,0 +1,180 @@
+package bedrock
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/modelgate/modelgate/internal/config"
+	"github.com/modelgate/modelgate/internal/runtime/core"
+	"github.com/modelgate/modelgate/internal/runtime/provider/anthropic"
+	"github.com/modelgate/modelgate/pkg/utils"
+)
+
+// Handler AWS Bedrock 处理器，继承 anthropic.Handler
+// 覆写 BeforeRequest 以实现 InvokeModel 端点拼接与 SigV4 签名：baseUrl/model/{modelId}/invoke[-with-response-stream]
+// 请求/响应均为 Anthropic Messages 格式，非 Claude 模型（如 Llama）在此转换
+type Handler struct {
+	*anthropic.Handler
+}
+
+func NewHandler() *Handler {
+	return &Handler{
+		Handler: anthropic.NewHandler(core.ProviderCodeBedrock),
+	}
+}
+
+func (h *Handler) BeforeRequest(ctx context.Context, c *core.Context) (err error) {
+	modelId := c.CurrentModel.ModelCode
+	var body []byte
+	switch GetModelFamily(modelId) {
+	case ModelFamilyAnthropic:
+		body, err = ConvertAnthropicRequest(c.InputBody)
+	case ModelFamilyLlama:
+		body, err = ConvertLlamaRequest(c.InputBody)
+	default:
+		err = fmt.Errorf("%s unsupported model: %s", h.Provider(), modelId)
+	}
+	if err != nil {
+		return
+	}
+
+	var cfg Config
+	if err = c.CurrentModel.ParseProviderConfig(&cfg); err != nil {
+		return
+	}
+	baseUrl := strings.TrimRight(c.CurrentModel.BaseUrl, "/")
+	region := cfg.Region
+	if region == "" {
+		region = parseRegion(baseUrl)
+	}
+	if region == "" {
+		err = fmt.Errorf("%s region not configured", h.Provider())
+		return
+	}
+
+	action := "invoke"
+	if c.IsStream {
+		action = "invoke-with-response-stream"
+	}
+	endpoint, err := url.Parse(baseUrl + "/model/" + escapeRFC3986(modelId) + "/" + action)
+	if err != nil {
+		return
+	}
+
+	log.Infof("bedrock handler, model: %s, endpoint: %s", modelId, endpoint)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint.String(), bytes.NewReader(body))
+	if err != nil {
+		return
+	}
+	apiKey, err := utils.DecryptAESGCM(c.CurrentModel.ApiKeyEncrypted, []byte(config.GetConfig().Secret.Key))
+	if err != nil {
+		return
+	}
+	cred, err := ParseCredentials(string(apiKey))
+	if err != nil {
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.IsStream {
+		req.Header.Set("Accept", "application/vnd.amazon.eventstream")
+	} else {
+		req.Header.Set("Accept", "application/json")
+	}
+	signRequest(req, body, cred, region, signingService, time.Now())
+	c.HTTPRequest = req
+	return
+}
+
+// parseRegion 从 bedrock-runtime.{region}.amazonaws.com 中解析区域
+func parseRegion(baseUrl string) string {
+	u, err := url.Parse(baseUrl)
+	if err != nil {
+		return ""
+	}
+	parts := strings.Split(u.Hostname(), ".")
+	if len(parts) >= 4 && strings.HasPrefix(parts[0], "bedrock") {
+		return parts[1]
+	}
+	return ""
+}
+
+// DoRequest 发送请求，Llama 响应转换为 Anthropic 格式
+func (h *Handler) DoRequest(ctx context.Context, c *core.Context) (err error) {
+	resp, err := core.ProviderClient(c.CurrentModel).Do(c.HTTPRequest)
+	if err != nil {
+		return
+	}
+	c.HTTPResponse = resp
+
+	defer resp.Body.Close()
+	c.RawResponse, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		log.Error(h.Provider(), string(c.RawResponse))
+		err = h.parseResponseError(resp, c.RawResponse)
+		return
+	}
+	if GetModelFamily(c.CurrentModel.ModelCode) == ModelFamilyLlama {
+		c.RawResponse, err = ConvertLlamaResponse(c.RawResponse, "msg_"+c.RequestUUID.String(), c.CurrentModel.ModelCode)
+		if err != nil {
+			return
+		}
+		// 响应体已改写，原始长度不再适用
+		resp.Header.Del("Content-Length")
+	}
+	return
+}
+
+func (h *Handler) parseResponseError(resp *http.Response, body []byte) error {
+	var respData ErrorResponse
+	if err := json.Unmarshal(body, &respData); err != nil || respData.Message == "" {
+		return fmt.Errorf("%s response error: %s", h.Provider(), string(body))
+	}
+	if errType := resp.Header.Get("X-Amzn-ErrorType"); errType != "" {
+		return fmt.Errorf("%s response error: %s: %s", h.Provider(), strings.Split(errType, ":")[0], respData.Message)
+	}
+	return fmt.Errorf("%s response error: %s", h.Provider(), respData.Message)
+}
+
+// AfterResponse 处理响应结果，Bedrock 返回的 model 为空时使用模型 ID
+func (h *Handler) AfterResponse(ctx context.Context, c *core.Context) (err error) {
+	if err = h.Handler.AfterResponse(ctx, c); err != nil {
+		return
+	}
+	if c.ActualModel == "" {
+		c.ActualModel = c.CurrentModel.ModelCode
+	}
+	return
+}
+
+// DoStream 发送流式请求
+func (h *Handler) DoStream(ctx context.Context, c *core.Context) (stream core.Stream, err error) {
+	resp, err := core.ProviderClient(c.CurrentModel).Do(c.HTTPRequest)
+	if err != nil {
+		return
+	}
+
+	c.HTTPResponse = resp
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		b, _ := io.ReadAll(resp.Body)
+		c.RawResponse = b
+		return nil, h.parseResponseError(resp, b)
+	}
+
+	modelId := c.CurrentModel.ModelCode
+	return NewStreamReceiver(resp.Body, GetModelFamily(modelId), "msg_"+c.RequestUUID.String(), modelId), nil
+}
@@ -0,0 +1,62 @@
+package bedrock
+
+import "encoding/json"
+
+// AnthropicVersion Bedrock 上 Claude 模型要求的 anthropic_version
+const AnthropicVersion = "bedrock-2023-05-31"
+
+// Config Bedrock 供应商扩展配置，对应 Provider.Config
+type Config struct {
+	Region string `json:"region"` // 区域，为空时从 BaseUrl 解析
+}
+
+// ModelFamily 模型系列，不同系列的 InvokeModel 请求体不同
+type ModelFamily string
+
+const (
+	ModelFamilyAnthropic ModelFamily = "anthropic"
+	ModelFamilyLlama     ModelFamily = "llama"
+)
+
+// MessagesRequest Anthropic Messages 请求中需要转换的字段
+type MessagesRequest struct {
+	System        json.RawMessage `json:"system,omitempty"`
+	Messages      []Message       `json:"messages"`
+	MaxTokens     *int64          `json:"max_tokens,omitempty"`
+	Temperature   *float64        `json:"temperature,omitempty"`
+	TopP          *float64        `json:"top_p,omitempty"`
+	StopSequences []string        `json:"stop_sequences,omitempty"`
+}
+
+type Message struct {
+	Role    string          `json:"role"`
+	Content json.RawMessage `json:"content"`
+}
+
+// LlamaRequest Meta Llama InvokeModel 请求
+type LlamaRequest struct {
+	Prompt      string   `json:"prompt"`
+	MaxGenLen   *int64   `json:"max_gen_len,omitempty"`
+	Temperature *float64 `json:"temperature,omitempty"`
+	TopP        *float64 `json:"top_p,omitempty"`
+}
+
+// LlamaResponse Meta Llama InvokeModel 响应，流式响应的每个 chunk 也是该结构
+type LlamaResponse struct {
+	Generation           string             `json:"generation"`
+	PromptTokenCount     int64              `json:"prompt_token_count"`
+	GenerationTokenCount int64              `json:"generation_token_count"`
+	StopReason           *string            `json:"stop_reason"`
+	InvocationMetrics    *InvocationMetrics `json:"amazon-bedrock-invocationMetrics,omitempty"`
+}
+
+// InvocationMetrics 流式响应最后一个 chunk 携带的统计信息
+type InvocationMetrics struct {
+	InputTokenCount  int64 `json:"inputTokenCount"`
+	OutputTokenCount int64 `json:"outputTokenCount"`
+}
+
+// ErrorResponse 错误响应
+type ErrorResponse struct {
+	Message string `json:"message"`
+}
@@ -0,0 +1,148 @@
+package bedrock
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/modelgate/modelgate/internal/runtime/core"
+	"github.com/modelgate/modelgate/internal/runtime/provider/anthropic"
+)
+
+// StreamReceiver 流式接收器，解析 InvokeModelWithResponseStream 返回的 event-stream，
+// 每个 chunk 输出一条 Anthropic Messages 流式事件
+type StreamReceiver struct {
+	decoder *eventStreamDecoder
+	body    io.ReadCloser
+	family  ModelFamily
+	id      string
+	model   string
+	started bool
+	pending []*core.StreamChunk
+}
+
+// NewStreamReceiver 创建流式接收器
+func NewStreamReceiver(body io.ReadCloser, family ModelFamily, id, model string) core.Stream {
+	return &StreamReceiver{
+		decoder: newEventStreamDecoder(body),
+		body:    body,
+		family:  family,
+		id:      id,
+		model:   model,
+	}
+}
+
+// Recv 接收
+func (s *StreamReceiver) Recv() (*core.StreamChunk, error) {
+	for {
+		if len(s.pending) > 0 {
+			chunk := s.pending[0]
+			s.pending = s.pending[1:]
+			return chunk, nil
+		}
+		msg, err := s.decoder.Decode()
+		if err != nil {
+			if err == io.EOF {
+				return &core.StreamChunk{Finish: true}, err
+			}
+			return nil, err
+		}
+		switch msg.Headers[":message-type"] {
+		case "event":
+		case "exception", "error":
+			var respData ErrorResponse
+			_ = json.Unmarshal(msg.Payload, &respData)
+			errType := msg.Headers[":exception-type"]
+			if errType == "" {
+				errType = msg.Headers[":error-code"]
+			}
+			return nil, fmt.Errorf("bedrock stream error: %s: %s", errType, respData.Message)
+		default:
+			continue
+		}
+		if msg.Headers[":event-type"] != "chunk" {
+			continue
+		}
+		var payload struct {
+			Bytes string `json:"bytes"`
+		}
+		if err = json.Unmarshal(msg.Payload, &payload); err != nil {
+			return nil, fmt.Errorf("unmarshal bedrock stream chunk error: %v", err)
+		}
+		data, err := base64.StdEncoding.DecodeString(payload.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("decode bedrock stream chunk error: %v", err)
+		}
+		if s.family != ModelFamilyLlama {
+			var event struct {
+				Type string `json:"type"`
+			}
+			_ = json.Unmarshal(data, &event)
+			return &core.StreamChunk{Event: event.Type, Data: string(data)}, nil
+		}
+		if err = s.convertLlamaChunk(data); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// convertLlamaChunk Llama 流式 chunk 转换为 Anthropic 流式事件
+func (s *StreamReceiver) convertLlamaChunk(data []byte) (err error) {
+	var chunk LlamaResponse
+	if err = json.Unmarshal(data, &chunk); err != nil {
+		return fmt.Errorf("unmarshal llama stream chunk error: %v", err)
+	}
+	if !s.started {
+		s.started = true
+		s.push(map[string]any{
+			"type": anthropic.MessageStart,
+			"message": map[string]any{
+				"id":      s.id,
+				"type":    "message",
+				"role":    "assistant",
+				"model":   s.model,
+				"content": []any{},
+				"usage":   map[string]any{"input_tokens": chunk.PromptTokenCount, "output_tokens": 0},
+			},
+		})
+		s.push(map[string]any{
+			"type":          anthropic.ContentBlockStart,
+			"index":         0,
+			"content_block": map[string]any{"type": "text", "text": ""},
+		})
+	}
+	if chunk.Generation != "" {
+		s.push(map[string]any{
+			"type":  anthropic.ContentBlockDelta,
+			"index": 0,
+			"delta": map[string]any{"type": "text_delta", "text": chunk.Generation},
+		})
+	}
+	if chunk.StopReason == nil {
+		return
+	}
+	usage := map[string]any{"output_tokens": chunk.GenerationTokenCount}
+	if metrics := chunk.InvocationMetrics; metrics != nil {
+		usage["input_tokens"] = metrics.InputTokenCount
+		usage["output_tokens"] = metrics.OutputTokenCount
+	}
+	s.push(map[string]any{"type": anthropic.ContentBlockStop, "index": 0})
+	s.push(map[string]any{
+		"type":  anthropic.MessageDelta,
+		"delta": map[string]any{"stop_reason": convertLlamaStopReason(chunk.StopReason), "stop_sequence": nil},
+		"usage": usage,
+	})
+	s.push(map[string]any{"type": anthropic.MessageStop})
+	return
+}
+
+func (s *StreamReceiver) push(event map[string]any) {
+	data, _ := json.Marshal(event)
+	s.pending = append(s.pending, &core.StreamChunk{Event: event["type"].(string), Data: string(data)})
+}
+
+// Close 关闭
+func (s *StreamReceiver) Close() error {
+	return s.body.Close()
+}
@@ -0,0 +1,18 @@
+package cohere
+
+import (
+	"github.com/samber/do/v2"
+
+	"github.com/modelgate/modelgate/internal/runtime/core"
+)
+
+func Init(i do.Injector) {
+	handler := NewHandler(core.ProviderCodeCohere)
+
+	core.ExecutorRegistry.Register(core.ProviderCodeCohere, func(opts core.Options) (core.Executor, error) {
+		if opts.Endpoint != core.EndpointRerank {
+			return nil, core.ErrEndpointNotSupported(core.ProviderCodeCohere, opts.Endpoint)
+		}
+		return core.ExecutorRegistry.NewExecutor(handler, opts)
+	})
+}
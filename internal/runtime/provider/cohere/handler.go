@@ -0,0 +1,118 @@
+package cohere
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/samber/lo"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/modelgate/modelgate/internal/config"
+	"github.com/modelgate/modelgate/internal/runtime/core"
+	"github.com/modelgate/modelgate/pkg/utils"
+)
+
+type Handler struct {
+	provider string
+}
+
+func NewHandler(provider string) *Handler {
+	return &Handler{
+		provider: provider,
+	}
+}
+
+func (h *Handler) Provider() string {
+	return h.provider
+}
+
+// BeforeRequest 构建请求参数，转换为 Cohere v2 重排序请求
+func (h *Handler) BeforeRequest(ctx context.Context, c *core.Context) (err error) {
+	var rerankReq core.RerankRequest
+	if err = json.Unmarshal(c.InputBody, &rerankReq); err != nil {
+		return
+	}
+	body, err := json.Marshal(&RerankRequest{
+		Model:     c.CurrentModel.ModelCode,
+		Query:     rerankReq.Query,
+		Documents: rerankReq.DocumentTexts(),
+		TopN:      rerankReq.TopN,
+	})
+	if err != nil {
+		return
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", c.CurrentModel.BaseUrl+"/v2/rerank", bytes.NewReader(body))
+	if err != nil {
+		return
+	}
+
+	apiKey, err := utils.DecryptAESGCM(c.CurrentModel.ApiKeyEncrypted, []byte(config.GetConfig().Secret.Key))
+	if err != nil {
+		return
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", apiKey))
+	req.Header.Set("Content-Type", "application/json")
+	c.HTTPRequest = req
+	return
+}
+
+// DoRequest 发送请求，并处理结果
+func (h *Handler) DoRequest(ctx context.Context, c *core.Context) (err error) {
+	resp, err := core.ProviderClient(c.CurrentModel).Do(c.HTTPRequest)
+	if err != nil {
+		return
+	}
+	c.HTTPResponse = resp
+
+	defer resp.Body.Close()
+	c.RawResponse, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		log.Error(h.provider, string(c.RawResponse))
+		var respData ErrorResponse
+		if json.Unmarshal(c.RawResponse, &respData) == nil && respData.Message != "" {
+			return fmt.Errorf("%s response error: %s", h.provider, respData.Message)
+		}
+		return fmt.Errorf("%s response error: %s", h.provider, string(c.RawResponse))
+	}
+	return
+}
+
+// AfterResponse 处理响应结果，按搜索单元计费；v2 不再返回文档，按 return_documents 补全
+func (h *Handler) AfterResponse(ctx context.Context, c *core.Context) (err error) {
+	var respData RerankResponse
+	if err = json.Unmarshal(c.RawResponse, &respData); err != nil {
+		return
+	}
+	if respData.Meta != nil && respData.Meta.BilledUnits != nil && respData.Meta.BilledUnits.SearchUnits > 0 {
+		c.Units = respData.Meta.BilledUnits.SearchUnits
+	}
+
+	var rerankReq core.RerankRequest
+	if err = json.Unmarshal(c.InputBody, &rerankReq); err != nil {
+		return
+	}
+	if !lo.FromPtr(rerankReq.ReturnDocuments) {
+		return
+	}
+	texts := rerankReq.DocumentTexts()
+	for i, result := range respData.Results {
+		if result.Document == nil && result.Index >= 0 && result.Index < len(texts) {
+			respData.Results[i].Document = &RerankDocument{Text: texts[result.Index]}
+		}
+	}
+	c.RawResponse, err = json.Marshal(&respData)
+	return
+}
+
+// DoStream 重排序不支持流式
+func (h *Handler) DoStream(ctx context.Context, c *core.Context) (stream core.Stream, err error) {
+	return nil, fmt.Errorf("%s does not support stream", h.provider)
+}
@@ -0,0 +1,42 @@
+package cohere
+
+import "encoding/json"
+
+// RerankRequest Cohere v2 重排序请求，documents 只支持字符串
+type RerankRequest struct {
+	Model     string   `json:"model"`
+	Query     string   `json:"query"`
+	Documents []string `json:"documents"`
+	TopN      *int     `json:"top_n,omitempty"`
+}
+
+// RerankResponse Cohere 重排序响应
+type RerankResponse struct {
+	Id      string         `json:"id,omitempty"`
+	Results []RerankResult `json:"results"`
+	Meta    *Meta          `json:"meta,omitempty"`
+}
+
+type RerankResult struct {
+	Index          int             `json:"index"`
+	RelevanceScore float64         `json:"relevance_score"`
+	Document       *RerankDocument `json:"document,omitempty"`
+}
+
+type RerankDocument struct {
+	Text string `json:"text"`
+}
+
+type Meta struct {
+	ApiVersion  json.RawMessage `json:"api_version,omitempty"`
+	BilledUnits *BilledUnits    `json:"billed_units,omitempty"`
+}
+
+type BilledUnits struct {
+	SearchUnits int64 `json:"search_units,omitempty"`
+}
+
+// ErrorResponse Cohere 错误响应
+type ErrorResponse struct {
+	Message string `json:"message"`
+}
@@ -0,0 +1,314 @@
+package gemini
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/modelgate/modelgate/internal/runtime/core"
+	"github.com/modelgate/modelgate/internal/runtime/provider/openai"
+)
+
+// ConvertRequest 将 OpenAI Chat Completions 请求转换为 generateContent 请求
+func ConvertRequest(data []byte) (req *Request, err error) {
+	var oaiReq openai.ChatCompletionRequest
+	if err = json.Unmarshal(data, &oaiReq); err != nil {
+		return
+	}
+	req = &Request{}
+	// tool_call_id -> 函数名，functionResponse 需要函数名
+	toolNames := make(map[string]string)
+	for _, msg := range oaiReq.Messages {
+		switch msg.Role {
+		case "system", "developer":
+			if req.SystemInstruction == nil {
+				req.SystemInstruction = &Content{}
+			}
+			req.SystemInstruction.Parts = append(req.SystemInstruction.Parts, Part{Text: msg.Content.String()})
+		case "assistant":
+			content := Content{Role: RoleModel}
+			if text := msg.Content.String(); text != "" {
+				content.Parts = append(content.Parts, Part{Text: text})
+			}
+			for _, toolCall := range msg.ToolCalls {
+				toolNames[toolCall.Id] = toolCall.Function.Name
+				content.Parts = append(content.Parts, Part{FunctionCall: &FunctionCall{
+					Name: toolCall.Function.Name,
+					Args: rawJSONOrEmpty(toolCall.Function.Arguments),
+				}})
+			}
+			req.appendContent(content)
+		case "tool":
+			response, _ := json.Marshal(map[string]any{"content": msg.Content.String()})
+			req.appendContent(Content{Role: RoleUser, Parts: []Part{{FunctionResponse: &FunctionResponse{
+				Name:     toolNames[msg.ToolCallId],
+				Response: response,
+			}}}})
+		default:
+			var parts []Part
+			parts, err = convertUserContent(msg.Content)
+			if err != nil {
+				return
+			}
+			req.appendContent(Content{Role: RoleUser, Parts: parts})
+		}
+	}
+
+	config := &GenerationConfig{
+		Temperature:     oaiReq.Temperature,
+		TopP:            oaiReq.TopP,
+		CandidateCount:  oaiReq.N,
+		MaxOutputTokens: oaiReq.GetMaxTokens(),
+		StopSequences:   oaiReq.GetStop(),
+	}
+	if format := oaiReq.ResponseFormat; format != nil && format.Type != "text" {
+		config.ResponseMimeType = "application/json"
+		if len(format.JsonSchema) > 0 {
+			var schema struct {
+				Schema json.RawMessage `json:"schema"`
+			}
+			if json.Unmarshal(format.JsonSchema, &schema) == nil {
+				config.ResponseSchema = schema.Schema
+			}
+		}
+	}
+	req.GenerationConfig = config
+
+	if len(oaiReq.Tools) > 0 {
+		tool := Tool{}
+		for _, t := range oaiReq.Tools {
+			tool.FunctionDeclarations = append(tool.FunctionDeclarations, FunctionDeclaration{
+				Name:        t.Function.Name,
+				Description: t.Function.Description,
+				Parameters:  t.Function.Parameters,
+			})
+		}
+		req.Tools = []Tool{tool}
+		req.ToolConfig = convertToolChoice(oaiReq.ToolChoice)
+	}
+	return
+}
+
+// appendContent Gemini 要求 user / model 交替出现，相同角色的连续消息合并
+func (r *Request) appendContent(content Content) {
+	if len(content.Parts) == 0 {
+		return
+	}
+	if n := len(r.Contents); n > 0 && r.Contents[n-1].Role == content.Role {
+		r.Contents[n-1].Parts = append(r.Contents[n-1].Parts, content.Parts...)
+		return
+	}
+	r.Contents = append(r.Contents, content)
+}
+
+func convertUserContent(content openai.MessageContent) (parts []Part, err error) {
+	if content.Parts == nil {
+		return []Part{{Text: content.Text}}, nil
+	}
+	for _, item := range content.Parts {
+		switch item.Type {
+		case openai.ContentPartTypeText:
+			parts = append(parts, Part{Text: item.Text})
+		case openai.ContentPartTypeImageUrl:
+			if item.ImageUrl == nil {
+				continue
+			}
+			var part Part
+			part, err = convertImageUrl(item.ImageUrl.Url)
+			if err != nil {
+				return
+			}
+			parts = append(parts, part)
+		}
+	}
+	return
+}
+
+// convertImageUrl data URL 转为 inlineData，其余作为 fileData 传递
+func convertImageUrl(url string) (part Part, err error) {
+	if !strings.HasPrefix(url, "data:") {
+		part.FileData = &FileData{FileUri: url}
+		return
+	}
+	// data:image/png;base64,xxxx
+	meta, data, ok := strings.Cut(strings.TrimPrefix(url, "data:"), ",")
+	if !ok || !strings.HasSuffix(meta, ";base64") {
+		err = fmt.Errorf("invalid image data url")
+		return
+	}
+	part.InlineData = &Blob{
+		MimeType: strings.TrimSuffix(meta, ";base64"),
+		Data:     data,
+	}
+	return
+}
+
+func convertToolChoice(data json.RawMessage) *ToolConfig {
+	if len(data) == 0 {
+		return nil
+	}
+	var choice string
+	if err := json.Unmarshal(data, &choice); err == nil {
+		switch choice {
+		case "none":
+			return &ToolConfig{FunctionCallingConfig: &FunctionCallingConfig{Mode: "NONE"}}
+		case "required":
+			return &ToolConfig{FunctionCallingConfig: &FunctionCallingConfig{Mode: "ANY"}}
+		default:
+			return &ToolConfig{FunctionCallingConfig: &FunctionCallingConfig{Mode: "AUTO"}}
+		}
+	}
+	var named openai.Tool
+	if err := json.Unmarshal(data, &named); err == nil && named.Function.Name != "" {
+		return &ToolConfig{FunctionCallingConfig: &FunctionCallingConfig{
+			Mode:                 "ANY",
+			AllowedFunctionNames: []string{named.Function.Name},
+		}}
+	}
+	return nil
+}
+
+func rawJSONOrEmpty(s string) json.RawMessage {
+	if s == "" || !json.Valid([]byte(s)) {
+		return json.RawMessage("{}")
+	}
+	return json.RawMessage(s)
+}
+
+// ConvertResponse 将 generateContent 响应转换为 OpenAI Chat Completions 响应
+func ConvertResponse(resp *Response, model string) *openai.ChatCompletionResponse {
+	out := &openai.ChatCompletionResponse{
+		Id:      "chatcmpl-" + resp.ResponseId,
+		Object:  openai.ObjectChatCompletion,
+		Created: time.Now().Unix(),
+		Model:   model,
+		Usage:   ConvertUsage(resp.UsageMetadata),
+	}
+	if resp.ModelVersion != "" {
+		out.Model = resp.ModelVersion
+	}
+	for _, candidate := range resp.Candidates {
+		message, finishReason := convertCandidate(candidate, false)
+		out.Choices = append(out.Choices, openai.Choice{
+			Index:        candidate.Index,
+			Message:      message,
+			FinishReason: finishReason,
+		})
+	}
+	return out
+}
+
+// ConvertChunk 将流式事件转换为 OpenAI chat.completion.chunk
+func ConvertChunk(resp *Response, id string, created int64, model string) *openai.ChatCompletionResponse {
+	out := &openai.ChatCompletionResponse{
+		Id:      id,
+		Object:  openai.ObjectChatCompletionChunk,
+		Created: created,
+		Model:   model,
+	}
+	if resp.ModelVersion != "" {
+		out.Model = resp.ModelVersion
+	}
+	var finished bool
+	for _, candidate := range resp.Candidates {
+		delta, finishReason := convertCandidate(candidate, true)
+		out.Choices = append(out.Choices, openai.Choice{
+			Index:        candidate.Index,
+			Delta:        delta,
+			FinishReason: finishReason,
+		})
+		finished = finished || finishReason != nil
+	}
+	// usageMetadata 为累计值，只在最后一个事件中输出，与 OpenAI 行为保持一致
+	if finished {
+		out.Usage = ConvertUsage(resp.UsageMetadata)
+	}
+	return out
+}
+
+func convertCandidate(candidate Candidate, isDelta bool) (message *openai.ChoiceMessage, finishReason *string) {
+	message = &openai.ChoiceMessage{Role: "assistant"}
+	var text strings.Builder
+	var hasText bool
+	for i, part := range candidate.Content.Parts {
+		switch {
+		case part.FunctionCall != nil:
+			args := string(part.FunctionCall.Args)
+			if args == "" {
+				args = "{}"
+			}
+			toolCall := openai.ToolCall{
+				Id:   fmt.Sprintf("call_%s_%d", part.FunctionCall.Name, i),
+				Type: "function",
+				Function: openai.ToolCallFunction{
+					Name:      part.FunctionCall.Name,
+					Arguments: args,
+				},
+			}
+			if isDelta {
+				toolCall.Index = new(int64)
+				*toolCall.Index = int64(len(message.ToolCalls))
+			}
+			message.ToolCalls = append(message.ToolCalls, toolCall)
+		case part.Thought:
+			// 思考内容不输出
+		default:
+			hasText = true
+			text.WriteString(part.Text)
+		}
+	}
+	if hasText || !isDelta {
+		content := text.String()
+		message.Content = &content
+	}
+	if candidate.FinishReason != "" {
+		reason := convertFinishReason(candidate.FinishReason)
+		if len(message.ToolCalls) > 0 {
+			reason = openai.FinishReasonToolCalls
+		}
+		finishReason = &reason
+	}
+	return
+}
+
+func convertFinishReason(reason string) string {
+	switch reason {
+	case FinishReasonStop:
+		return openai.FinishReasonStop
+	case FinishReasonMaxTokens:
+		return openai.FinishReasonLength
+	default:
+		// SAFETY、RECITATION、BLOCKLIST 等
+		return openai.FinishReasonContentFilter
+	}
+}
+
+// ConvertUsage 思考 token 按输出计费
+func ConvertUsage(usage *UsageMetadata) *openai.Usage {
+	if usage == nil {
+		return nil
+	}
+	out := &openai.Usage{
+		PromptTokens:     usage.PromptTokenCount,
+		CompletionTokens: usage.CandidatesTokenCount + usage.ThoughtsTokenCount,
+		TotalTokens:      usage.TotalTokenCount,
+	}
+	if usage.CachedContentTokenCount > 0 {
+		out.PromptTokensDetails = &openai.PromptTokensDetails{CachedTokens: usage.CachedContentTokenCount}
+	}
+	return out
+}
+
+// ToCoreUsage 转换为 core.Usage
+func ToCoreUsage(usage *UsageMetadata) *core.Usage {
+	if usage == nil || usage.TotalTokenCount == 0 {
+		return nil
+	}
+	return &core.Usage{
+		PromptTokens:       usage.PromptTokenCount,
+		PromptCachedTokens: usage.CachedContentTokenCount,
+		CompletionTokens:   usage.CandidatesTokenCount + usage.ThoughtsTokenCount,
+		TotalTokens:        usage.TotalTokenCount,
+	}
+}
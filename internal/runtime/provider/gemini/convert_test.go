@@ -0,0 +1,114 @@
+package gemini
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestConvertRequest(t *testing.T) {
+	body := `{
+		"model": "gemini-2.5-flash",
+		"messages": [
+			{"role": "system", "content": "you are a helpful assistant"},
+			{"role": "user", "content": [
+				{"type": "text", "text": "what is in this image?"},
+				{"type": "image_url", "image_url": {"url": "data:image/png;base64,aGVsbG8="}}
+			]},
+			{"role": "assistant", "content": null, "tool_calls": [
+				{"id": "call_1", "type": "function", "function": {"name": "get_weather", "arguments": "{\"city\":\"beijing\"}"}}
+			]},
+			{"role": "tool", "tool_call_id": "call_1", "content": "sunny"}
+		],
+		"max_tokens": 100,
+		"stop": "END",
+		"tools": [{"type": "function", "function": {"name": "get_weather", "parameters": {"type": "object"}}}],
+		"tool_choice": "required"
+	}`
+	req, err := ConvertRequest([]byte(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.SystemInstruction == nil || req.SystemInstruction.Parts[0].Text != "you are a helpful assistant" {
+		t.Fatalf("unexpected system instruction: %+v", req.SystemInstruction)
+	}
+	if len(req.Contents) != 3 {
+		t.Fatalf("unexpected contents: %+v", req.Contents)
+	}
+	if blob := req.Contents[0].Parts[1].InlineData; blob == nil || blob.MimeType != "image/png" || blob.Data != "aGVsbG8=" {
+		t.Fatalf("unexpected inline data: %+v", blob)
+	}
+	if req.Contents[1].Role != RoleModel || req.Contents[1].Parts[0].FunctionCall.Name != "get_weather" {
+		t.Fatalf("unexpected model content: %+v", req.Contents[1])
+	}
+	if resp := req.Contents[2].Parts[0].FunctionResponse; resp == nil || resp.Name != "get_weather" {
+		t.Fatalf("unexpected function response: %+v", resp)
+	}
+	if *req.GenerationConfig.MaxOutputTokens != 100 || req.GenerationConfig.StopSequences[0] != "END" {
+		t.Fatalf("unexpected generation config: %+v", req.GenerationConfig)
+	}
+	if req.ToolConfig.FunctionCallingConfig.Mode != "ANY" {
+		t.Fatalf("unexpected tool config: %+v", req.ToolConfig)
+	}
+}
+
+func TestConvertResponse(t *testing.T) {
+	body := `{
+		"candidates": [{"content": {"role": "model", "parts": [{"text": "hello"}]}, "finishReason": "MAX_TOKENS", "index": 0}],
+		"usageMetadata": {"promptTokenCount": 10, "candidatesTokenCount": 5, "thoughtsTokenCount": 3, "totalTokenCount": 18},
+		"modelVersion": "gemini-2.5-flash"
+	}`
+	var resp Response
+	if err := json.Unmarshal([]byte(body), &resp); err != nil {
+		t.Fatal(err)
+	}
+	out := ConvertResponse(&resp, "gemini")
+	if *out.Choices[0].Message.Content != "hello" || *out.Choices[0].FinishReason != "length" {
+		t.Fatalf("unexpected choice: %+v", out.Choices[0])
+	}
+	if out.Usage.PromptTokens != 10 || out.Usage.CompletionTokens != 8 {
+		t.Fatalf("unexpected usage: %+v", out.Usage)
+	}
+}
+
+func TestStreamReceiver(t *testing.T) {
+	body := "data: {\"candidates\": [{\"content\": {\"parts\": [{\"text\": \"hel\"}]}}], \"usageMetadata\": {\"promptTokenCount\": 10, \"totalTokenCount\": 10}}\r\n\r\n" +
+		"data: {\"candidates\": [{\"content\": {\"parts\": [{\"text\": \"lo\"}]}, \"finishReason\": \"STOP\"}], \"usageMetadata\": {\"promptTokenCount\": 10, \"candidatesTokenCount\": 2, \"totalTokenCount\": 12}}\r\n\r\n"
+	stream := NewStreamReceiver(io.NopCloser(strings.NewReader(body)), "gemini-2.5-flash")
+
+	var usageChunks int
+	var text strings.Builder
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		var data struct {
+			Choices []struct {
+				Delta struct {
+					Content string `json:"content"`
+				} `json:"delta"`
+			} `json:"choices"`
+			Usage *struct {
+				TotalTokens int64 `json:"total_tokens"`
+			} `json:"usage"`
+		}
+		if err = json.Unmarshal([]byte(chunk.Data), &data); err != nil {
+			t.Fatal(err)
+		}
+		text.WriteString(data.Choices[0].Delta.Content)
+		if data.Usage != nil {
+			usageChunks++
+			if data.Usage.TotalTokens != 12 {
+				t.Fatalf("unexpected usage: %+v", data.Usage)
+			}
+		}
+	}
+	if text.String() != "hello" || usageChunks != 1 {
+		t.Fatalf("unexpected stream result: %s, usage chunks: %d", text.String(), usageChunks)
+	}
+}
@@ -0,0 +1,22 @@
+package gemini
+
+import (
+	"github.com/samber/do/v2"
+
+	"github.com/modelgate/modelgate/internal/runtime/core"
+)
+
+func Init(i do.Injector) {
+	handler := NewHandler(core.ProviderCodeGemini)
+
+	core.ExecutorRegistry.Register(core.ProviderCodeGemini, func(opts core.Options) (core.Executor, error) {
+		if !opts.Endpoint.IsChat() {
+			return nil, core.ErrEndpointNotSupported(core.ProviderCodeGemini, opts.Endpoint)
+		}
+		h, err := core.Translate(handler, core.ProtocolOpenAI, opts.Protocol)
+		if err != nil {
+			return nil, err
+		}
+		return core.ExecutorRegistry.NewExecutor(h, opts)
+	})
+}
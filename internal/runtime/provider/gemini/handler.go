@@ -0,0 +1,135 @@
+package gemini
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/modelgate/modelgate/internal/config"
+	"github.com/modelgate/modelgate/internal/runtime/core"
+	"github.com/modelgate/modelgate/pkg/utils"
+)
+
+type Handler struct {
+	provider string
+}
+
+func NewHandler(provider string) *Handler {
+	return &Handler{
+		provider: provider,
+	}
+}
+
+func (h *Handler) Provider() string {
+	return h.provider
+}
+
+// BeforeRequest 构建请求参数，OpenAI 格式请求转换为 generateContent 请求
+func (h *Handler) BeforeRequest(ctx context.Context, c *core.Context) (err error) {
+	geminiReq, err := ConvertRequest(c.InputBody)
+	if err != nil {
+		err = fmt.Errorf("convert %s request error: %v", h.provider, err)
+		return
+	}
+	body, err := json.Marshal(geminiReq)
+	if err != nil {
+		return
+	}
+
+	endpoint := fmt.Sprintf("%s/v1beta/models/%s:generateContent", c.CurrentModel.BaseUrl, c.CurrentModel.ModelCode)
+	if c.IsStream {
+		endpoint = fmt.Sprintf("%s/v1beta/models/%s:streamGenerateContent?alt=sse", c.CurrentModel.BaseUrl, c.CurrentModel.ModelCode)
+	}
+	req, err := http.NewRequestWithContext(
+		ctx,
+		"POST",
+		endpoint,
+		bytes.NewReader(body),
+	)
+	if err != nil {
+		return
+	}
+
+	apiKey, err := utils.DecryptAESGCM(c.CurrentModel.ApiKeyEncrypted, []byte(config.GetConfig().Secret.Key))
+	if err != nil {
+		return
+	}
+	req.Header.Set("x-goog-api-key", string(apiKey))
+	req.Header.Set("Content-Type", "application/json")
+	c.HTTPRequest = req
+	return
+}
+
+// DoRequest 发送请求，并处理结果
+func (h *Handler) DoRequest(ctx context.Context, c *core.Context) (err error) {
+	resp, err := core.ProviderClient(c.CurrentModel).Do(c.HTTPRequest)
+	if err != nil {
+		return
+	}
+	c.HTTPResponse = resp
+
+	defer resp.Body.Close()
+	c.RawResponse, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		log.Error(h.provider, string(c.RawResponse))
+		err = h.parseResponseError(c.RawResponse)
+		return
+	}
+	return
+}
+
+func (h *Handler) parseResponseError(body []byte) (err error) {
+	var respData Error
+	if err = json.Unmarshal(body, &respData); err != nil || respData.Error.Message == "" {
+		err = fmt.Errorf("%s response error: %s", h.provider, string(body))
+		return
+	}
+	err = fmt.Errorf("%s response error: %s", h.provider, respData.Error.Message)
+	return
+}
+
+// AfterResponse 处理响应结果，响应转换为 OpenAI 格式
+func (h *Handler) AfterResponse(ctx context.Context, c *core.Context) (err error) {
+	var respData Response
+	if err = json.Unmarshal(c.RawResponse, &respData); err != nil {
+		err = fmt.Errorf("unmarshal response error: %v", err)
+		return
+	}
+	c.ActualModel = respData.ModelVersion
+	c.Usage = ToCoreUsage(respData.UsageMetadata)
+
+	c.RawResponse, err = json.Marshal(ConvertResponse(&respData, c.CurrentModel.ModelCode))
+	if err != nil {
+		return
+	}
+	// 响应体已改写，原始长度不再适用
+	c.HTTPResponse.Header.Del("Content-Length")
+	return
+}
+
+// DoStream 发送流式请求
+func (h *Handler) DoStream(ctx context.Context, c *core.Context) (stream core.Stream, err error) {
+	resp, err := core.ProviderClient(c.CurrentModel).Do(c.HTTPRequest)
+	if err != nil {
+		return
+	}
+
+	c.HTTPResponse = resp
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		b, _ := io.ReadAll(resp.Body)
+		c.RawResponse = b
+		return nil, h.parseResponseError(b)
+	}
+
+	return NewStreamReceiver(resp.Body, c.CurrentModel.ModelCode), nil
+}
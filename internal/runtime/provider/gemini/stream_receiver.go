@@ -0,0 +1,70 @@
+package gemini
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/modelgate/modelgate/internal/runtime/core"
+)
+
+// StreamReceiver 流式接收器，将 Gemini SSE 事件转换为 OpenAI chat.completion.chunk
+type StreamReceiver struct {
+	reader  *bufio.Reader
+	body    io.ReadCloser
+	id      string
+	created int64
+	model   string
+}
+
+// NewStreamReceiver 创建流式接收器
+func NewStreamReceiver(body io.ReadCloser, model string) core.Stream {
+	now := time.Now()
+	return &StreamReceiver{
+		reader:  bufio.NewReader(body),
+		body:    body,
+		id:      fmt.Sprintf("chatcmpl-%d", now.UnixNano()),
+		created: now.Unix(),
+		model:   model,
+	}
+}
+
+// Recv 接收
+func (s *StreamReceiver) Recv() (*core.StreamChunk, error) {
+	for {
+		line, err := s.reader.ReadString('\n')
+		if err != nil {
+			if err == io.EOF {
+				return &core.StreamChunk{Finish: true}, err
+			}
+			return nil, err
+		}
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "data:") {
+			continue
+		}
+		payload := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		if payload == "" {
+			continue
+		}
+		var resp Response
+		if err = json.Unmarshal([]byte(payload), &resp); err != nil {
+			return nil, fmt.Errorf("unmarshal gemini stream chunk error: %v", err)
+		}
+		data, err := json.Marshal(ConvertChunk(&resp, s.id, s.created, s.model))
+		if err != nil {
+			return nil, err
+		}
+		return &core.StreamChunk{
+			Data: string(data),
+		}, nil
+	}
+}
+
+// Close 关闭
+func (s *StreamReceiver) Close() error {
+	return s.body.Close()
+}
@@ -0,0 +1,21 @@
+package jina
+
+import (
+	"github.com/samber/do/v2"
+
+	"github.com/modelgate/modelgate/internal/runtime/core"
+	"github.com/modelgate/modelgate/internal/runtime/provider/openai"
+)
+
+// Init Jina AI 的 embeddings、rerank 接口与 OpenAI 格式兼容，复用 openai.Handler
+func Init(i do.Injector) {
+	handler := openai.NewHandler(core.ProviderCodeJina)
+
+	core.ExecutorRegistry.Register(core.ProviderCodeJina, func(opts core.Options) (core.Executor, error) {
+		switch opts.Endpoint {
+		case core.EndpointEmbeddings, core.EndpointRerank:
+			return core.ExecutorRegistry.NewExecutor(handler, opts)
+		}
+		return nil, core.ErrEndpointNotSupported(core.ProviderCodeJina, opts.Endpoint)
+	})
+}
@@ -34,7 +34,11 @@ func (h *OpenAIHandler) BeforeRequest(ctx context.Context, c *core.Context) (err
 
 	log.Infof("minimax openai handler, model: %s, endpoint: %s", c.CurrentModel.ModelCode, endpoint)
 
-	req, err := http.NewRequest("POST", endpoint, bytes.NewReader(c.InputBody))
+	body, err := c.RequestBody()
+	if err != nil {
+		return
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(body))
 	if err != nil {
 		return
 	}
@@ -43,7 +47,7 @@ func (h *OpenAIHandler) BeforeRequest(ctx context.Context, c *core.Context) (err
 		return
 	}
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", apiKey))
-	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Content-Type", c.GetContentType())
 	c.HTTPRequest = req
 	return
 }
@@ -66,7 +70,11 @@ func (h *AnthropicHandler) BeforeRequest(ctx context.Context, c *core.Context) (
 
 	log.Infof("minimax anthropic handler, model: %s, endpoint: %s", c.CurrentModel.ModelCode, endpoint)
 
-	req, err := http.NewRequest("POST", endpoint, bytes.NewReader(c.InputBody))
+	body, err := c.RequestBody()
+	if err != nil {
+		return
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(body))
 	if err != nil {
 		return
 	}
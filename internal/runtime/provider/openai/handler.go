@@ -6,9 +6,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
 
 	"github.com/openai/openai-go"
+	"github.com/samber/lo"
 	log "github.com/sirupsen/logrus"
 
 	"github.com/modelgate/modelgate/internal/config"
@@ -33,10 +35,15 @@ func (h *Handler) Provider() string {
 // BeforeRequest 构建请求参数
 func (h *Handler) BeforeRequest(ctx context.Context, c *core.Context) (err error) {
 	endpoint := c.CurrentModel.BaseUrl + c.UrlPath
-	req, err := http.NewRequest(
+	body, err := c.RequestBody()
+	if err != nil {
+		return
+	}
+	req, err := http.NewRequestWithContext(
+		ctx,
 		"POST",
 		endpoint,
-		bytes.NewReader(c.InputBody),
+		bytes.NewReader(body),
 	)
 	if err != nil {
 		return
@@ -47,20 +54,26 @@ func (h *Handler) BeforeRequest(ctx context.Context, c *core.Context) (err error
 		return
 	}
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", apiKey))
-	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Content-Type", c.GetContentType())
 	c.HTTPRequest = req
 	return
 }
 
 // DoRequest 发送请求，并处理结果
 func (h *Handler) DoRequest(ctx context.Context, c *core.Context) (err error) {
-	resp, err := core.HttpClient.Do(c.HTTPRequest)
+	resp, err := core.ProviderClient(c.CurrentModel).Do(c.HTTPRequest)
 	if err != nil {
 		return
 	}
 	c.HTTPResponse = resp
 
 	defer resp.Body.Close()
+	// 二进制响应直接透传给客户端，不缓存
+	if resp.StatusCode == http.StatusOK && c.BinaryWriter != nil {
+		c.BinaryWriter.WriteHeader(resp.StatusCode, resp.Header)
+		_, err = io.Copy(c.BinaryWriter, resp.Body)
+		return
+	}
 	c.RawResponse, err = io.ReadAll(resp.Body)
 	if err != nil {
 		return
@@ -100,14 +113,33 @@ func (h *Handler) parseResponseError(_ context.Context, c *core.Context) (err er
 
 // AfterResponse 处理响应结果
 func (h *Handler) AfterResponse(ctx context.Context, c *core.Context) (err error) {
+	switch c.Endpoint {
+	case core.EndpointSpeech:
+		// 音频已写给客户端，按请求字符数计费
+		return
+	case core.EndpointAudio:
+		return h.afterAudio(c)
+	}
 	var respData struct {
-		Model string                 `json:"model"`
-		Usage openai.CompletionUsage `json:"usage"`
+		Object string                 `json:"object"`
+		Model  string                 `json:"model"`
+		Usage  openai.CompletionUsage `json:"usage"`
 	}
 	if err = json.Unmarshal(c.RawResponse, &respData); err != nil {
 		return
 	}
 	c.ActualModel = respData.Model
+	if respData.Object == ObjectResponse {
+		return h.afterResponses(c)
+	}
+	switch c.Endpoint {
+	case core.EndpointEmbeddings:
+		return h.afterEmbeddings(c)
+	case core.EndpointImages:
+		return h.afterImages(c)
+	case core.EndpointRerank:
+		return h.afterRerank(c)
+	}
 	if respData.Usage.TotalTokens > 0 {
 		c.Usage = &core.Usage{
 			PromptTokens:       respData.Usage.PromptTokens,
@@ -119,19 +151,138 @@ func (h *Handler) AfterResponse(ctx context.Context, c *core.Context) (err error
 	return
 }
 
+// afterResponses Responses API 的 usage 格式与 Chat Completions 不同
+func (h *Handler) afterResponses(c *core.Context) (err error) {
+	var respData Response
+	if err = json.Unmarshal(c.RawResponse, &respData); err != nil {
+		return
+	}
+	c.Usage = ResponsesUsageToCore(respData.Usage)
+	return
+}
+
+// afterEmbeddings embeddings 只有输入 token，部分上游只返回 total_tokens
+func (h *Handler) afterEmbeddings(c *core.Context) (err error) {
+	var respData struct {
+		Usage struct {
+			PromptTokens int64 `json:"prompt_tokens"`
+			TotalTokens  int64 `json:"total_tokens"`
+		} `json:"usage"`
+	}
+	if err = json.Unmarshal(c.RawResponse, &respData); err != nil {
+		return
+	}
+	promptTokens := lo.Ternary(respData.Usage.PromptTokens > 0, respData.Usage.PromptTokens, respData.Usage.TotalTokens)
+	if promptTokens > 0 {
+		c.Usage = &core.Usage{
+			PromptTokens: promptTokens,
+			TotalTokens:  promptTokens,
+		}
+	}
+	return
+}
+
+// afterRerank Jina 和自部署重排序服务返回 token 用量，Cohere 兼容服务返回搜索单元
+func (h *Handler) afterRerank(c *core.Context) (err error) {
+	var respData struct {
+		Usage struct {
+			PromptTokens int64 `json:"prompt_tokens"`
+			TotalTokens  int64 `json:"total_tokens"`
+		} `json:"usage"`
+		Meta struct {
+			BilledUnits struct {
+				SearchUnits int64 `json:"search_units"`
+			} `json:"billed_units"`
+		} `json:"meta"`
+	}
+	if err = json.Unmarshal(c.RawResponse, &respData); err != nil {
+		return
+	}
+	promptTokens := lo.Ternary(respData.Usage.PromptTokens > 0, respData.Usage.PromptTokens, respData.Usage.TotalTokens)
+	if promptTokens > 0 {
+		c.Usage = &core.Usage{
+			PromptTokens: promptTokens,
+			TotalTokens:  promptTokens,
+		}
+	}
+	if respData.Meta.BilledUnits.SearchUnits > 0 {
+		c.Units = respData.Meta.BilledUnits.SearchUnits
+	}
+	return
+}
+
+// afterImages 按实际返回的图片张数计费，gpt-image 模型同时返回 token 用量
+func (h *Handler) afterImages(c *core.Context) (err error) {
+	var respData struct {
+		Data  []json.RawMessage `json:"data"`
+		Usage *struct {
+			InputTokens  int64 `json:"input_tokens"`
+			OutputTokens int64 `json:"output_tokens"`
+			TotalTokens  int64 `json:"total_tokens"`
+		} `json:"usage"`
+	}
+	if err = json.Unmarshal(c.RawResponse, &respData); err != nil {
+		return
+	}
+	c.Units = int64(len(respData.Data))
+	if usage := respData.Usage; usage != nil && usage.TotalTokens > 0 {
+		c.Usage = &core.Usage{
+			PromptTokens:     usage.InputTokens,
+			CompletionTokens: usage.OutputTokens,
+			TotalTokens:      usage.TotalTokens,
+		}
+	}
+	return
+}
+
+// afterAudio 按实际音频时长计费，text、srt、vtt 格式的响应不含时长，保留按文件估算的值
+func (h *Handler) afterAudio(c *core.Context) (err error) {
+	var respData struct {
+		Duration float64 `json:"duration"`
+		Usage    *struct {
+			Type         string  `json:"type"`
+			Seconds      float64 `json:"seconds"`
+			InputTokens  int64   `json:"input_tokens"`
+			OutputTokens int64   `json:"output_tokens"`
+			TotalTokens  int64   `json:"total_tokens"`
+		} `json:"usage"`
+	}
+	if json.Unmarshal(c.RawResponse, &respData) != nil {
+		return
+	}
+	seconds := respData.Duration
+	if usage := respData.Usage; usage != nil {
+		if usage.Type == "duration" {
+			seconds = usage.Seconds
+		} else if usage.TotalTokens > 0 {
+			c.Usage = &core.Usage{
+				PromptTokens:     usage.InputTokens,
+				CompletionTokens: usage.OutputTokens,
+				TotalTokens:      usage.TotalTokens,
+			}
+		}
+	}
+	if seconds > 0 {
+		c.Units = int64(math.Ceil(seconds))
+	}
+	return
+}
+
 // DoStream 发送流式请求
 func (h *Handler) DoStream(ctx context.Context, c *core.Context) (stream core.Stream, err error) {
-	resp, err := core.HttpClient.Do(c.HTTPRequest)
+	resp, err := core.ProviderClient(c.CurrentModel).Do(c.HTTPRequest)
 	if err != nil {
 		return
 	}
 
+	c.HTTPResponse = resp
 	if resp.StatusCode != http.StatusOK {
 		defer resp.Body.Close()
 		b, _ := io.ReadAll(resp.Body)
+		// 保存错误响应，用于判断是否重试
+		c.RawResponse = b
 		return nil, fmt.Errorf("openai stream error: %s", b)
 	}
 
-	c.HTTPResponse = resp
 	return NewStreamReceiver(resp.Body), nil
 }
@@ -0,0 +1,188 @@
+package openai
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+const (
+	ObjectChatCompletion      = "chat.completion"
+	ObjectChatCompletionChunk = "chat.completion.chunk"
+)
+
+const (
+	FinishReasonStop          = "stop"
+	FinishReasonLength        = "length"
+	FinishReasonToolCalls     = "tool_calls"
+	FinishReasonContentFilter = "content_filter"
+)
+
+// ChatCompletionRequest Chat Completions 请求
+type ChatCompletionRequest struct {
+	Model               string          `json:"model"`
+	Messages            []Message       `json:"messages"`
+	Stream              bool            `json:"stream,omitempty"`
+	StreamOptions       *StreamOptions  `json:"stream_options,omitempty"`
+	MaxTokens           *int64          `json:"max_tokens,omitempty"`
+	MaxCompletionTokens *int64          `json:"max_completion_tokens,omitempty"`
+	Temperature         *float64        `json:"temperature,omitempty"`
+	TopP                *float64        `json:"top_p,omitempty"`
+	N                   *int64          `json:"n,omitempty"`
+	Stop                json.RawMessage `json:"stop,omitempty"`
+	Tools               []Tool          `json:"tools,omitempty"`
+	ToolChoice          json.RawMessage `json:"tool_choice,omitempty"`
+	ResponseFormat      *ResponseFormat `json:"response_format,omitempty"`
+	User                string          `json:"user,omitempty"`
+}
+
+// GetMaxTokens 最大输出 token 数，优先使用 max_completion_tokens
+func (r *ChatCompletionRequest) GetMaxTokens() *int64 {
+	if r.MaxCompletionTokens != nil {
+		return r.MaxCompletionTokens
+	}
+	return r.MaxTokens
+}
+
+// GetStop stop 参数既可以是字符串也可以是字符串数组
+func (r *ChatCompletionRequest) GetStop() []string {
+	if len(r.Stop) == 0 {
+		return nil
+	}
+	var stop string
+	if err := json.Unmarshal(r.Stop, &stop); err == nil {
+		return []string{stop}
+	}
+	var stops []string
+	_ = json.Unmarshal(r.Stop, &stops)
+	return stops
+}
+
+type StreamOptions struct {
+	IncludeUsage bool `json:"include_usage,omitempty"`
+}
+
+type ResponseFormat struct {
+	Type       string          `json:"type"`
+	JsonSchema json.RawMessage `json:"json_schema,omitempty"`
+}
+
+// Message 对话消息
+type Message struct {
+	Role       string         `json:"role"`
+	Content    MessageContent `json:"content"`
+	Name       string         `json:"name,omitempty"`
+	ToolCalls  []ToolCall     `json:"tool_calls,omitempty"`
+	ToolCallId string         `json:"tool_call_id,omitempty"`
+}
+
+// MessageContent 消息内容，既可以是字符串也可以是内容块数组
+type MessageContent struct {
+	Text  string
+	Parts []ContentPart
+}
+
+// String 返回所有文本内容
+func (m MessageContent) String() string {
+	if m.Parts == nil {
+		return m.Text
+	}
+	var text strings.Builder
+	for _, part := range m.Parts {
+		if part.Type == ContentPartTypeText {
+			text.WriteString(part.Text)
+		}
+	}
+	return text.String()
+}
+
+func (m MessageContent) MarshalJSON() ([]byte, error) {
+	if m.Parts != nil {
+		return json.Marshal(m.Parts)
+	}
+	return json.Marshal(m.Text)
+}
+
+func (m *MessageContent) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	if len(data) > 0 && data[0] == '[' {
+		return json.Unmarshal(data, &m.Parts)
+	}
+	return json.Unmarshal(data, &m.Text)
+}
+
+const (
+	ContentPartTypeText     = "text"
+	ContentPartTypeImageUrl = "image_url"
+)
+
+// ContentPart 内容块
+type ContentPart struct {
+	Type     string    `json:"type"`
+	Text     string    `json:"text,omitempty"`
+	ImageUrl *ImageUrl `json:"image_url,omitempty"`
+}
+
+type ImageUrl struct {
+	Url    string `json:"url"`
+	Detail string `json:"detail,omitempty"`
+}
+
+type Tool struct {
+	Type     string       `json:"type"`
+	Function ToolFunction `json:"function"`
+}
+
+type ToolFunction struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Parameters  json.RawMessage `json:"parameters,omitempty"`
+}
+
+type ToolCall struct {
+	Index    *int64           `json:"index,omitempty"`
+	Id       string           `json:"id,omitempty"`
+	Type     string           `json:"type,omitempty"`
+	Function ToolCallFunction `json:"function"`
+}
+
+type ToolCallFunction struct {
+	Name      string `json:"name,omitempty"`
+	Arguments string `json:"arguments"`
+}
+
+// ChatCompletionResponse Chat Completions 响应
+type ChatCompletionResponse struct {
+	Id      string   `json:"id"`
+	Object  string   `json:"object"`
+	Created int64    `json:"created"`
+	Model   string   `json:"model"`
+	Choices []Choice `json:"choices"`
+	Usage   *Usage   `json:"usage,omitempty"`
+}
+
+type Choice struct {
+	Index        int64          `json:"index"`
+	Message      *ChoiceMessage `json:"message,omitempty"`
+	Delta        *ChoiceMessage `json:"delta,omitempty"`
+	FinishReason *string        `json:"finish_reason"`
+}
+
+// ChoiceMessage 响应消息，content 为 null 时不输出
+type ChoiceMessage struct {
+	Role      string     `json:"role,omitempty"`
+	Content   *string    `json:"content,omitempty"`
+	ToolCalls []ToolCall `json:"tool_calls,omitempty"`
+}
+
+type Usage struct {
+	PromptTokens        int64                `json:"prompt_tokens"`
+	CompletionTokens    int64                `json:"completion_tokens"`
+	TotalTokens         int64                `json:"total_tokens"`
+	PromptTokensDetails *PromptTokensDetails `json:"prompt_tokens_details,omitempty"`
+}
+
+type PromptTokensDetails struct {
+	CachedTokens int64 `json:"cached_tokens"`
+}
@@ -4,37 +4,49 @@ import (
 	"github.com/samber/do/v2"
 
 	"github.com/modelgate/modelgate/internal/runtime/core"
-	"github.com/modelgate/modelgate/internal/runtime/hooks"
 )
 
 func Init(i do.Injector) {
-	reqHook := do.MustInvoke[*hooks.RequestHook](i)
-	tokenHook := do.MustInvoke[*hooks.OpenAITokenHook](i)
-	billingHook := do.MustInvoke[*hooks.BillingHook](i)
-	streamWriteHook := do.MustInvoke[*hooks.StreamWriteHook](i)
-
 	{
 		handler := NewHandler(core.ProviderCodeOpenAI)
+		realtimeHandler := NewRealtimeHandler(core.ProviderCodeOpenAI)
 
 		core.ExecutorRegistry.Register(core.ProviderCodeOpenAI, func(opts core.Options) (core.Executor, error) {
-			if opts.IsStream {
-				return core.NewStreamExecutor(handler, reqHook, streamWriteHook, tokenHook, billingHook), nil
-			} else {
-				base := core.NewExecutor(handler, reqHook, tokenHook, billingHook)
-				return core.NewRetryExecutor(base, opts.Retry), nil
+			switch opts.Endpoint {
+			case core.EndpointEmbeddings, core.EndpointImages, core.EndpointAudio, core.EndpointSpeech:
+				return core.ExecutorRegistry.NewExecutor(handler, opts)
+			case core.EndpointRerank:
+				// 兼容 Jina 格式的自部署重排序服务，如 vLLM、TEI
+				return core.ExecutorRegistry.NewExecutor(handler, opts)
+			case core.EndpointRealtime:
+				return core.ExecutorRegistry.NewExecutor(realtimeHandler, opts)
+			}
+			if !opts.Endpoint.IsChat() {
+				return nil, core.ErrEndpointNotSupported(core.ProviderCodeOpenAI, opts.Endpoint)
 			}
+			// OpenAI 原生支持 Responses API，直接透传
+			var h core.Handler = handler
+			if opts.Protocol != core.ProtocolOpenAIResponses {
+				var err error
+				if h, err = core.Translate(handler, core.ProtocolOpenAI, opts.Protocol); err != nil {
+					return nil, err
+				}
+			}
+			return core.ExecutorRegistry.NewExecutor(h, opts)
 		})
 	}
 	{
 		handler := NewHandler(core.ProviderCodeDeepSeek)
 
 		core.ExecutorRegistry.Register(core.ProviderCodeDeepSeek, func(opts core.Options) (core.Executor, error) {
-			if opts.IsStream {
-				return core.NewStreamExecutor(handler, reqHook, streamWriteHook, tokenHook, billingHook), nil
-			} else {
-				base := core.NewExecutor(handler, reqHook, tokenHook, billingHook)
-				return core.NewRetryExecutor(base, opts.Retry), nil
+			if !opts.Endpoint.IsChat() {
+				return nil, core.ErrEndpointNotSupported(core.ProviderCodeDeepSeek, opts.Endpoint)
+			}
+			h, err := core.Translate(handler, core.ProtocolOpenAI, opts.Protocol)
+			if err != nil {
+				return nil, err
 			}
+			return core.ExecutorRegistry.NewExecutor(h, opts)
 		})
 	}
 }
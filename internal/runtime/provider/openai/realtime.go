@@ -0,0 +1,216 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/gorilla/websocket"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/modelgate/modelgate/internal/config"
+	"github.com/modelgate/modelgate/internal/runtime/core"
+	"github.com/modelgate/modelgate/pkg/utils"
+)
+
+// RealtimeHandler Realtime API 处理器，整个 WebSocket 会话作为一次请求
+type RealtimeHandler struct {
+	provider string
+}
+
+func NewRealtimeHandler(provider string) *RealtimeHandler {
+	return &RealtimeHandler{
+		provider: provider,
+	}
+}
+
+func (h *RealtimeHandler) Provider() string {
+	return h.provider
+}
+
+// BeforeRequest 构建上游 WebSocket 地址和握手请求头
+func (h *RealtimeHandler) BeforeRequest(ctx context.Context, c *core.Context) (err error) {
+	u, err := url.Parse(c.CurrentModel.BaseUrl + "/v1/realtime")
+	if err != nil {
+		return
+	}
+	u.Scheme = strings.Replace(u.Scheme, "http", "ws", 1)
+	u.RawQuery = url.Values{"model": {c.CurrentModel.ModelCode}}.Encode()
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
+	if err != nil {
+		return
+	}
+	apiKey, err := utils.DecryptAESGCM(c.CurrentModel.ApiKeyEncrypted, []byte(config.GetConfig().Secret.Key))
+	if err != nil {
+		return
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", apiKey))
+	if beta := c.Header.Get("OpenAI-Beta"); beta != "" {
+		req.Header.Set("OpenAI-Beta", beta)
+	}
+	c.HTTPRequest = req
+	return
+}
+
+// realtimeDialer 使用供应商传输配置中的代理和 TLS
+func realtimeDialer(m *core.Model) *websocket.Dialer {
+	dialer := *websocket.DefaultDialer
+	if t, ok := core.ProviderClient(m).Transport.(*http.Transport); ok {
+		dialer.Proxy = t.Proxy
+		dialer.TLSClientConfig = t.TLSClientConfig
+		dialer.NetDialContext = t.DialContext
+	}
+	return &dialer
+}
+
+// DoRequest 连接上游并双向转发消息，任一方断开时结束会话
+func (h *RealtimeHandler) DoRequest(ctx context.Context, c *core.Context) (err error) {
+	upstream, resp, err := realtimeDialer(c.CurrentModel).DialContext(ctx, c.HTTPRequest.URL.String(), c.HTTPRequest.Header)
+	if resp != nil {
+		c.HTTPResponse = resp
+	}
+	if err != nil {
+		if resp != nil {
+			body, _ := io.ReadAll(resp.Body)
+			err = fmt.Errorf("%s realtime dial error: %s", h.provider, body)
+		}
+		return
+	}
+
+	var wg sync.WaitGroup
+	var upstreamErr, settleErr error
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer c.ClientConn.Close()
+		for {
+			messageType, data, rErr := upstream.ReadMessage()
+			if rErr != nil {
+				if websocket.IsUnexpectedCloseError(rErr, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+					upstreamErr = rErr
+				}
+				return
+			}
+			if messageType == websocket.TextMessage {
+				settleErr = h.onEvent(ctx, c, data)
+			}
+			if wErr := c.ClientConn.WriteMessage(messageType, data); wErr != nil {
+				return
+			}
+			// 余额不足，通知客户端后结束会话
+			if settleErr != nil {
+				writeRealtimeError(c.ClientConn, "insufficient_quota", settleErr)
+				return
+			}
+		}
+	}()
+
+	for {
+		messageType, data, rErr := c.ClientConn.ReadMessage()
+		if rErr != nil {
+			break
+		}
+		if wErr := upstream.WriteMessage(messageType, data); wErr != nil {
+			break
+		}
+	}
+	// 客户端断开后关闭上游，等待转发结束再结算
+	upstream.Close()
+	wg.Wait()
+	if settleErr != nil {
+		return settleErr
+	}
+	if upstreamErr != nil {
+		log.Warnf("%s realtime upstream closed: %v", h.provider, upstreamErr)
+	}
+	return upstreamErr
+}
+
+// writeRealtimeError 以 Realtime error 事件通知客户端
+func writeRealtimeError(conn core.MessageConn, typ string, err error) {
+	data, _ := json.Marshal(map[string]any{
+		"type":  "error",
+		"error": map[string]string{"type": typ, "message": err.Error()},
+	})
+	_ = conn.WriteMessage(websocket.TextMessage, data)
+}
+
+// realtimeEvent Realtime 服务端事件中与用量相关的字段
+type realtimeEvent struct {
+	Type    string `json:"type"`
+	Session *struct {
+		Model string `json:"model"`
+	} `json:"session"`
+	Response *struct {
+		Usage *realtimeUsage `json:"usage"`
+	} `json:"response"`
+}
+
+type realtimeUsage struct {
+	TotalTokens       int64 `json:"total_tokens"`
+	InputTokens       int64 `json:"input_tokens"`
+	OutputTokens      int64 `json:"output_tokens"`
+	InputTokenDetails struct {
+		AudioTokens         int64 `json:"audio_tokens"`
+		CachedTokens        int64 `json:"cached_tokens"`
+		CachedTokensDetails struct {
+			AudioTokens int64 `json:"audio_tokens"`
+		} `json:"cached_tokens_details"`
+	} `json:"input_token_details"`
+	OutputTokenDetails struct {
+		AudioTokens int64 `json:"audio_tokens"`
+	} `json:"output_token_details"`
+}
+
+// onEvent 每个 response.done 携带一次响应的用量，会话内累加并结算，余额不足时返回错误
+func (h *RealtimeHandler) onEvent(ctx context.Context, c *core.Context, data []byte) (err error) {
+	var event realtimeEvent
+	if err = json.Unmarshal(data, &event); err != nil {
+		log.Errorf("json unmarshal realtime event error: %v", err)
+		return nil
+	}
+	switch event.Type {
+	case "session.created":
+		if event.Session != nil {
+			c.ActualModel = event.Session.Model
+		}
+	case "response.done":
+		if event.Response == nil || event.Response.Usage == nil {
+			return
+		}
+		addRealtimeUsage(c, event.Response.Usage)
+		if c.Settle != nil {
+			return c.Settle(ctx, c)
+		}
+	}
+	return
+}
+
+// addRealtimeUsage 缓存命中的部分按缓存价格计费，不再计入 PromptTokens
+func addRealtimeUsage(c *core.Context, usage *realtimeUsage) {
+	if c.Usage == nil {
+		c.Usage = &core.Usage{}
+	}
+	cached := usage.InputTokenDetails.CachedTokens
+	c.Usage.PromptTokens += usage.InputTokens - cached
+	c.Usage.PromptCachedTokens += cached
+	c.Usage.PromptAudioTokens += usage.InputTokenDetails.AudioTokens - usage.InputTokenDetails.CachedTokensDetails.AudioTokens
+	c.Usage.CompletionTokens += usage.OutputTokens
+	c.Usage.CompletionAudioTokens += usage.OutputTokenDetails.AudioTokens
+	c.Usage.TotalTokens += usage.TotalTokens
+}
+
+// AfterResponse 用量已在会话中累加
+func (h *RealtimeHandler) AfterResponse(ctx context.Context, c *core.Context) (err error) {
+	return
+}
+
+// DoStream Realtime 使用 WebSocket，不支持 SSE 流式
+func (h *RealtimeHandler) DoStream(ctx context.Context, c *core.Context) (stream core.Stream, err error) {
+	return nil, fmt.Errorf("%s realtime does not support stream", h.provider)
+}
@@ -0,0 +1,52 @@
+package openai
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/modelgate/modelgate/internal/runtime/core"
+)
+
+func TestRealtimeUsage(t *testing.T) {
+	h := NewRealtimeHandler(core.ProviderCodeOpenAI)
+	ctx := context.Background()
+	c := &core.Context{}
+	done := []byte(`{"type":"response.done","response":{"usage":{"total_tokens":300,"input_tokens":200,"output_tokens":100,
+		"input_token_details":{"text_tokens":50,"audio_tokens":150,"cached_tokens":40,"cached_tokens_details":{"text_tokens":10,"audio_tokens":30}},
+		"output_token_details":{"text_tokens":20,"audio_tokens":80}}}}`)
+	h.onEvent(ctx, c, []byte(`{"type":"session.created","session":{"model":"gpt-realtime"}}`))
+	h.onEvent(ctx, c, done)
+	h.onEvent(ctx, c, []byte(`{"type":"response.audio.delta","delta":"AAAA"}`))
+	h.onEvent(ctx, c, done)
+
+	if c.ActualModel != "gpt-realtime" {
+		t.Fatalf("actual model: %s", c.ActualModel)
+	}
+	want := core.Usage{
+		PromptTokens:          320,
+		PromptCachedTokens:    80,
+		CompletionTokens:      200,
+		TotalTokens:           600,
+		PromptAudioTokens:     240,
+		CompletionAudioTokens: 160,
+	}
+	if *c.Usage != want {
+		t.Fatalf("got %+v, want %+v", *c.Usage, want)
+	}
+
+	// 每次响应结束时结算，余额不足时返回错误
+	settled := 0
+	c.Settle = func(ctx context.Context, c *core.Context) error {
+		if settled++; settled > 1 {
+			return errors.New("insufficient balance")
+		}
+		return nil
+	}
+	if err := h.onEvent(ctx, c, done); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.onEvent(ctx, c, done); err == nil {
+		t.Fatal("expected settle error")
+	}
+}
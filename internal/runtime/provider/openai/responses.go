@@ -0,0 +1,204 @@
+package openai
+
+import (
+	"encoding/json"
+
+	"github.com/modelgate/modelgate/internal/runtime/core"
+)
+
+const ObjectResponse = "response"
+
+// Responses API 流式事件
+const (
+	ResponseCreated                    = "response.created"
+	ResponseInProgress                 = "response.in_progress"
+	ResponseCompleted                  = "response.completed"
+	ResponseIncomplete                 = "response.incomplete"
+	ResponseFailed                     = "response.failed"
+	ResponseOutputItemAdded            = "response.output_item.added"
+	ResponseOutputItemDone             = "response.output_item.done"
+	ResponseContentPartAdded           = "response.content_part.added"
+	ResponseContentPartDone            = "response.content_part.done"
+	ResponseOutputTextDelta            = "response.output_text.delta"
+	ResponseOutputTextDone             = "response.output_text.done"
+	ResponseFunctionCallArgumentsDelta = "response.function_call_arguments.delta"
+	ResponseFunctionCallArgumentsDone  = "response.function_call_arguments.done"
+)
+
+// Responses API 输入输出项类型
+const (
+	ItemTypeMessage            = "message"
+	ItemTypeFunctionCall       = "function_call"
+	ItemTypeFunctionCallOutput = "function_call_output"
+	ItemTypeReasoning          = "reasoning"
+
+	PartTypeInputText  = "input_text"
+	PartTypeInputImage = "input_image"
+	PartTypeOutputText = "output_text"
+)
+
+// ResponsesRequest Responses API 请求
+type ResponsesRequest struct {
+	Model           string          `json:"model"`
+	Instructions    string          `json:"instructions,omitempty"`
+	Input           ResponsesInput  `json:"input"`
+	Stream          bool            `json:"stream,omitempty"`
+	MaxOutputTokens *int64          `json:"max_output_tokens,omitempty"`
+	Temperature     *float64        `json:"temperature,omitempty"`
+	TopP            *float64        `json:"top_p,omitempty"`
+	Tools           []ResponsesTool `json:"tools,omitempty"`
+	ToolChoice      json.RawMessage `json:"tool_choice,omitempty"`
+	Text            *ResponsesText  `json:"text,omitempty"`
+	User            string          `json:"user,omitempty"`
+}
+
+// ResponsesInput 输入，既可以是字符串也可以是输入项数组
+type ResponsesInput struct {
+	Text  string
+	Items []ResponsesItem
+}
+
+func (r ResponsesInput) MarshalJSON() ([]byte, error) {
+	if r.Items != nil {
+		return json.Marshal(r.Items)
+	}
+	return json.Marshal(r.Text)
+}
+
+func (r *ResponsesInput) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '[' {
+		return json.Unmarshal(data, &r.Items)
+	}
+	return json.Unmarshal(data, &r.Text)
+}
+
+// ResponsesItem 输入输出项，message 省略 type 时按 message 处理
+type ResponsesItem struct {
+	Type    string           `json:"type,omitempty"`
+	Id      string           `json:"id,omitempty"`
+	Status  string           `json:"status,omitempty"`
+	Role    string           `json:"role,omitempty"`
+	Content ResponsesContent `json:"content,omitzero"`
+	// function_call / function_call_output
+	CallId    string          `json:"call_id,omitempty"`
+	Name      string          `json:"name,omitempty"`
+	Arguments string          `json:"arguments,omitempty"`
+	Output    json.RawMessage `json:"output,omitempty"`
+}
+
+// ResponsesContent 消息内容，既可以是字符串也可以是内容块数组
+type ResponsesContent struct {
+	Text  string
+	Parts []ResponsesPart
+}
+
+// IsZero 用于 omitzero
+func (r ResponsesContent) IsZero() bool {
+	return r.Parts == nil && r.Text == ""
+}
+
+func (r ResponsesContent) MarshalJSON() ([]byte, error) {
+	if r.Parts != nil {
+		return json.Marshal(r.Parts)
+	}
+	return json.Marshal(r.Text)
+}
+
+func (r *ResponsesContent) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	if len(data) > 0 && data[0] == '[' {
+		return json.Unmarshal(data, &r.Parts)
+	}
+	return json.Unmarshal(data, &r.Text)
+}
+
+type ResponsesPart struct {
+	Type        string `json:"type"`
+	Text        string `json:"text,omitempty"`
+	ImageUrl    string `json:"image_url,omitempty"`
+	Annotations []any  `json:"annotations,omitzero"`
+}
+
+type ResponsesTool struct {
+	Type        string          `json:"type"`
+	Name        string          `json:"name,omitempty"`
+	Description string          `json:"description,omitempty"`
+	Parameters  json.RawMessage `json:"parameters,omitempty"`
+	Strict      *bool           `json:"strict,omitempty"`
+}
+
+type ResponsesText struct {
+	Format *ResponsesTextFormat `json:"format,omitempty"`
+}
+
+type ResponsesTextFormat struct {
+	Type   string          `json:"type"`
+	Name   string          `json:"name,omitempty"`
+	Schema json.RawMessage `json:"schema,omitempty"`
+	Strict *bool           `json:"strict,omitempty"`
+}
+
+// Response Responses API 响应
+type Response struct {
+	Id                string             `json:"id"`
+	Object            string             `json:"object"`
+	CreatedAt         int64              `json:"created_at"`
+	Status            string             `json:"status"`
+	Model             string             `json:"model"`
+	Output            []ResponsesItem    `json:"output"`
+	IncompleteDetails *IncompleteDetails `json:"incomplete_details"`
+	Usage             *ResponsesUsage    `json:"usage"`
+}
+
+type IncompleteDetails struct {
+	Reason string `json:"reason"`
+}
+
+type ResponsesUsage struct {
+	InputTokens         int64                `json:"input_tokens"`
+	InputTokensDetails  *InputTokensDetails  `json:"input_tokens_details,omitempty"`
+	OutputTokens        int64                `json:"output_tokens"`
+	OutputTokensDetails *OutputTokensDetails `json:"output_tokens_details,omitempty"`
+	TotalTokens         int64                `json:"total_tokens"`
+}
+
+type InputTokensDetails struct {
+	CachedTokens int64 `json:"cached_tokens"`
+}
+
+type OutputTokensDetails struct {
+	ReasoningTokens int64 `json:"reasoning_tokens"`
+}
+
+// ResponsesEvent Responses API 流式事件
+type ResponsesEvent struct {
+	Type           string         `json:"type"`
+	SequenceNumber int64          `json:"sequence_number"`
+	Response       *Response      `json:"response,omitempty"`
+	OutputIndex    *int64         `json:"output_index,omitempty"`
+	ContentIndex   *int64         `json:"content_index,omitempty"`
+	ItemId         string         `json:"item_id,omitempty"`
+	Item           *ResponsesItem `json:"item,omitempty"`
+	Part           *ResponsesPart `json:"part,omitempty"`
+	Delta          string         `json:"delta,omitempty"`
+	Text           string         `json:"text,omitempty"`
+	Arguments      string         `json:"arguments,omitempty"`
+}
+
+// ResponsesUsageToCore 转换为 core.Usage
+func ResponsesUsageToCore(usage *ResponsesUsage) *core.Usage {
+	if usage == nil || usage.TotalTokens == 0 {
+		return nil
+	}
+	out := &core.Usage{
+		PromptTokens:     usage.InputTokens,
+		CompletionTokens: usage.OutputTokens,
+		TotalTokens:      usage.TotalTokens,
+	}
+	if usage.InputTokensDetails != nil {
+		out.PromptCachedTokens = usage.InputTokensDetails.CachedTokens
+	}
+	return out
+}
@@ -12,6 +12,7 @@ import (
 type StreamReceiver struct {
 	reader *bufio.Reader
 	body   io.ReadCloser
+	event  string // 上一行 event: 的事件名
 }
 
 // NewStreamReceiver 创建流式接收器
@@ -33,6 +34,10 @@ func (s *StreamReceiver) Recv() (*core.StreamChunk, error) {
 			return nil, err
 		}
 		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "event:") {
+			s.event = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+			continue
+		}
 		if line == "" || !strings.HasPrefix(line, "data: ") {
 			continue
 		}
@@ -43,8 +48,11 @@ func (s *StreamReceiver) Recv() (*core.StreamChunk, error) {
 		if payload == "" {
 			continue
 		}
+		event := s.event
+		s.event = ""
 		return &core.StreamChunk{
-			Data: payload,
+			Event: event,
+			Data:  payload,
 		}, nil
 	}
 }
@@ -36,7 +36,11 @@ func (h *OpenAIHandler) BeforeRequest(ctx context.Context, c *core.Context) (err
 
 	log.Infof("zhipu openai handler, model: %s, endpoint: %s", c.CurrentModel.ModelCode, endpoint)
 
-	req, err := http.NewRequest("POST", endpoint, bytes.NewReader(c.InputBody))
+	body, err := c.RequestBody()
+	if err != nil {
+		return
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(body))
 	if err != nil {
 		return
 	}
@@ -45,7 +49,7 @@ func (h *OpenAIHandler) BeforeRequest(ctx context.Context, c *core.Context) (err
 		return
 	}
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", apiKey))
-	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Content-Type", c.GetContentType())
 	c.HTTPRequest = req
 	return
 }
@@ -69,7 +73,11 @@ func (h *AnthropicHandler) BeforeRequest(ctx context.Context, c *core.Context) (
 
 	log.Infof("zhipu anthropic handler, model: %s, endpoint: %s", c.CurrentModel.ModelCode, endpoint)
 
-	req, err := http.NewRequest("POST", endpoint, bytes.NewReader(c.InputBody))
+	body, err := c.RequestBody()
+	if err != nil {
+		return
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(body))
 	if err != nil {
 		return
 	}
@@ -84,7 +92,7 @@ func (h *AnthropicHandler) BeforeRequest(ctx context.Context, c *core.Context) (
 }
 
 func (h *AnthropicHandler) DoRequest(ctx context.Context, c *core.Context) (err error) {
-	resp, err := core.HttpClient.Do(c.HTTPRequest)
+	resp, err := core.ProviderClient(c.CurrentModel).Do(c.HTTPRequest)
 	if err != nil {
 		return
 	}
@@ -4,31 +4,32 @@ import (
 	"github.com/samber/do/v2"
 
 	"github.com/modelgate/modelgate/internal/runtime/core"
-	"github.com/modelgate/modelgate/internal/runtime/hooks"
 )
 
 func Init(i do.Injector) {
-	reqHook := do.MustInvoke[*hooks.RequestHook](i)
-	tokenHook := do.MustInvoke[*hooks.OpenAITokenHook](i)
-	billingHook := do.MustInvoke[*hooks.BillingHook](i)
-	streamWriteHook := do.MustInvoke[*hooks.StreamWriteHook](i)
-
 	openaiHandler := NewOpenAIHandler()
 	anthropicHandler := NewAnthropicHandler()
 
-	// 智谱同时支持 OpenAI 和 Anthropic 协议，根据 opts.UrlPath 选择对应 handler
+	// 智谱同时支持 OpenAI 和 Anthropic 协议，根据 opts.Protocol 选择对应 handler
 	core.ExecutorRegistry.Register(core.ProviderCodeZhipu, func(opts core.Options) (core.Executor, error) {
+		switch opts.Endpoint {
+		case core.EndpointEmbeddings, core.EndpointImages:
+			return core.ExecutorRegistry.NewExecutor(openaiHandler, opts)
+		}
+		if !opts.Endpoint.IsChat() {
+			return nil, core.ErrEndpointNotSupported(core.ProviderCodeZhipu, opts.Endpoint)
+		}
 		var handler core.Handler
-		if opts.IsAnthropic {
+		if opts.Protocol == core.ProtocolAnthropic {
 			handler = anthropicHandler
 		} else {
-			handler = openaiHandler
+			// 其他入站协议（如 Responses）转换为 OpenAI 协议
+			var err error
+			if handler, err = core.Translate(openaiHandler, core.ProtocolOpenAI, opts.Protocol); err != nil {
+				return nil, err
+			}
 		}
 
-		if opts.IsStream {
-			return core.NewStreamExecutor(handler, reqHook, streamWriteHook, tokenHook, billingHook), nil
-		}
-		base := core.NewExecutor(handler, reqHook, tokenHook, billingHook)
-		return core.NewRetryExecutor(base, opts.Retry), nil
+		return core.ExecutorRegistry.NewExecutor(handler, opts)
 	})
 }
@@ -2,38 +2,86 @@ package runtime
 
 import (
 	"context"
+	"encoding/json"
+	"net/http"
 
 	"github.com/samber/do/v2"
+	log "github.com/sirupsen/logrus"
 
+	"github.com/modelgate/modelgate/internal/relay"
 	"github.com/modelgate/modelgate/internal/runtime/core"
 	"github.com/modelgate/modelgate/internal/runtime/hooks"
 	"github.com/modelgate/modelgate/internal/runtime/provider/anthropic"
+	"github.com/modelgate/modelgate/internal/runtime/provider/azure"
+	"github.com/modelgate/modelgate/internal/runtime/provider/bedrock"
+	"github.com/modelgate/modelgate/internal/runtime/provider/cohere"
+	"github.com/modelgate/modelgate/internal/runtime/provider/gemini"
+	"github.com/modelgate/modelgate/internal/runtime/provider/jina"
 	"github.com/modelgate/modelgate/internal/runtime/provider/minimax"
 	"github.com/modelgate/modelgate/internal/runtime/provider/openai"
 	"github.com/modelgate/modelgate/internal/runtime/provider/zhipu"
+	"github.com/modelgate/modelgate/internal/runtime/translate"
 )
 
+// failover 重试时的故障转移
+var failover core.Failover
+
 // Init 初始化
 func Init(i do.Injector) {
+	failover = newFailover(do.MustInvoke[relay.Service](i))
+
 	// Hooks
 	do.Provide(i, hooks.NewRequestHook)
 	do.Provide(i, hooks.NewStreamHook)
 	do.Provide(i, hooks.NewOpenAITokenHook)
+	do.Provide(i, hooks.NewEmbeddingTokenHook)
+	do.Provide(i, hooks.NewImageHook)
+	do.Provide(i, hooks.NewAudioHook)
+	do.Provide(i, hooks.NewRerankHook)
 	do.Provide(i, hooks.NewBillingHook)
+	registerHook[*hooks.RequestHook](i)
+	registerHook[*hooks.StreamWriteHook](i)
+	registerHook[*hooks.OpenAITokenHook](i)
+	registerHook[*hooks.EmbeddingTokenHook](i)
+	registerHook[*hooks.ImageHook](i)
+	registerHook[*hooks.AudioHook](i)
+	registerHook[*hooks.RerankHook](i)
+	registerHook[*hooks.BillingHook](i)
+
+	// 协议转换
+	translate.Init()
 
 	// Provider
 	anthropic.Init(i)
 	openai.Init(i)
 	minimax.Init(i)
 	zhipu.Init(i)
+	gemini.Init(i)
+	azure.Init(i)
+	bedrock.Init(i)
+	cohere.Init(i)
+	jina.Init(i)
+}
+
+// registerHook 注册内置 hook 供流水线按名称引用，内置 hook 没有配置，使用单例
+func registerHook[T core.Hook](i do.Injector) {
+	h := do.MustInvoke[T](i)
+	core.ExecutorRegistry.RegisterHook(h.Name(), func(json.RawMessage) (core.Hook, error) {
+		return h, nil
+	})
 }
 
 // Run 执行
 func Run(ctx context.Context, c *core.Context) (err error) {
+	if c.Endpoint.IsFree() {
+		return runFree(ctx, c)
+	}
 	exector, err := core.ExecutorRegistry.Get(c.CurrentModel.ProviderCode, core.Options{
-		IsStream:    c.IsStream,
-		IsAnthropic: c.IsAnthropic,
-		Retry:       3,
+		IsStream: c.IsStream,
+		Protocol: c.Protocol,
+		Endpoint: c.Endpoint,
+		Retry:    3,
+		Failover: failover,
 	})
 	if err != nil {
 		return
@@ -42,3 +90,37 @@ func Run(ctx context.Context, c *core.Context) (err error) {
 	err = exector.Execute(ctx, c)
 	return
 }
+
+// runFree 免费接口只透传一次，供应商或上游不支持时本地估算
+func runFree(ctx context.Context, c *core.Context) (err error) {
+	exector, err := core.ExecutorRegistry.Get(c.CurrentModel.ProviderCode, core.Options{
+		Protocol: c.Protocol,
+		Endpoint: c.Endpoint,
+	})
+	if err == nil {
+		err = exector.Execute(ctx, c)
+		if err == nil || !upstreamNotSupported(c.HTTPResponse) {
+			return
+		}
+	}
+	log.Warnf("provider %s count tokens unavailable, estimate locally: %v", c.CurrentModel.ProviderCode, err)
+	num, err := hooks.EstimateInputTokens(c)
+	if err != nil {
+		return
+	}
+	c.HTTPResponse = nil
+	c.RawResponse, err = json.Marshal(map[string]int{"input_tokens": num})
+	return
+}
+
+// upstreamNotSupported 连接失败或上游没有该接口
+func upstreamNotSupported(resp *http.Response) bool {
+	if resp == nil {
+		return true
+	}
+	switch resp.StatusCode {
+	case http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,371 @@
+package translate
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/samber/lo"
+
+	"github.com/modelgate/modelgate/internal/runtime/core"
+	"github.com/modelgate/modelgate/internal/runtime/provider/gemini"
+	"github.com/modelgate/modelgate/internal/runtime/provider/openai"
+)
+
+// GeminiRequest 入站 generateContent 请求，model、stream 来自请求路径，由接入层写入请求体
+type GeminiRequest struct {
+	gemini.Request
+	Model  string `json:"model"`
+	Stream bool   `json:"stream,omitempty"`
+}
+
+// GeminiToChatRequest Gemini generateContent 请求转换为 Chat Completions 请求
+func GeminiToChatRequest(data []byte) ([]byte, error) {
+	var req GeminiRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		return nil, err
+	}
+	out := &openai.ChatCompletionRequest{
+		Model:  req.Model,
+		Stream: req.Stream,
+	}
+	if req.Stream {
+		out.StreamOptions = &openai.StreamOptions{IncludeUsage: true}
+	}
+	if req.SystemInstruction != nil {
+		out.Messages = append(out.Messages, openai.Message{
+			Role:    "system",
+			Content: openai.MessageContent{Text: geminiPartsText(req.SystemInstruction.Parts)},
+		})
+	}
+	// Gemini 的 functionCall 可以没有 id，按函数名依次对应 functionResponse
+	callIds := make(map[string][]string)
+	for _, content := range req.Contents {
+		if content.Role == gemini.RoleModel {
+			message := openai.Message{Role: "assistant", Content: openai.MessageContent{Text: geminiPartsText(content.Parts)}}
+			for i, part := range content.Parts {
+				if part.FunctionCall == nil {
+					continue
+				}
+				id := part.FunctionCall.Id
+				if id == "" {
+					id = fmt.Sprintf("call_%s_%d", part.FunctionCall.Name, i)
+				}
+				callIds[part.FunctionCall.Name] = append(callIds[part.FunctionCall.Name], id)
+				message.ToolCalls = append(message.ToolCalls, openai.ToolCall{
+					Id:       id,
+					Type:     "function",
+					Function: openai.ToolCallFunction{Name: part.FunctionCall.Name, Arguments: string(rawJSONOrEmpty(string(part.FunctionCall.Args)))},
+				})
+			}
+			out.Messages = append(out.Messages, message)
+			continue
+		}
+		var parts []openai.ContentPart
+		for _, part := range content.Parts {
+			switch {
+			case part.FunctionResponse != nil:
+				id := part.FunctionResponse.Id
+				if ids := callIds[part.FunctionResponse.Name]; len(ids) > 0 {
+					id = lo.CoalesceOrEmpty(id, ids[0])
+					callIds[part.FunctionResponse.Name] = ids[1:]
+				}
+				out.Messages = append(out.Messages, openai.Message{
+					Role:       "tool",
+					ToolCallId: id,
+					Content:    openai.MessageContent{Text: string(part.FunctionResponse.Response)},
+				})
+			case part.InlineData != nil:
+				parts = append(parts, openai.ContentPart{Type: openai.ContentPartTypeImageUrl, ImageUrl: &openai.ImageUrl{
+					Url: "data:" + part.InlineData.MimeType + ";base64," + part.InlineData.Data,
+				}})
+			case part.FileData != nil:
+				parts = append(parts, openai.ContentPart{Type: openai.ContentPartTypeImageUrl, ImageUrl: &openai.ImageUrl{Url: part.FileData.FileUri}})
+			case part.Thought:
+				// 思考内容不回传
+			default:
+				parts = append(parts, openai.ContentPart{Type: openai.ContentPartTypeText, Text: part.Text})
+			}
+		}
+		if len(parts) == 0 {
+			continue
+		}
+		message := openai.Message{Role: "user", Content: openai.MessageContent{Parts: parts}}
+		if len(parts) == 1 && parts[0].Type == openai.ContentPartTypeText {
+			message.Content = openai.MessageContent{Text: parts[0].Text}
+		}
+		out.Messages = append(out.Messages, message)
+	}
+
+	if config := req.GenerationConfig; config != nil {
+		out.Temperature = config.Temperature
+		out.TopP = config.TopP
+		out.N = config.CandidateCount
+		out.MaxTokens = config.MaxOutputTokens
+		if len(config.StopSequences) > 0 {
+			out.Stop, _ = json.Marshal(config.StopSequences)
+		}
+		if config.ResponseMimeType == "application/json" {
+			out.ResponseFormat = &openai.ResponseFormat{Type: "json_object"}
+			if len(config.ResponseSchema) > 0 {
+				out.ResponseFormat.Type = "json_schema"
+				out.ResponseFormat.JsonSchema, _ = json.Marshal(map[string]any{
+					"name":   "response",
+					"schema": lowerSchemaTypes(config.ResponseSchema),
+				})
+			}
+		}
+	}
+	for _, tool := range req.Tools {
+		// 仅支持函数声明，googleSearch、codeExecution 等内置工具无法转换
+		for _, fn := range tool.FunctionDeclarations {
+			out.Tools = append(out.Tools, openai.Tool{
+				Type: "function",
+				Function: openai.ToolFunction{
+					Name:        fn.Name,
+					Description: fn.Description,
+					Parameters:  lowerSchemaTypes(fn.Parameters),
+				},
+			})
+		}
+	}
+	if len(out.Tools) > 0 && req.ToolConfig != nil && req.ToolConfig.FunctionCallingConfig != nil {
+		out.ToolChoice = convertFunctionCallingConfig(req.ToolConfig.FunctionCallingConfig)
+	}
+	return json.Marshal(out)
+}
+
+func geminiPartsText(parts []gemini.Part) string {
+	var text strings.Builder
+	for _, part := range parts {
+		if !part.Thought {
+			text.WriteString(part.Text)
+		}
+	}
+	return text.String()
+}
+
+func convertFunctionCallingConfig(config *gemini.FunctionCallingConfig) json.RawMessage {
+	var choice any
+	switch config.Mode {
+	case "NONE":
+		choice = "none"
+	case "ANY":
+		choice = "required"
+		if len(config.AllowedFunctionNames) == 1 {
+			choice = openai.Tool{Type: "function", Function: openai.ToolFunction{Name: config.AllowedFunctionNames[0]}}
+		}
+	default:
+		choice = "auto"
+	}
+	data, _ := json.Marshal(choice)
+	return data
+}
+
+// lowerSchemaTypes Gemini Schema 的 type 为大写枚举，如 OBJECT，JSON Schema 要求小写
+func lowerSchemaTypes(data json.RawMessage) json.RawMessage {
+	if len(data) == 0 {
+		return data
+	}
+	var schema any
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return data
+	}
+	var walk func(v any)
+	walk = func(v any) {
+		switch v := v.(type) {
+		case map[string]any:
+			for k, item := range v {
+				if s, ok := item.(string); ok && k == "type" {
+					v[k] = strings.ToLower(s)
+					continue
+				}
+				walk(item)
+			}
+		case []any:
+			for _, item := range v {
+				walk(item)
+			}
+		}
+	}
+	walk(schema)
+	out, err := json.Marshal(schema)
+	if err != nil {
+		return data
+	}
+	return out
+}
+
+// ChatToGeminiResponse Chat Completions 响应转换为 Gemini generateContent 响应
+func ChatToGeminiResponse(data []byte) ([]byte, error) {
+	var resp openai.ChatCompletionResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, err
+	}
+	out := &gemini.Response{
+		Candidates:    []gemini.Candidate{},
+		UsageMetadata: convertChatUsageMetadata(resp.Usage),
+		ModelVersion:  resp.Model,
+		ResponseId:    resp.Id,
+	}
+	for _, choice := range resp.Choices {
+		if choice.Message == nil {
+			continue
+		}
+		out.Candidates = append(out.Candidates, gemini.Candidate{
+			Index:        choice.Index,
+			Content:      gemini.Content{Role: gemini.RoleModel, Parts: geminiParts(choice.Message.Content, choice.Message.ToolCalls)},
+			FinishReason: convertGeminiFinishReason(choice.FinishReason),
+		})
+	}
+	return json.Marshal(out)
+}
+
+func geminiParts(text *string, toolCalls []openai.ToolCall) []gemini.Part {
+	parts := []gemini.Part{}
+	if text != nil && *text != "" {
+		parts = append(parts, gemini.Part{Text: *text})
+	}
+	for _, toolCall := range toolCalls {
+		parts = append(parts, gemini.Part{FunctionCall: &gemini.FunctionCall{
+			Id:   toolCall.Id,
+			Name: toolCall.Function.Name,
+			Args: rawJSONOrEmpty(toolCall.Function.Arguments),
+		}})
+	}
+	return parts
+}
+
+// convertGeminiFinishReason OpenAI finish_reason 转换为 Gemini finishReason，工具调用也是 STOP
+func convertGeminiFinishReason(reason *string) string {
+	if reason == nil {
+		return ""
+	}
+	switch *reason {
+	case openai.FinishReasonLength:
+		return gemini.FinishReasonMaxTokens
+	case openai.FinishReasonContentFilter:
+		return gemini.FinishReasonSafety
+	default:
+		return gemini.FinishReasonStop
+	}
+}
+
+// convertChatUsageMetadata Gemini promptTokenCount 包含缓存命中的 token，与 OpenAI 一致
+func convertChatUsageMetadata(usage *openai.Usage) *gemini.UsageMetadata {
+	if usage == nil {
+		return nil
+	}
+	out := &gemini.UsageMetadata{
+		PromptTokenCount:     usage.PromptTokens,
+		CandidatesTokenCount: usage.CompletionTokens,
+		TotalTokenCount:      usage.TotalTokens,
+	}
+	if usage.PromptTokensDetails != nil {
+		out.CachedContentTokenCount = usage.PromptTokensDetails.CachedTokens
+	}
+	return out
+}
+
+// chatToGemini OpenAI chat.completion.chunk 转换为 Gemini 流式事件
+// 工具调用参数在 OpenAI 中分段输出，Gemini 要求完整的 functionCall，结束时一次输出
+type chatToGemini struct {
+	id        string
+	model     string
+	toolCalls map[int64]map[int64]*openai.ToolCall // choice index -> tool_calls index -> 工具调用
+	finished  []gemini.Candidate                   // 已结束但还没有输出的候选，等待 usage
+	usage     *openai.Usage
+}
+
+func newChatToGemini() *chatToGemini {
+	return &chatToGemini{toolCalls: make(map[int64]map[int64]*openai.ToolCall)}
+}
+
+func (s *chatToGemini) Convert(chunk *core.StreamChunk) (chunks []*core.StreamChunk, err error) {
+	var resp openai.ChatCompletionResponse
+	if err = json.Unmarshal([]byte(chunk.Data), &resp); err != nil {
+		return nil, fmt.Errorf("unmarshal openai stream chunk error: %v", err)
+	}
+	s.id, s.model = resp.Id, resp.Model
+	if resp.Usage != nil {
+		s.usage = resp.Usage
+	}
+	var candidates []gemini.Candidate
+	for _, choice := range resp.Choices {
+		if choice.Delta == nil {
+			continue
+		}
+		calls := s.toolCalls[choice.Index]
+		for _, toolCall := range choice.Delta.ToolCalls {
+			if calls == nil {
+				calls = make(map[int64]*openai.ToolCall)
+				s.toolCalls[choice.Index] = calls
+			}
+			var index int64
+			if toolCall.Index != nil {
+				index = *toolCall.Index
+			}
+			if prev, ok := calls[index]; ok {
+				prev.Function.Arguments += toolCall.Function.Arguments
+				continue
+			}
+			calls[index] = &toolCall
+		}
+		if choice.FinishReason == nil {
+			if text := choice.Delta.Content; text != nil && *text != "" {
+				candidates = append(candidates, gemini.Candidate{
+					Index:   choice.Index,
+					Content: gemini.Content{Role: gemini.RoleModel, Parts: geminiParts(text, nil)},
+				})
+			}
+			continue
+		}
+		s.finished = append(s.finished, gemini.Candidate{
+			Index:        choice.Index,
+			Content:      gemini.Content{Role: gemini.RoleModel, Parts: geminiParts(choice.Delta.Content, sortedToolCalls(calls))},
+			FinishReason: convertGeminiFinishReason(choice.FinishReason),
+		})
+	}
+	if len(candidates) > 0 {
+		chunks = append(chunks, s.event(candidates, nil))
+	}
+	// usage 在结束 chunk 之后单独输出，与结束候选合并为最后一个事件
+	if s.usage != nil && len(s.finished) > 0 {
+		chunks = append(chunks, s.Finish()...)
+	}
+	return
+}
+
+func sortedToolCalls(calls map[int64]*openai.ToolCall) []openai.ToolCall {
+	indexes := make([]int64, 0, len(calls))
+	for index := range calls {
+		indexes = append(indexes, index)
+	}
+	sort.Slice(indexes, func(i, j int) bool { return indexes[i] < indexes[j] })
+	out := make([]openai.ToolCall, 0, len(indexes))
+	for _, index := range indexes {
+		out = append(out, *calls[index])
+	}
+	return out
+}
+
+// Finish 输出等待中的结束候选和 usage
+func (s *chatToGemini) Finish() []*core.StreamChunk {
+	if len(s.finished) == 0 && s.usage == nil {
+		return nil
+	}
+	chunk := s.event(s.finished, convertChatUsageMetadata(s.usage))
+	s.finished, s.usage = nil, nil
+	return []*core.StreamChunk{chunk}
+}
+
+func (s *chatToGemini) event(candidates []gemini.Candidate, usage *gemini.UsageMetadata) *core.StreamChunk {
+	data, _ := json.Marshal(&gemini.Response{
+		Candidates:    lo.Ternary(candidates != nil, candidates, []gemini.Candidate{}),
+		UsageMetadata: usage,
+		ModelVersion:  s.model,
+		ResponseId:    s.id,
+	})
+	return &core.StreamChunk{Data: string(data)}
+}
@@ -0,0 +1,325 @@
+package translate
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/modelgate/modelgate/internal/runtime/provider/anthropic"
+	"github.com/modelgate/modelgate/internal/runtime/provider/openai"
+)
+
+// DefaultMaxTokens OpenAI 请求未指定 max_tokens 时使用，Anthropic 要求必填
+const DefaultMaxTokens = 4096
+
+// ChatToMessagesRequest OpenAI Chat Completions 请求转换为 Anthropic Messages 请求
+func ChatToMessagesRequest(data []byte) ([]byte, error) {
+	var req openai.ChatCompletionRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		return nil, err
+	}
+	out := &anthropic.Request{
+		Model:         req.Model,
+		MaxTokens:     DefaultMaxTokens,
+		Temperature:   req.Temperature,
+		TopP:          req.TopP,
+		StopSequences: req.GetStop(),
+		Stream:        req.Stream,
+	}
+	if maxTokens := req.GetMaxTokens(); maxTokens != nil {
+		out.MaxTokens = *maxTokens
+	}
+	if req.User != "" {
+		out.Metadata = &anthropic.Metadata{UserId: req.User}
+	}
+
+	var system []string
+	for _, msg := range req.Messages {
+		switch msg.Role {
+		case "system", "developer":
+			system = append(system, msg.Content.String())
+		case "assistant":
+			var blocks []anthropic.Content
+			if text := msg.Content.String(); text != "" {
+				blocks = append(blocks, anthropic.Content{Type: anthropic.ContentTypeText, Text: text})
+			}
+			for _, toolCall := range msg.ToolCalls {
+				blocks = append(blocks, anthropic.Content{
+					Type:  anthropic.ContentTypeToolUse,
+					Id:    toolCall.Id,
+					Name:  toolCall.Function.Name,
+					Input: rawJSONOrEmpty(toolCall.Function.Arguments),
+				})
+			}
+			appendMessage(out, "assistant", blocks)
+		case "tool":
+			content, _ := json.Marshal(msg.Content.String())
+			appendMessage(out, "user", []anthropic.Content{{
+				Type:      anthropic.ContentTypeToolResult,
+				ToolUseId: msg.ToolCallId,
+				Content:   content,
+			}})
+		default:
+			blocks, err := convertChatUserContent(msg.Content)
+			if err != nil {
+				return nil, err
+			}
+			appendMessage(out, "user", blocks)
+		}
+	}
+	if len(system) > 0 {
+		out.System, _ = json.Marshal(strings.Join(system, "\n"))
+	}
+
+	for _, tool := range req.Tools {
+		schema := tool.Function.Parameters
+		if len(schema) == 0 {
+			schema = json.RawMessage(`{"type":"object","properties":{}}`)
+		}
+		out.Tools = append(out.Tools, anthropic.Tool{
+			Name:        tool.Function.Name,
+			Description: tool.Function.Description,
+			InputSchema: schema,
+		})
+	}
+	out.ToolChoice = convertChatToolChoice(req.ToolChoice)
+	return json.Marshal(out)
+}
+
+// appendMessage Anthropic 要求 user / assistant 交替出现，相同角色的连续消息合并
+func appendMessage(req *anthropic.Request, role string, blocks []anthropic.Content) {
+	if len(blocks) == 0 {
+		return
+	}
+	if n := len(req.Messages); n > 0 && req.Messages[n-1].Role == role {
+		req.Messages[n-1].Content.Blocks = append(req.Messages[n-1].Content.Blocks, blocks...)
+		return
+	}
+	req.Messages = append(req.Messages, anthropic.Message{
+		Role:    role,
+		Content: anthropic.MessageContent{Blocks: blocks},
+	})
+}
+
+func convertChatUserContent(content openai.MessageContent) (blocks []anthropic.Content, err error) {
+	if content.Parts == nil {
+		return []anthropic.Content{{Type: anthropic.ContentTypeText, Text: content.Text}}, nil
+	}
+	for _, part := range content.Parts {
+		switch part.Type {
+		case openai.ContentPartTypeText:
+			blocks = append(blocks, anthropic.Content{Type: anthropic.ContentTypeText, Text: part.Text})
+		case openai.ContentPartTypeImageUrl:
+			if part.ImageUrl == nil {
+				continue
+			}
+			var source *anthropic.ImageSource
+			if source, err = convertImageUrl(part.ImageUrl.Url); err != nil {
+				return
+			}
+			blocks = append(blocks, anthropic.Content{Type: anthropic.ContentTypeImage, Source: source})
+		}
+	}
+	return
+}
+
+// convertImageUrl data URL 转为 base64 图片，其余作为 url 图片
+func convertImageUrl(url string) (*anthropic.ImageSource, error) {
+	if !strings.HasPrefix(url, "data:") {
+		return &anthropic.ImageSource{Type: "url", Url: url}, nil
+	}
+	// data:image/png;base64,xxxx
+	meta, data, ok := strings.Cut(strings.TrimPrefix(url, "data:"), ",")
+	if !ok || !strings.HasSuffix(meta, ";base64") {
+		return nil, fmt.Errorf("invalid image data url")
+	}
+	return &anthropic.ImageSource{
+		Type:      "base64",
+		MediaType: strings.TrimSuffix(meta, ";base64"),
+		Data:      data,
+	}, nil
+}
+
+func convertChatToolChoice(data json.RawMessage) *anthropic.ToolChoice {
+	if len(data) == 0 {
+		return nil
+	}
+	var choice string
+	if err := json.Unmarshal(data, &choice); err == nil {
+		switch choice {
+		case "none":
+			return &anthropic.ToolChoice{Type: "none"}
+		case "required":
+			return &anthropic.ToolChoice{Type: "any"}
+		default:
+			return &anthropic.ToolChoice{Type: "auto"}
+		}
+	}
+	var named openai.Tool
+	if err := json.Unmarshal(data, &named); err == nil && named.Function.Name != "" {
+		return &anthropic.ToolChoice{Type: "tool", Name: named.Function.Name}
+	}
+	return nil
+}
+
+// MessagesToChatRequest Anthropic Messages 请求转换为 OpenAI Chat Completions 请求
+func MessagesToChatRequest(data []byte) ([]byte, error) {
+	var req anthropic.Request
+	if err := json.Unmarshal(data, &req); err != nil {
+		return nil, err
+	}
+	out := &openai.ChatCompletionRequest{
+		Model:       req.Model,
+		Stream:      req.Stream,
+		Temperature: req.Temperature,
+		TopP:        req.TopP,
+	}
+	if req.MaxTokens > 0 {
+		out.MaxTokens = &req.MaxTokens
+	}
+	if req.Stream {
+		// 流式响应需要 usage 用于计费和 message_delta
+		out.StreamOptions = &openai.StreamOptions{IncludeUsage: true}
+	}
+	if len(req.StopSequences) > 0 {
+		out.Stop, _ = json.Marshal(req.StopSequences)
+	}
+	if req.Metadata != nil {
+		out.User = req.Metadata.UserId
+	}
+
+	if system := systemText(req.System); system != "" {
+		out.Messages = append(out.Messages, openai.Message{
+			Role:    "system",
+			Content: openai.MessageContent{Text: system},
+		})
+	}
+	for _, msg := range req.Messages {
+		messages, err := convertAnthropicMessage(msg)
+		if err != nil {
+			return nil, err
+		}
+		out.Messages = append(out.Messages, messages...)
+	}
+
+	for _, tool := range req.Tools {
+		out.Tools = append(out.Tools, openai.Tool{
+			Type: "function",
+			Function: openai.ToolFunction{
+				Name:        tool.Name,
+				Description: tool.Description,
+				Parameters:  tool.InputSchema,
+			},
+		})
+	}
+	if choice := req.ToolChoice; choice != nil {
+		switch choice.Type {
+		case "any":
+			out.ToolChoice = json.RawMessage(`"required"`)
+		case "none":
+			out.ToolChoice = json.RawMessage(`"none"`)
+		case "tool":
+			out.ToolChoice, _ = json.Marshal(openai.Tool{Type: "function", Function: openai.ToolFunction{Name: choice.Name}})
+		default:
+			out.ToolChoice = json.RawMessage(`"auto"`)
+		}
+	}
+	return json.Marshal(out)
+}
+
+// systemText system 既可以是字符串也可以是文本块数组
+func systemText(data json.RawMessage) string {
+	if len(data) == 0 {
+		return ""
+	}
+	var content anthropic.MessageContent
+	if err := json.Unmarshal(data, &content); err != nil {
+		return ""
+	}
+	return blocksText(content.GetBlocks())
+}
+
+func blocksText(blocks []anthropic.Content) string {
+	var text []string
+	for _, block := range blocks {
+		if block.Type == anthropic.ContentTypeText {
+			text = append(text, block.Text)
+		}
+	}
+	return strings.Join(text, "\n")
+}
+
+// convertAnthropicMessage tool_result 拆分为 tool 消息，tool_use 转为 tool_calls
+func convertAnthropicMessage(msg anthropic.Message) (messages []openai.Message, err error) {
+	blocks := msg.Content.GetBlocks()
+	if msg.Role == "assistant" {
+		out := openai.Message{Role: "assistant"}
+		out.Content.Text = blocksText(blocks)
+		for _, block := range blocks {
+			if block.Type != anthropic.ContentTypeToolUse {
+				continue
+			}
+			arguments, _ := json.Marshal(block.Input)
+			if block.Input == nil {
+				arguments = []byte("{}")
+			}
+			out.ToolCalls = append(out.ToolCalls, openai.ToolCall{
+				Id:       block.Id,
+				Type:     "function",
+				Function: openai.ToolCallFunction{Name: block.Name, Arguments: string(arguments)},
+			})
+		}
+		return []openai.Message{out}, nil
+	}
+
+	var parts []openai.ContentPart
+	for _, block := range blocks {
+		switch block.Type {
+		case anthropic.ContentTypeToolResult:
+			messages = append(messages, openai.Message{
+				Role:       "tool",
+				ToolCallId: block.ToolUseId,
+				Content:    openai.MessageContent{Text: toolResultText(block.Content)},
+			})
+		case anthropic.ContentTypeText:
+			parts = append(parts, openai.ContentPart{Type: openai.ContentPartTypeText, Text: block.Text})
+		case anthropic.ContentTypeImage:
+			if block.Source == nil {
+				continue
+			}
+			url := block.Source.Url
+			if block.Source.Type == "base64" {
+				url = "data:" + block.Source.MediaType + ";base64," + block.Source.Data
+			}
+			parts = append(parts, openai.ContentPart{Type: openai.ContentPartTypeImageUrl, ImageUrl: &openai.ImageUrl{Url: url}})
+		}
+	}
+	if len(parts) > 0 {
+		content := openai.MessageContent{Parts: parts}
+		// 纯文本使用字符串，兼容不支持内容块数组的上游
+		if len(parts) == 1 && parts[0].Type == openai.ContentPartTypeText {
+			content = openai.MessageContent{Text: parts[0].Text}
+		}
+		messages = append(messages, openai.Message{Role: "user", Content: content})
+	}
+	return
+}
+
+// toolResultText tool_result 内容既可以是字符串也可以是内容块数组
+func toolResultText(data json.RawMessage) string {
+	if len(data) == 0 {
+		return ""
+	}
+	var content anthropic.MessageContent
+	if err := json.Unmarshal(data, &content); err != nil {
+		return string(data)
+	}
+	return blocksText(content.GetBlocks())
+}
+
+func rawJSONOrEmpty(s string) json.RawMessage {
+	if s == "" || !json.Valid([]byte(s)) {
+		return json.RawMessage("{}")
+	}
+	return json.RawMessage(s)
+}
@@ -0,0 +1,148 @@
+package translate
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/modelgate/modelgate/internal/runtime/provider/anthropic"
+	"github.com/modelgate/modelgate/internal/runtime/provider/openai"
+)
+
+// MessagesToChatResponse Anthropic Messages 响应转换为 OpenAI Chat Completions 响应
+func MessagesToChatResponse(data []byte) ([]byte, error) {
+	var resp anthropic.Response
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, err
+	}
+	message := &openai.ChoiceMessage{Role: "assistant"}
+	var text strings.Builder
+	for _, block := range resp.Content {
+		switch block.Type {
+		case anthropic.ContentTypeText:
+			text.WriteString(block.Text)
+		case anthropic.ContentTypeToolUse:
+			arguments, _ := json.Marshal(block.Input)
+			message.ToolCalls = append(message.ToolCalls, openai.ToolCall{
+				Id:       block.Id,
+				Type:     "function",
+				Function: openai.ToolCallFunction{Name: block.Name, Arguments: string(arguments)},
+			})
+		}
+	}
+	content := text.String()
+	message.Content = &content
+
+	var finishReason *string
+	if resp.StopReason != nil {
+		reason := convertStopReason(*resp.StopReason)
+		finishReason = &reason
+	}
+	return json.Marshal(&openai.ChatCompletionResponse{
+		Id:      resp.Id,
+		Object:  openai.ObjectChatCompletion,
+		Created: time.Now().Unix(),
+		Model:   resp.Model,
+		Choices: []openai.Choice{{
+			Message:      message,
+			FinishReason: finishReason,
+		}},
+		Usage: convertAnthropicUsage(&resp.Usage),
+	})
+}
+
+// convertStopReason Anthropic stop_reason 转换为 OpenAI finish_reason
+func convertStopReason(reason string) string {
+	switch reason {
+	case anthropic.StopReasonMaxTokens:
+		return openai.FinishReasonLength
+	case anthropic.StopReasonToolUse:
+		return openai.FinishReasonToolCalls
+	case anthropic.StopReasonRefusal:
+		return openai.FinishReasonContentFilter
+	default:
+		return openai.FinishReasonStop
+	}
+}
+
+// convertAnthropicUsage OpenAI prompt_tokens 包含缓存命中的 token
+func convertAnthropicUsage(usage *anthropic.Usage) *openai.Usage {
+	promptTokens := usage.InputTokens + usage.CacheReadInputTokens + usage.CacheCreationInputTokens
+	out := &openai.Usage{
+		PromptTokens:     promptTokens,
+		CompletionTokens: usage.OutputTokens,
+		TotalTokens:      promptTokens + usage.OutputTokens,
+	}
+	if usage.CacheReadInputTokens > 0 {
+		out.PromptTokensDetails = &openai.PromptTokensDetails{CachedTokens: usage.CacheReadInputTokens}
+	}
+	return out
+}
+
+// ChatToMessagesResponse OpenAI Chat Completions 响应转换为 Anthropic Messages 响应
+func ChatToMessagesResponse(data []byte) ([]byte, error) {
+	var resp openai.ChatCompletionResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Choices) == 0 || resp.Choices[0].Message == nil {
+		return nil, fmt.Errorf("response has no choices")
+	}
+	choice := resp.Choices[0]
+	content := []anthropic.Content{}
+	if text := choice.Message.Content; text != nil && *text != "" {
+		content = append(content, anthropic.Content{Type: anthropic.ContentTypeText, Text: *text})
+	}
+	for _, toolCall := range choice.Message.ToolCalls {
+		content = append(content, anthropic.Content{
+			Type:  anthropic.ContentTypeToolUse,
+			Id:    toolCall.Id,
+			Name:  toolCall.Function.Name,
+			Input: rawJSONOrEmpty(toolCall.Function.Arguments),
+		})
+	}
+	stopReason := convertFinishReason(choice.FinishReason)
+	out := &anthropic.Response{
+		Id:         resp.Id,
+		Type:       "message",
+		Role:       "assistant",
+		Content:    content,
+		Model:      resp.Model,
+		StopReason: &stopReason,
+	}
+	if resp.Usage != nil {
+		out.Usage = convertOpenAIUsage(resp.Usage)
+	}
+	return json.Marshal(out)
+}
+
+// convertFinishReason OpenAI finish_reason 转换为 Anthropic stop_reason
+func convertFinishReason(reason *string) string {
+	if reason == nil {
+		return anthropic.StopReasonEndTurn
+	}
+	switch *reason {
+	case openai.FinishReasonLength:
+		return anthropic.StopReasonMaxTokens
+	case openai.FinishReasonToolCalls, "function_call":
+		return anthropic.StopReasonToolUse
+	case openai.FinishReasonContentFilter:
+		return anthropic.StopReasonRefusal
+	default:
+		return anthropic.StopReasonEndTurn
+	}
+}
+
+// convertOpenAIUsage Anthropic input_tokens 不包含缓存命中的 token
+func convertOpenAIUsage(usage *openai.Usage) anthropic.Usage {
+	var cached int64
+	if usage.PromptTokensDetails != nil {
+		cached = usage.PromptTokensDetails.CachedTokens
+	}
+	return anthropic.Usage{
+		InputTokens:          usage.PromptTokens - cached,
+		CacheReadInputTokens: cached,
+		OutputTokens:         usage.CompletionTokens,
+	}
+}
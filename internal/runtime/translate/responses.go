@@ -0,0 +1,395 @@
+package translate
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/samber/lo"
+
+	"github.com/modelgate/modelgate/internal/runtime/core"
+	"github.com/modelgate/modelgate/internal/runtime/provider/openai"
+)
+
+// ResponsesToChatRequest OpenAI Responses 请求转换为 Chat Completions 请求
+func ResponsesToChatRequest(data []byte) ([]byte, error) {
+	var req openai.ResponsesRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		return nil, err
+	}
+	out := &openai.ChatCompletionRequest{
+		Model:       req.Model,
+		Stream:      req.Stream,
+		MaxTokens:   req.MaxOutputTokens,
+		Temperature: req.Temperature,
+		TopP:        req.TopP,
+		User:        req.User,
+	}
+	if req.Stream {
+		out.StreamOptions = &openai.StreamOptions{IncludeUsage: true}
+	}
+	if req.Instructions != "" {
+		out.Messages = append(out.Messages, openai.Message{
+			Role:    "system",
+			Content: openai.MessageContent{Text: req.Instructions},
+		})
+	}
+	if req.Input.Items == nil {
+		out.Messages = append(out.Messages, openai.Message{
+			Role:    "user",
+			Content: openai.MessageContent{Text: req.Input.Text},
+		})
+	}
+	for _, item := range req.Input.Items {
+		switch item.Type {
+		case openai.ItemTypeFunctionCall:
+			toolCall := openai.ToolCall{
+				Id:       item.CallId,
+				Type:     "function",
+				Function: openai.ToolCallFunction{Name: item.Name, Arguments: item.Arguments},
+			}
+			// 连续的 function_call 合并到同一条 assistant 消息
+			if n := len(out.Messages); n > 0 && out.Messages[n-1].Role == "assistant" {
+				out.Messages[n-1].ToolCalls = append(out.Messages[n-1].ToolCalls, toolCall)
+				continue
+			}
+			out.Messages = append(out.Messages, openai.Message{Role: "assistant", ToolCalls: []openai.ToolCall{toolCall}})
+		case openai.ItemTypeFunctionCallOutput:
+			out.Messages = append(out.Messages, openai.Message{
+				Role:       "tool",
+				ToolCallId: item.CallId,
+				Content:    openai.MessageContent{Text: functionCallOutputText(item.Output)},
+			})
+		case openai.ItemTypeMessage, "":
+			out.Messages = append(out.Messages, openai.Message{
+				Role:    lo.Ternary(item.Role == "developer", "system", item.Role),
+				Content: convertResponsesContent(item.Content),
+			})
+		}
+	}
+
+	for _, tool := range req.Tools {
+		// 仅支持函数工具，内置工具（web_search 等）无法转换
+		if tool.Type != "function" {
+			continue
+		}
+		out.Tools = append(out.Tools, openai.Tool{
+			Type: "function",
+			Function: openai.ToolFunction{
+				Name:        tool.Name,
+				Description: tool.Description,
+				Parameters:  tool.Parameters,
+			},
+		})
+	}
+	if len(req.ToolChoice) > 0 && len(out.Tools) > 0 {
+		var named openai.ResponsesTool
+		if err := json.Unmarshal(req.ToolChoice, &named); err == nil && named.Name != "" {
+			out.ToolChoice, _ = json.Marshal(openai.Tool{Type: "function", Function: openai.ToolFunction{Name: named.Name}})
+		} else {
+			out.ToolChoice = req.ToolChoice
+		}
+	}
+	if req.Text != nil && req.Text.Format != nil && req.Text.Format.Type != "text" {
+		format := req.Text.Format
+		out.ResponseFormat = &openai.ResponseFormat{Type: format.Type}
+		if format.Type == "json_schema" {
+			out.ResponseFormat.JsonSchema, _ = json.Marshal(map[string]any{
+				"name":   format.Name,
+				"schema": format.Schema,
+				"strict": format.Strict,
+			})
+		}
+	}
+	return json.Marshal(out)
+}
+
+func convertResponsesContent(content openai.ResponsesContent) openai.MessageContent {
+	if content.Parts == nil {
+		return openai.MessageContent{Text: content.Text}
+	}
+	var parts []openai.ContentPart
+	for _, part := range content.Parts {
+		switch part.Type {
+		case openai.PartTypeInputText, openai.PartTypeOutputText:
+			parts = append(parts, openai.ContentPart{Type: openai.ContentPartTypeText, Text: part.Text})
+		case openai.PartTypeInputImage:
+			parts = append(parts, openai.ContentPart{Type: openai.ContentPartTypeImageUrl, ImageUrl: &openai.ImageUrl{Url: part.ImageUrl}})
+		}
+	}
+	// 纯文本使用字符串，兼容不支持内容块数组的上游
+	if len(parts) == 1 && parts[0].Type == openai.ContentPartTypeText {
+		return openai.MessageContent{Text: parts[0].Text}
+	}
+	return openai.MessageContent{Parts: parts}
+}
+
+// functionCallOutputText output 既可以是字符串也可以是内容块数组
+func functionCallOutputText(data json.RawMessage) string {
+	var content openai.ResponsesContent
+	if err := json.Unmarshal(data, &content); err != nil {
+		return string(data)
+	}
+	if content.Parts == nil {
+		return content.Text
+	}
+	var text strings.Builder
+	for _, part := range content.Parts {
+		text.WriteString(part.Text)
+	}
+	return text.String()
+}
+
+// ChatToResponsesResponse Chat Completions 响应转换为 Responses 响应
+func ChatToResponsesResponse(data []byte) ([]byte, error) {
+	var resp openai.ChatCompletionResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Choices) == 0 || resp.Choices[0].Message == nil {
+		return nil, fmt.Errorf("response has no choices")
+	}
+	choice := resp.Choices[0]
+	out := &openai.Response{
+		Id:        "resp_" + resp.Id,
+		Object:    openai.ObjectResponse,
+		CreatedAt: resp.Created,
+		Status:    "completed",
+		Model:     resp.Model,
+		Output:    []openai.ResponsesItem{},
+		Usage:     convertChatUsage(resp.Usage),
+	}
+	if text := choice.Message.Content; text != nil && *text != "" {
+		out.Output = append(out.Output, outputMessage("msg_"+resp.Id, *text, "completed"))
+	}
+	for _, toolCall := range choice.Message.ToolCalls {
+		out.Output = append(out.Output, openai.ResponsesItem{
+			Type:      openai.ItemTypeFunctionCall,
+			Id:        "fc_" + toolCall.Id,
+			Status:    "completed",
+			CallId:    toolCall.Id,
+			Name:      toolCall.Function.Name,
+			Arguments: toolCall.Function.Arguments,
+		})
+	}
+	if choice.FinishReason != nil && *choice.FinishReason == openai.FinishReasonLength {
+		out.Status = "incomplete"
+		out.IncompleteDetails = &openai.IncompleteDetails{Reason: "max_output_tokens"}
+	}
+	return json.Marshal(out)
+}
+
+func outputMessage(id, text, status string) openai.ResponsesItem {
+	return openai.ResponsesItem{
+		Type:   openai.ItemTypeMessage,
+		Id:     id,
+		Status: status,
+		Role:   "assistant",
+		Content: openai.ResponsesContent{Parts: []openai.ResponsesPart{{
+			Type:        openai.PartTypeOutputText,
+			Text:        text,
+			Annotations: []any{},
+		}}},
+	}
+}
+
+func convertChatUsage(usage *openai.Usage) *openai.ResponsesUsage {
+	if usage == nil {
+		return nil
+	}
+	out := &openai.ResponsesUsage{
+		InputTokens:         usage.PromptTokens,
+		InputTokensDetails:  &openai.InputTokensDetails{},
+		OutputTokens:        usage.CompletionTokens,
+		OutputTokensDetails: &openai.OutputTokensDetails{},
+		TotalTokens:         usage.TotalTokens,
+	}
+	if usage.PromptTokensDetails != nil {
+		out.InputTokensDetails.CachedTokens = usage.PromptTokensDetails.CachedTokens
+	}
+	return out
+}
+
+// chatToResponses Chat Completions 流式 chunk 转换为 Responses 流式事件
+type chatToResponses struct {
+	seq      int64
+	started  bool
+	response *openai.Response
+	// 当前打开的输出项
+	item     *openai.ResponsesItem
+	text     strings.Builder
+	toolCall int64 // 当前工具调用在 tool_calls 中的序号
+	finish   *string
+}
+
+func newChatToResponses() *chatToResponses {
+	return &chatToResponses{toolCall: -1}
+}
+
+func (s *chatToResponses) Convert(chunk *core.StreamChunk) (chunks []*core.StreamChunk, err error) {
+	var resp openai.ChatCompletionResponse
+	if err = json.Unmarshal([]byte(chunk.Data), &resp); err != nil {
+		return nil, fmt.Errorf("unmarshal openai stream chunk error: %v", err)
+	}
+	if !s.started {
+		s.started = true
+		s.response = &openai.Response{
+			Id:        "resp_" + resp.Id,
+			Object:    openai.ObjectResponse,
+			CreatedAt: lo.Ternary(resp.Created != 0, resp.Created, time.Now().Unix()),
+			Status:    "in_progress",
+			Model:     resp.Model,
+			Output:    []openai.ResponsesItem{},
+		}
+		snapshot := *s.response
+		chunks = append(chunks, s.event(&openai.ResponsesEvent{Type: openai.ResponseCreated, Response: &snapshot}))
+	}
+	if resp.Usage != nil {
+		s.response.Usage = convertChatUsage(resp.Usage)
+	}
+	if len(resp.Choices) == 0 {
+		return
+	}
+	choice := resp.Choices[0]
+	if delta := choice.Delta; delta != nil {
+		if delta.Content != nil && *delta.Content != "" {
+			if s.item == nil || s.item.Type != openai.ItemTypeMessage {
+				chunks = append(chunks, s.closeItem()...)
+				item := outputMessage("msg_"+resp.Id, "", "in_progress")
+				item.Content.Parts = []openai.ResponsesPart{}
+				chunks = append(chunks, s.openItem(&item)...)
+				chunks = append(chunks, s.event(&openai.ResponsesEvent{
+					Type:         openai.ResponseContentPartAdded,
+					ItemId:       item.Id,
+					OutputIndex:  s.outputIndex(),
+					ContentIndex: new(int64),
+					Part:         &openai.ResponsesPart{Type: openai.PartTypeOutputText, Annotations: []any{}},
+				}))
+			}
+			s.text.WriteString(*delta.Content)
+			chunks = append(chunks, s.event(&openai.ResponsesEvent{
+				Type:         openai.ResponseOutputTextDelta,
+				ItemId:       s.item.Id,
+				OutputIndex:  s.outputIndex(),
+				ContentIndex: new(int64),
+				Delta:        *delta.Content,
+			}))
+		}
+		for _, toolCall := range delta.ToolCalls {
+			index := s.toolCall
+			if toolCall.Index != nil {
+				index = *toolCall.Index
+			}
+			if toolCall.Id != "" || index != s.toolCall || s.item == nil || s.item.Type != openai.ItemTypeFunctionCall {
+				s.toolCall = index
+				chunks = append(chunks, s.closeItem()...)
+				chunks = append(chunks, s.openItem(&openai.ResponsesItem{
+					Type:   openai.ItemTypeFunctionCall,
+					Id:     "fc_" + toolCall.Id,
+					Status: "in_progress",
+					CallId: toolCall.Id,
+					Name:   toolCall.Function.Name,
+				})...)
+			}
+			if toolCall.Function.Arguments != "" {
+				s.item.Arguments += toolCall.Function.Arguments
+				chunks = append(chunks, s.event(&openai.ResponsesEvent{
+					Type:        openai.ResponseFunctionCallArgumentsDelta,
+					ItemId:      s.item.Id,
+					OutputIndex: s.outputIndex(),
+					Delta:       toolCall.Function.Arguments,
+				}))
+			}
+		}
+	}
+	if choice.FinishReason != nil {
+		s.finish = choice.FinishReason
+	}
+	return
+}
+
+func (s *chatToResponses) outputIndex() *int64 {
+	index := int64(len(s.response.Output))
+	return &index
+}
+
+func (s *chatToResponses) openItem(item *openai.ResponsesItem) []*core.StreamChunk {
+	s.item = item
+	s.text.Reset()
+	added := *item
+	return []*core.StreamChunk{s.event(&openai.ResponsesEvent{
+		Type:        openai.ResponseOutputItemAdded,
+		OutputIndex: s.outputIndex(),
+		Item:        &added,
+	})}
+}
+
+// closeItem 结束当前输出项，并加入最终响应的 output
+func (s *chatToResponses) closeItem() (chunks []*core.StreamChunk) {
+	item := s.item
+	if item == nil {
+		return
+	}
+	s.item = nil
+	item.Status = "completed"
+	switch item.Type {
+	case openai.ItemTypeMessage:
+		text := s.text.String()
+		part := openai.ResponsesPart{Type: openai.PartTypeOutputText, Text: text, Annotations: []any{}}
+		item.Content.Parts = []openai.ResponsesPart{part}
+		chunks = append(chunks,
+			s.event(&openai.ResponsesEvent{
+				Type:         openai.ResponseOutputTextDone,
+				ItemId:       item.Id,
+				OutputIndex:  s.outputIndex(),
+				ContentIndex: new(int64),
+				Text:         text,
+			}),
+			s.event(&openai.ResponsesEvent{
+				Type:         openai.ResponseContentPartDone,
+				ItemId:       item.Id,
+				OutputIndex:  s.outputIndex(),
+				ContentIndex: new(int64),
+				Part:         &part,
+			}))
+	case openai.ItemTypeFunctionCall:
+		chunks = append(chunks, s.event(&openai.ResponsesEvent{
+			Type:        openai.ResponseFunctionCallArgumentsDone,
+			ItemId:      item.Id,
+			OutputIndex: s.outputIndex(),
+			Arguments:   item.Arguments,
+		}))
+	}
+	chunks = append(chunks, s.event(&openai.ResponsesEvent{
+		Type:        openai.ResponseOutputItemDone,
+		OutputIndex: s.outputIndex(),
+		Item:        item,
+	}))
+	s.response.Output = append(s.response.Output, *item)
+	return
+}
+
+// Finish 结束所有输出项，输出 response.completed，其中包含 usage
+func (s *chatToResponses) Finish() (chunks []*core.StreamChunk) {
+	if !s.started {
+		return
+	}
+	chunks = append(chunks, s.closeItem()...)
+	eventType := openai.ResponseCompleted
+	s.response.Status = "completed"
+	if s.finish != nil && *s.finish == openai.FinishReasonLength {
+		eventType = openai.ResponseIncomplete
+		s.response.Status = "incomplete"
+		s.response.IncompleteDetails = &openai.IncompleteDetails{Reason: "max_output_tokens"}
+	}
+	chunks = append(chunks, s.event(&openai.ResponsesEvent{Type: eventType, Response: s.response}))
+	return
+}
+
+func (s *chatToResponses) event(event *openai.ResponsesEvent) *core.StreamChunk {
+	event.SequenceNumber = s.seq
+	s.seq++
+	data, _ := json.Marshal(event)
+	return &core.StreamChunk{Event: event.Type, Data: string(data)}
+}
@@ -0,0 +1,303 @@
+package translate
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/modelgate/modelgate/internal/runtime/core"
+	"github.com/modelgate/modelgate/internal/runtime/provider/anthropic"
+	"github.com/modelgate/modelgate/internal/runtime/provider/openai"
+)
+
+// StreamConverter 流式事件转换器，一个上游 chunk 可能对应零个或多个下游 chunk
+type StreamConverter interface {
+	Convert(chunk *core.StreamChunk) ([]*core.StreamChunk, error)
+	// Finish 上游结束时补齐收尾事件
+	Finish() []*core.StreamChunk
+}
+
+// stream 包装上游流，输出转换后的 chunk
+type stream struct {
+	core.Stream
+	converter StreamConverter
+	pending   []*core.StreamChunk
+	finished  bool
+}
+
+func newStream(s core.Stream, converter StreamConverter) core.Stream {
+	return &stream{Stream: s, converter: converter}
+}
+
+// Recv 接收
+func (s *stream) Recv() (*core.StreamChunk, error) {
+	for {
+		if len(s.pending) > 0 {
+			chunk := s.pending[0]
+			s.pending = s.pending[1:]
+			return chunk, nil
+		}
+		if s.finished {
+			return &core.StreamChunk{Finish: true}, io.EOF
+		}
+		chunk, err := s.Stream.Recv()
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		if chunk != nil && !chunk.Finish && chunk.Data != "" {
+			var chunks []*core.StreamChunk
+			if chunks, err = s.converter.Convert(chunk); err != nil {
+				return nil, err
+			}
+			s.pending = append(s.pending, chunks...)
+		}
+		if err == io.EOF || (chunk != nil && chunk.Finish) {
+			s.finished = true
+			s.pending = append(s.pending, s.converter.Finish()...)
+		}
+	}
+}
+
+// anthropicToOpenAI Anthropic 流式事件转换为 OpenAI chat.completion.chunk
+type anthropicToOpenAI struct {
+	id           string
+	model        string
+	created      int64
+	usage        anthropic.Usage
+	toolIndex    map[int]int64 // content block index -> tool_calls index
+	finishReason string
+}
+
+func newAnthropicToOpenAI() *anthropicToOpenAI {
+	return &anthropicToOpenAI{
+		created:   time.Now().Unix(),
+		toolIndex: make(map[int]int64),
+	}
+}
+
+func (s *anthropicToOpenAI) Convert(chunk *core.StreamChunk) (chunks []*core.StreamChunk, err error) {
+	var event anthropic.StreamResponse
+	if err = json.Unmarshal([]byte(chunk.Data), &event); err != nil {
+		return nil, fmt.Errorf("unmarshal anthropic stream event error: %v", err)
+	}
+	switch event.Type {
+	case anthropic.MessageStart:
+		if event.Message != nil {
+			s.id = event.Message.Id
+			s.model = event.Message.Model
+			s.usage = event.Message.Usage
+		}
+		content := ""
+		return s.chunk(&openai.ChoiceMessage{Role: "assistant", Content: &content}, nil)
+	case anthropic.ContentBlockStart:
+		if event.ContentBlock == nil || event.ContentBlock.Type != anthropic.ContentTypeToolUse {
+			return
+		}
+		index := int64(len(s.toolIndex))
+		s.toolIndex[event.Index] = index
+		return s.chunk(&openai.ChoiceMessage{ToolCalls: []openai.ToolCall{{
+			Index:    &index,
+			Id:       event.ContentBlock.Id,
+			Type:     "function",
+			Function: openai.ToolCallFunction{Name: event.ContentBlock.Name},
+		}}}, nil)
+	case anthropic.ContentBlockDelta:
+		if event.Delta == nil {
+			return
+		}
+		switch event.Delta.Type {
+		case anthropic.DeltaTypeText:
+			text := event.Delta.Text
+			return s.chunk(&openai.ChoiceMessage{Content: &text}, nil)
+		case anthropic.DeltaTypeInputJson:
+			index := s.toolIndex[event.Index]
+			return s.chunk(&openai.ChoiceMessage{ToolCalls: []openai.ToolCall{{
+				Index:    &index,
+				Function: openai.ToolCallFunction{Arguments: event.Delta.PartialJson},
+			}}}, nil)
+		}
+	case anthropic.MessageDelta:
+		if event.Usage != nil {
+			s.usage.OutputTokens = event.Usage.OutputTokens
+			if event.Usage.InputTokens > 0 {
+				s.usage.InputTokens = event.Usage.InputTokens
+			}
+		}
+		if event.Delta != nil && event.Delta.StopReason != nil {
+			s.finishReason = convertStopReason(*event.Delta.StopReason)
+			return s.chunk(&openai.ChoiceMessage{}, &s.finishReason)
+		}
+	case anthropic.ErrorEvent:
+		if event.Error != nil {
+			return nil, fmt.Errorf("anthropic stream error: %s", event.Error.Message)
+		}
+	}
+	return
+}
+
+func (s *anthropicToOpenAI) chunk(delta *openai.ChoiceMessage, finishReason *string) ([]*core.StreamChunk, error) {
+	data, err := json.Marshal(&openai.ChatCompletionResponse{
+		Id:      s.id,
+		Object:  openai.ObjectChatCompletionChunk,
+		Created: s.created,
+		Model:   s.model,
+		Choices: []openai.Choice{{Delta: delta, FinishReason: finishReason}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return []*core.StreamChunk{{Data: string(data)}}, nil
+}
+
+// Finish 最后输出 usage chunk，与 OpenAI stream_options.include_usage 一致
+func (s *anthropicToOpenAI) Finish() []*core.StreamChunk {
+	data, err := json.Marshal(&openai.ChatCompletionResponse{
+		Id:      s.id,
+		Object:  openai.ObjectChatCompletionChunk,
+		Created: s.created,
+		Model:   s.model,
+		Choices: []openai.Choice{},
+		Usage:   convertAnthropicUsage(&s.usage),
+	})
+	if err != nil {
+		log.Errorf("marshal usage chunk error: %v", err)
+		return nil
+	}
+	return []*core.StreamChunk{{Data: string(data)}}
+}
+
+// openAIToAnthropic OpenAI chat.completion.chunk 转换为 Anthropic 流式事件
+type openAIToAnthropic struct {
+	started      bool
+	blockIndex   int    // 当前内容块序号
+	blockType    string // 当前打开的内容块类型，为空表示没有打开的内容块
+	toolIndex    int64  // 当前工具调用在 tool_calls 中的序号
+	finishReason *string
+	usage        *openai.Usage
+}
+
+func newOpenAIToAnthropic() *openAIToAnthropic {
+	return &openAIToAnthropic{blockIndex: -1, toolIndex: -1}
+}
+
+func (s *openAIToAnthropic) Convert(chunk *core.StreamChunk) (chunks []*core.StreamChunk, err error) {
+	var resp openai.ChatCompletionResponse
+	if err = json.Unmarshal([]byte(chunk.Data), &resp); err != nil {
+		return nil, fmt.Errorf("unmarshal openai stream chunk error: %v", err)
+	}
+	if resp.Usage != nil {
+		s.usage = resp.Usage
+	}
+	if !s.started {
+		s.started = true
+		chunks = append(chunks, event(anthropic.MessageStart, map[string]any{
+			"message": map[string]any{
+				"id":            resp.Id,
+				"type":          "message",
+				"role":          "assistant",
+				"model":         resp.Model,
+				"content":       []any{},
+				"stop_reason":   nil,
+				"stop_sequence": nil,
+				"usage":         map[string]any{"input_tokens": 0, "output_tokens": 0},
+			},
+		}))
+	}
+	if len(resp.Choices) == 0 {
+		return
+	}
+	choice := resp.Choices[0]
+	if delta := choice.Delta; delta != nil {
+		if delta.Content != nil && *delta.Content != "" {
+			if s.blockType != anthropic.ContentTypeText {
+				chunks = append(chunks, s.startBlock(map[string]any{"type": anthropic.ContentTypeText, "text": ""})...)
+				s.blockType = anthropic.ContentTypeText
+			}
+			chunks = append(chunks, event(anthropic.ContentBlockDelta, map[string]any{
+				"index": s.blockIndex,
+				"delta": map[string]any{"type": anthropic.DeltaTypeText, "text": *delta.Content},
+			}))
+		}
+		for _, toolCall := range delta.ToolCalls {
+			index := s.toolIndex
+			if toolCall.Index != nil {
+				index = *toolCall.Index
+			}
+			// 新的工具调用
+			if toolCall.Id != "" || index != s.toolIndex || s.blockType != anthropic.ContentTypeToolUse {
+				s.toolIndex = index
+				chunks = append(chunks, s.startBlock(map[string]any{
+					"type":  anthropic.ContentTypeToolUse,
+					"id":    toolCall.Id,
+					"name":  toolCall.Function.Name,
+					"input": map[string]any{},
+				})...)
+				s.blockType = anthropic.ContentTypeToolUse
+			}
+			if toolCall.Function.Arguments != "" {
+				chunks = append(chunks, event(anthropic.ContentBlockDelta, map[string]any{
+					"index": s.blockIndex,
+					"delta": map[string]any{"type": anthropic.DeltaTypeInputJson, "partial_json": toolCall.Function.Arguments},
+				}))
+			}
+		}
+	}
+	if choice.FinishReason != nil {
+		s.finishReason = choice.FinishReason
+	}
+	return
+}
+
+// startBlock 关闭当前内容块并开始新的内容块
+func (s *openAIToAnthropic) startBlock(block map[string]any) (chunks []*core.StreamChunk) {
+	chunks = append(chunks, s.stopBlock()...)
+	s.blockIndex++
+	chunks = append(chunks, event(anthropic.ContentBlockStart, map[string]any{
+		"index":         s.blockIndex,
+		"content_block": block,
+	}))
+	return
+}
+
+func (s *openAIToAnthropic) stopBlock() []*core.StreamChunk {
+	if s.blockType == "" {
+		return nil
+	}
+	s.blockType = ""
+	return []*core.StreamChunk{event(anthropic.ContentBlockStop, map[string]any{"index": s.blockIndex})}
+}
+
+// Finish OpenAI 的 usage 在 finish_reason 之后返回，因此在流结束时输出 message_delta
+func (s *openAIToAnthropic) Finish() (chunks []*core.StreamChunk) {
+	if !s.started {
+		return
+	}
+	chunks = append(chunks, s.stopBlock()...)
+	usage := map[string]any{"output_tokens": 0}
+	if s.usage != nil {
+		u := convertOpenAIUsage(s.usage)
+		usage = map[string]any{
+			"input_tokens":            u.InputTokens,
+			"cache_read_input_tokens": u.CacheReadInputTokens,
+			"output_tokens":           u.OutputTokens,
+		}
+	}
+	chunks = append(chunks, event(anthropic.MessageDelta, map[string]any{
+		"delta": map[string]any{"stop_reason": convertFinishReason(s.finishReason), "stop_sequence": nil},
+		"usage": usage,
+	}))
+	chunks = append(chunks, event(anthropic.MessageStop, nil))
+	return
+}
+
+func event(eventType string, fields map[string]any) *core.StreamChunk {
+	data := map[string]any{"type": eventType}
+	for k, v := range fields {
+		data[k] = v
+	}
+	b, _ := json.Marshal(data)
+	return &core.StreamChunk{Event: eventType, Data: string(b)}
+}
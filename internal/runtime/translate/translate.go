@@ -0,0 +1,121 @@
+package translate
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/modelgate/modelgate/internal/runtime/core"
+)
+
+// Init 注册协议转换
+func Init() {
+	core.RegisterTranslator(NewHandler)
+}
+
+// Handler 协议转换处理器，入站请求转换为上游协议，上游响应转换回入站协议
+type Handler struct {
+	core.Handler
+	native  core.Protocol // 上游协议
+	inbound core.Protocol // 入站协议
+}
+
+// NewHandler 创建协议转换处理器，Responses、Gemini 只与 Chat Completions 互转，其他上游协议经 Chat Completions 中转
+func NewHandler(h core.Handler, native, inbound core.Protocol) (core.Handler, error) {
+	if !isSupported(native) || !isSupported(inbound) || native == core.ProtocolOpenAIResponses || native == core.ProtocolGemini {
+		return nil, fmt.Errorf("unsupported protocol translation: %s -> %s", inbound, native)
+	}
+	if (inbound == core.ProtocolOpenAIResponses || inbound == core.ProtocolGemini) && native != core.ProtocolOpenAI {
+		inner, err := NewHandler(h, native, core.ProtocolOpenAI)
+		if err != nil {
+			return nil, err
+		}
+		return NewHandler(inner, core.ProtocolOpenAI, inbound)
+	}
+	return &Handler{
+		Handler: h,
+		native:  native,
+		inbound: inbound,
+	}, nil
+}
+
+func isSupported(protocol core.Protocol) bool {
+	switch protocol {
+	case core.ProtocolOpenAI, core.ProtocolAnthropic, core.ProtocolOpenAIResponses, core.ProtocolGemini:
+		return true
+	}
+	return false
+}
+
+// BeforeRequest 请求体和路径转换为上游协议后交给上游处理器，结束后恢复，重试时可再次转换
+func (h *Handler) BeforeRequest(ctx context.Context, c *core.Context) (err error) {
+	inputBody, urlPath := c.InputBody, c.UrlPath
+	defer func() {
+		c.InputBody, c.UrlPath = inputBody, urlPath
+	}()
+
+	switch {
+	case h.inbound == core.ProtocolOpenAIResponses:
+		c.InputBody, err = ResponsesToChatRequest(inputBody)
+		c.UrlPath = "/v1/chat/completions"
+	case h.inbound == core.ProtocolGemini:
+		c.InputBody, err = GeminiToChatRequest(inputBody)
+		c.UrlPath = "/v1/chat/completions"
+	case h.native == core.ProtocolAnthropic:
+		if !strings.HasSuffix(urlPath, "/chat/completions") {
+			return fmt.Errorf("provider %s does not support %s", h.Provider(), urlPath)
+		}
+		c.InputBody, err = ChatToMessagesRequest(inputBody)
+		c.UrlPath = "/v1/messages"
+	case h.native == core.ProtocolOpenAI:
+		c.InputBody, err = MessagesToChatRequest(inputBody)
+		c.UrlPath = "/v1/chat/completions"
+	}
+	if err != nil {
+		return fmt.Errorf("translate %s request to %s error: %v", h.inbound, h.native, err)
+	}
+	return h.Handler.BeforeRequest(ctx, c)
+}
+
+// AfterResponse 上游处理器解析用量后，响应转换为入站协议
+func (h *Handler) AfterResponse(ctx context.Context, c *core.Context) (err error) {
+	if err = h.Handler.AfterResponse(ctx, c); err != nil {
+		return
+	}
+	switch {
+	case h.inbound == core.ProtocolOpenAIResponses:
+		c.RawResponse, err = ChatToResponsesResponse(c.RawResponse)
+	case h.inbound == core.ProtocolGemini:
+		c.RawResponse, err = ChatToGeminiResponse(c.RawResponse)
+	case h.native == core.ProtocolAnthropic:
+		c.RawResponse, err = MessagesToChatResponse(c.RawResponse)
+	case h.native == core.ProtocolOpenAI:
+		c.RawResponse, err = ChatToMessagesResponse(c.RawResponse)
+	}
+	if err != nil {
+		return fmt.Errorf("translate %s response to %s error: %v", h.native, h.inbound, err)
+	}
+	// 响应体已改写，原始长度不再适用
+	if c.HTTPResponse != nil {
+		c.HTTPResponse.Header.Del("Content-Length")
+	}
+	return
+}
+
+// DoStream 上游流式事件转换为入站协议
+func (h *Handler) DoStream(ctx context.Context, c *core.Context) (s core.Stream, err error) {
+	s, err = h.Handler.DoStream(ctx, c)
+	if err != nil {
+		return
+	}
+	switch {
+	case h.inbound == core.ProtocolOpenAIResponses:
+		return newStream(s, newChatToResponses()), nil
+	case h.inbound == core.ProtocolGemini:
+		return newStream(s, newChatToGemini()), nil
+	case h.native == core.ProtocolAnthropic:
+		return newStream(s, newAnthropicToOpenAI()), nil
+	default:
+		return newStream(s, newOpenAIToAnthropic()), nil
+	}
+}
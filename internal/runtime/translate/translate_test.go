@@ -0,0 +1,307 @@
+package translate
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/modelgate/modelgate/internal/runtime/core"
+	"github.com/modelgate/modelgate/internal/runtime/provider/anthropic"
+	"github.com/modelgate/modelgate/internal/runtime/provider/gemini"
+	"github.com/modelgate/modelgate/internal/runtime/provider/openai"
+)
+
+func TestMessagesToChatRequest(t *testing.T) {
+	body := `{
+		"model": "gpt-4o",
+		"system": [{"type": "text", "text": "be brief"}],
+		"max_tokens": 256,
+		"stream": true,
+		"messages": [
+			{"role": "user", "content": [
+				{"type": "text", "text": "weather?"},
+				{"type": "image", "source": {"type": "base64", "media_type": "image/png", "data": "aGVsbG8="}}
+			]},
+			{"role": "assistant", "content": [{"type": "tool_use", "id": "toolu_1", "name": "get_weather", "input": {"city": "beijing"}}]},
+			{"role": "user", "content": [{"type": "tool_result", "tool_use_id": "toolu_1", "content": "sunny"}]}
+		],
+		"tools": [{"name": "get_weather", "input_schema": {"type": "object"}}],
+		"tool_choice": {"type": "any"}
+	}`
+	data, err := MessagesToChatRequest([]byte(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var req openai.ChatCompletionRequest
+	if err = json.Unmarshal(data, &req); err != nil {
+		t.Fatal(err)
+	}
+	if len(req.Messages) != 4 || req.Messages[0].Role != "system" || req.Messages[0].Content.String() != "be brief" {
+		t.Fatalf("unexpected messages: %s", data)
+	}
+	if url := req.Messages[1].Content.Parts[1].ImageUrl.Url; url != "data:image/png;base64,aGVsbG8=" {
+		t.Fatalf("unexpected image url: %s", url)
+	}
+	if call := req.Messages[2].ToolCalls[0]; call.Id != "toolu_1" || call.Function.Arguments != `{"city":"beijing"}` {
+		t.Fatalf("unexpected tool call: %+v", call)
+	}
+	if msg := req.Messages[3]; msg.Role != "tool" || msg.ToolCallId != "toolu_1" || msg.Content.String() != "sunny" {
+		t.Fatalf("unexpected tool message: %+v", msg)
+	}
+	if string(req.ToolChoice) != `"required"` || req.StreamOptions == nil || !req.StreamOptions.IncludeUsage {
+		t.Fatalf("unexpected request: %s", data)
+	}
+}
+
+func TestChatToMessagesRequest(t *testing.T) {
+	body := `{
+		"model": "claude-sonnet-4",
+		"messages": [
+			{"role": "system", "content": "be brief"},
+			{"role": "user", "content": "weather?"},
+			{"role": "assistant", "content": null, "tool_calls": [{"id": "call_1", "type": "function", "function": {"name": "get_weather", "arguments": "{\"city\":\"beijing\"}"}}]},
+			{"role": "tool", "tool_call_id": "call_1", "content": "sunny"},
+			{"role": "user", "content": "thanks"}
+		],
+		"stop": ["END"]
+	}`
+	data, err := ChatToMessagesRequest([]byte(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var req anthropic.Request
+	if err = json.Unmarshal(data, &req); err != nil {
+		t.Fatal(err)
+	}
+	if string(req.System) != `"be brief"` || req.MaxTokens != DefaultMaxTokens || req.StopSequences[0] != "END" {
+		t.Fatalf("unexpected request: %s", data)
+	}
+	// tool 消息与后续 user 消息合并
+	if len(req.Messages) != 3 || len(req.Messages[2].Content.Blocks) != 2 {
+		t.Fatalf("unexpected messages: %s", data)
+	}
+	if block := req.Messages[2].Content.Blocks[0]; block.Type != anthropic.ContentTypeToolResult || block.ToolUseId != "call_1" {
+		t.Fatalf("unexpected tool result: %+v", block)
+	}
+}
+
+func TestAnthropicToOpenAIStream(t *testing.T) {
+	events := []string{
+		`{"type":"message_start","message":{"id":"msg_1","model":"claude","usage":{"input_tokens":10,"output_tokens":1}}}`,
+		`{"type":"content_block_start","index":0,"content_block":{"type":"text","text":""}}`,
+		`{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"hi"}}`,
+		`{"type":"content_block_stop","index":0}`,
+		`{"type":"message_delta","delta":{"stop_reason":"end_turn"},"usage":{"output_tokens":5}}`,
+		`{"type":"message_stop"}`,
+	}
+	chunks := collect(t, newStream(newSliceStream(events), newAnthropicToOpenAI()))
+	var text string
+	var finishReason string
+	var usage *openai.Usage
+	for _, chunk := range chunks {
+		var resp openai.ChatCompletionResponse
+		if err := json.Unmarshal([]byte(chunk.Data), &resp); err != nil {
+			t.Fatal(err)
+		}
+		if len(resp.Choices) > 0 {
+			if content := resp.Choices[0].Delta.Content; content != nil {
+				text += *content
+			}
+			if reason := resp.Choices[0].FinishReason; reason != nil {
+				finishReason = *reason
+			}
+		}
+		if resp.Usage != nil {
+			usage = resp.Usage
+		}
+	}
+	if text != "hi" || finishReason != "stop" || usage == nil || usage.PromptTokens != 10 || usage.CompletionTokens != 5 {
+		t.Fatalf("unexpected result: %s, %s, %+v", text, finishReason, usage)
+	}
+}
+
+func TestOpenAIToAnthropicStream(t *testing.T) {
+	events := []string{
+		`{"id":"chatcmpl-1","model":"gpt-4o","choices":[{"index":0,"delta":{"role":"assistant","content":"Let me check"}}]}`,
+		`{"id":"chatcmpl-1","model":"gpt-4o","choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"id":"call_1","type":"function","function":{"name":"get_weather","arguments":""}}]}}]}`,
+		`{"id":"chatcmpl-1","model":"gpt-4o","choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"function":{"arguments":"{\"city\":\"beijing\"}"}}]}}]}`,
+		`{"id":"chatcmpl-1","model":"gpt-4o","choices":[{"index":0,"delta":{},"finish_reason":"tool_calls"}]}`,
+		`{"id":"chatcmpl-1","model":"gpt-4o","choices":[],"usage":{"prompt_tokens":20,"completion_tokens":8,"total_tokens":28}}`,
+	}
+	chunks := collect(t, newStream(newSliceStream(events), newOpenAIToAnthropic()))
+	var types []string
+	for _, chunk := range chunks {
+		types = append(types, chunk.Event)
+	}
+	expected := "message_start,content_block_start,content_block_delta,content_block_stop," +
+		"content_block_start,content_block_delta,content_block_stop,message_delta,message_stop"
+	if strings.Join(types, ",") != expected {
+		t.Fatalf("unexpected events: %v", types)
+	}
+	var delta struct {
+		Delta struct {
+			StopReason string `json:"stop_reason"`
+		} `json:"delta"`
+		Usage anthropic.Usage `json:"usage"`
+	}
+	if err := json.Unmarshal([]byte(chunks[7].Data), &delta); err != nil {
+		t.Fatal(err)
+	}
+	if delta.Delta.StopReason != anthropic.StopReasonToolUse || delta.Usage.InputTokens != 20 || delta.Usage.OutputTokens != 8 {
+		t.Fatalf("unexpected message_delta: %s", chunks[7].Data)
+	}
+}
+
+func TestResponsesToChatRequest(t *testing.T) {
+	body := `{
+		"model": "gpt-4o",
+		"instructions": "be brief",
+		"max_output_tokens": 128,
+		"stream": true,
+		"input": [
+			{"role": "user", "content": [{"type": "input_text", "text": "weather?"}]},
+			{"type": "function_call", "call_id": "call_1", "name": "get_weather", "arguments": "{}"},
+			{"type": "function_call_output", "call_id": "call_1", "output": "sunny"}
+		],
+		"tools": [{"type": "function", "name": "get_weather", "parameters": {"type": "object"}}, {"type": "web_search"}]
+	}`
+	data, err := ResponsesToChatRequest([]byte(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var req openai.ChatCompletionRequest
+	if err = json.Unmarshal(data, &req); err != nil {
+		t.Fatal(err)
+	}
+	if len(req.Messages) != 4 || req.Messages[0].Role != "system" || req.Messages[1].Content.Text != "weather?" {
+		t.Fatalf("unexpected messages: %s", data)
+	}
+	if req.Messages[2].ToolCalls[0].Id != "call_1" || req.Messages[3].Role != "tool" || req.Messages[3].Content.Text != "sunny" {
+		t.Fatalf("unexpected tool messages: %s", data)
+	}
+	if len(req.Tools) != 1 || *req.MaxTokens != 128 || req.StreamOptions == nil {
+		t.Fatalf("unexpected request: %s", data)
+	}
+}
+
+func TestChatToResponsesStream(t *testing.T) {
+	events := []string{
+		`{"id":"chatcmpl-1","model":"gpt-4o","choices":[{"index":0,"delta":{"role":"assistant","content":"Hi"}}]}`,
+		`{"id":"chatcmpl-1","model":"gpt-4o","choices":[{"index":0,"delta":{"content":" there"},"finish_reason":"stop"}]}`,
+		`{"id":"chatcmpl-1","model":"gpt-4o","choices":[],"usage":{"prompt_tokens":5,"completion_tokens":2,"total_tokens":7}}`,
+	}
+	chunks := collect(t, newStream(newSliceStream(events), newChatToResponses()))
+	var types []string
+	for _, chunk := range chunks {
+		types = append(types, chunk.Event)
+	}
+	expected := "response.created,response.output_item.added,response.content_part.added," +
+		"response.output_text.delta,response.output_text.delta,response.output_text.done," +
+		"response.content_part.done,response.output_item.done,response.completed"
+	if strings.Join(types, ",") != expected {
+		t.Fatalf("unexpected events: %v", types)
+	}
+	var completed openai.ResponsesEvent
+	if err := json.Unmarshal([]byte(chunks[8].Data), &completed); err != nil {
+		t.Fatal(err)
+	}
+	output := completed.Response.Output
+	if completed.SequenceNumber != 8 || len(output) != 1 || output[0].Content.Parts[0].Text != "Hi there" {
+		t.Fatalf("unexpected response.completed: %s", chunks[8].Data)
+	}
+	if completed.Response.Usage == nil || completed.Response.Usage.TotalTokens != 7 {
+		t.Fatalf("unexpected usage: %s", chunks[8].Data)
+	}
+}
+
+func TestGeminiToChatRequest(t *testing.T) {
+	body := `{
+		"model": "gemini-2.5-flash",
+		"stream": true,
+		"systemInstruction": {"parts": [{"text": "be brief"}]},
+		"contents": [
+			{"role": "user", "parts": [{"text": "weather?"}]},
+			{"role": "model", "parts": [{"functionCall": {"name": "get_weather", "args": {"city": "paris"}}}]},
+			{"role": "user", "parts": [{"functionResponse": {"name": "get_weather", "response": {"result": "sunny"}}}]}
+		],
+		"generationConfig": {"maxOutputTokens": 128, "stopSequences": ["END"]},
+		"tools": [{"functionDeclarations": [{"name": "get_weather", "parameters": {"type": "OBJECT"}}]}]
+	}`
+	data, err := GeminiToChatRequest([]byte(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var req openai.ChatCompletionRequest
+	if err = json.Unmarshal(data, &req); err != nil {
+		t.Fatal(err)
+	}
+	if len(req.Messages) != 4 || req.Messages[0].Role != "system" || req.Messages[1].Content.Text != "weather?" {
+		t.Fatalf("unexpected messages: %s", data)
+	}
+	toolCall := req.Messages[2].ToolCalls[0]
+	if req.Messages[2].Role != "assistant" || req.Messages[3].Role != "tool" || req.Messages[3].ToolCallId != toolCall.Id {
+		t.Fatalf("unexpected tool messages: %s", data)
+	}
+	if len(req.Tools) != 1 || *req.MaxTokens != 128 || req.StreamOptions == nil || !strings.Contains(string(data), `"type":"object"`) {
+		t.Fatalf("unexpected request: %s", data)
+	}
+}
+
+func TestChatToGeminiStream(t *testing.T) {
+	events := []string{
+		`{"id":"chatcmpl-1","model":"gpt-4o","choices":[{"index":0,"delta":{"role":"assistant","content":"Hi"}}]}`,
+		`{"id":"chatcmpl-1","model":"gpt-4o","choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"id":"call_1","type":"function","function":{"name":"get_weather","arguments":"{\"city\""}}]}}]}`,
+		`{"id":"chatcmpl-1","model":"gpt-4o","choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"function":{"arguments":":\"paris\"}"}}]},"finish_reason":"tool_calls"}]}`,
+		`{"id":"chatcmpl-1","model":"gpt-4o","choices":[],"usage":{"prompt_tokens":5,"completion_tokens":2,"total_tokens":7}}`,
+	}
+	chunks := collect(t, newStream(newSliceStream(events), newChatToGemini()))
+	if len(chunks) != 2 {
+		t.Fatalf("unexpected chunks: %d", len(chunks))
+	}
+	var last gemini.Response
+	if err := json.Unmarshal([]byte(chunks[1].Data), &last); err != nil {
+		t.Fatal(err)
+	}
+	call := last.Candidates[0].Content.Parts[0].FunctionCall
+	if call == nil || call.Name != "get_weather" || string(call.Args) != `{"city":"paris"}` {
+		t.Fatalf("unexpected function call: %s", chunks[1].Data)
+	}
+	if last.Candidates[0].FinishReason != gemini.FinishReasonStop || last.UsageMetadata == nil || last.UsageMetadata.TotalTokenCount != 7 {
+		t.Fatalf("unexpected last event: %s", chunks[1].Data)
+	}
+}
+
+func collect(t *testing.T, s core.Stream) (chunks []*core.StreamChunk) {
+	for {
+		chunk, err := s.Recv()
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		chunks = append(chunks, chunk)
+	}
+}
+
+type sliceStream struct {
+	events []string
+}
+
+func newSliceStream(events []string) core.Stream {
+	return &sliceStream{events: events}
+}
+
+func (s *sliceStream) Recv() (*core.StreamChunk, error) {
+	if len(s.events) == 0 {
+		return &core.StreamChunk{Finish: true}, io.EOF
+	}
+	data := s.events[0]
+	s.events = s.events[1:]
+	return &core.StreamChunk{Data: data}, nil
+}
+
+func (s *sliceStream) Close() error {
+	return nil
+}
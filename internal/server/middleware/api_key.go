@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
 	"github.com/samber/do/v2"
 	log "github.com/sirupsen/logrus"
 
@@ -13,6 +14,12 @@ import (
 	"github.com/modelgate/modelgate/pkg/common"
 )
 
+// realtimeKeyProtocolPrefix Realtime 子协议中的密钥前缀
+const realtimeKeyProtocolPrefix = "openai-insecure-api-key."
+
+// geminiPathPrefix Gemini 原生接口路径前缀
+const geminiPathPrefix = "/v1beta/"
+
 // CheckApiKey  校验apiKey
 func CheckApiKey(i do.Injector) gin.HandlerFunc {
 	relayService := do.MustInvoke[relay.Service](i)
@@ -26,24 +33,50 @@ func CheckApiKey(i do.Injector) gin.HandlerFunc {
 
 		common.SetAccountId(c, accountApiKey.AccountId)
 		common.SetApiKeyId(c, accountApiKey.ID)
+		common.SetApiKeyModels(c, accountApiKey.GetScope().Models)
 	}
 }
 
 func checkApiKey(c *gin.Context, relayService relay.Service) (apiKey *model.AccountApiKey, err error) {
-	auth := c.Request.Header.Get("Authorization")
-	if auth == "" {
-		err = errors.New("Authorization header is required")
-		return
+	// Anthropic SDK 使用 x-api-key 传递密钥
+	key := c.Request.Header.Get("x-api-key")
+	// Google GenAI SDK 使用 x-goog-api-key 或 key 查询参数，查询参数容易出现在访问日志中，只用于 Gemini 原生接口
+	if key == "" {
+		key = c.Request.Header.Get("x-goog-api-key")
 	}
-	auths := strings.SplitN(auth, " ", 2)
-	if len(auths) != 2 {
-		err = errors.New("Authorization header is invalid")
-		return
+	if key == "" && strings.HasPrefix(c.Request.URL.Path, geminiPathPrefix) {
+		key = c.Query("key")
+	}
+	if key == "" {
+		key = subprotocolApiKey(c)
+	}
+	if key == "" {
+		auth := c.Request.Header.Get("Authorization")
+		if auth == "" {
+			err = errors.New("Authorization header is required")
+			return
+		}
+		auths := strings.SplitN(auth, " ", 2)
+		if len(auths) != 2 {
+			err = errors.New("Authorization header is invalid")
+			return
+		}
+		key = auths[1]
 	}
-	apiKey, err = relayService.GetAccountApiKey(c, auths[1])
+	apiKey, err = relayService.GetAccountApiKey(c, key)
 	if err != nil {
 		err = errors.New("failed to get account api key")
 		return
 	}
 	return apiKey, nil
 }
+
+// subprotocolApiKey 浏览器无法设置 WebSocket 请求头，Realtime 通过子协议传递密钥
+func subprotocolApiKey(c *gin.Context) string {
+	for _, protocol := range websocket.Subprotocols(c.Request) {
+		if key, ok := strings.CutPrefix(protocol, realtimeKeyProtocolPrefix); ok {
+			return key
+		}
+	}
+	return ""
+}
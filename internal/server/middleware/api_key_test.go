@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/mock/gomock"
+
+	"github.com/modelgate/modelgate/internal/relay"
+	"github.com/modelgate/modelgate/internal/relay/model"
+)
+
+func TestCheckApiKeyQuery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+	relayService := relay.NewMockService(ctl)
+	relayService.EXPECT().GetAccountApiKey(gomock.Any(), "sk-test").Return(&model.AccountApiKey{}, nil)
+
+	tests := []struct {
+		path    string
+		wantErr bool
+	}{
+		{"/v1beta/models/gemini-2.5-flash:generateContent?key=sk-test", false},
+		{"/v1/chat/completions?key=sk-test", true},
+	}
+	for _, tt := range tests {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest("POST", tt.path, nil)
+		if _, err := checkApiKey(c, relayService); (err != nil) != tt.wantErr {
+			t.Fatalf("%s: err = %v, wantErr %v", tt.path, err, tt.wantErr)
+		}
+	}
+}
@@ -21,8 +21,31 @@ func registerRouters(container do.Injector, engine *gin.Engine) {
 	// v1
 	rgv1 := engine.Group("/v1", middleware.RateLimit(container), middleware.CheckApiKey(container))
 
+	rgv1.GET("/models", relayService.ListModels)
+	rgv1.GET("/models/:model", relayService.GetModel)
 	rgv1.POST("/completions", relayService.Run)
 	rgv1.POST("/chat/completions", relayService.Run)
 	rgv1.POST("/embeddings", relayService.Run)
+	rgv1.POST("/responses", relayService.Run)
+	rgv1.POST("/images/generations", relayService.Run)
+	rgv1.POST("/images/edits", relayService.Run)
+	rgv1.POST("/audio/transcriptions", relayService.Run)
+	rgv1.POST("/audio/translations", relayService.Run)
+	rgv1.POST("/audio/speech", relayService.Run)
+	rgv1.POST("/rerank", relayService.Run)
+	rgv1.GET("/realtime", relayService.Realtime)
+	rgv1.POST("/files", relayService.UploadFile)
+	rgv1.GET("/files", relayService.ListFiles)
+	rgv1.GET("/files/:file_id", relayService.GetFile)
+	rgv1.GET("/files/:file_id/content", relayService.GetFileContent)
+	rgv1.DELETE("/files/:file_id", relayService.DeleteFile)
+	rgv1.POST("/batches", relayService.CreateBatch)
+	rgv1.GET("/batches", relayService.ListBatches)
+	rgv1.GET("/batches/:batch_id", relayService.GetBatch)
+	rgv1.POST("/batches/:batch_id/cancel", relayService.CancelBatch)
 	rgv1.POST("/relay/anthropic/:provider/*path", relayService.RunWithProvider)
+
+	// Gemini 原生接口，路径形如 /v1beta/models/{model}:generateContent
+	rgv1beta := engine.Group("/v1beta", middleware.RateLimit(container), middleware.CheckApiKey(container))
+	rgv1beta.POST("/models/:model_method", relayService.GenerateContent)
 }
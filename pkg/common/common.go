@@ -3,8 +3,9 @@ package common
 import "github.com/gin-gonic/gin"
 
 const (
-	AccountIdKey = "accountId"
-	ApiKeyIdKey  = "apiKeyId"
+	AccountIdKey    = "accountId"
+	ApiKeyIdKey     = "apiKeyId"
+	ApiKeyModelsKey = "apiKeyModels"
 )
 
 func SetAccountId(c *gin.Context, accountId int64) {
@@ -22,3 +23,12 @@ func SetApiKeyId(c *gin.Context, apiKeyId int64) {
 func GetApiKeyId(c *gin.Context) int64 {
 	return c.GetInt64(ApiKeyIdKey)
 }
+
+// SetApiKeyModels API Key 允许使用的模型，为空不限制
+func SetApiKeyModels(c *gin.Context, models []string) {
+	c.Set(ApiKeyModelsKey, models)
+}
+
+func GetApiKeyModels(c *gin.Context) []string {
+	return c.GetStringSlice(ApiKeyModelsKey)
+}
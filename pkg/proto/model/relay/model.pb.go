@@ -75,20 +75,25 @@ func (ModelStatus) EnumDescriptor() ([]byte, []int) {
 }
 
 type Model struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	ProviderId    int64                  `protobuf:"varint,2,opt,name=provider_id,json=providerId,proto3" json:"provider_id,omitempty"`
-	ProviderCode  string                 `protobuf:"bytes,3,opt,name=provider_code,json=providerCode,proto3" json:"provider_code,omitempty"`
-	ActualCode    string                 `protobuf:"bytes,4,opt,name=actual_code,json=actualCode,proto3" json:"actual_code,omitempty"`
-	Code          string                 `protobuf:"bytes,5,opt,name=code,proto3" json:"code,omitempty"`
-	Name          string                 `protobuf:"bytes,6,opt,name=name,proto3" json:"name,omitempty"`
-	Priority      int64                  `protobuf:"varint,7,opt,name=priority,proto3" json:"priority,omitempty"`
-	Weight        int64                  `protobuf:"varint,8,opt,name=weight,proto3" json:"weight,omitempty"`
-	Status        string                 `protobuf:"bytes,9,opt,name=status,proto3" json:"status,omitempty"`
-	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Id              int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	ProviderId      int64                  `protobuf:"varint,2,opt,name=provider_id,json=providerId,proto3" json:"provider_id,omitempty"`
+	ProviderCode    string                 `protobuf:"bytes,3,opt,name=provider_code,json=providerCode,proto3" json:"provider_code,omitempty"`
+	ActualCode      string                 `protobuf:"bytes,4,opt,name=actual_code,json=actualCode,proto3" json:"actual_code,omitempty"`
+	Code            string                 `protobuf:"bytes,5,opt,name=code,proto3" json:"code,omitempty"`
+	Name            string                 `protobuf:"bytes,6,opt,name=name,proto3" json:"name,omitempty"`
+	Priority        int64                  `protobuf:"varint,7,opt,name=priority,proto3" json:"priority,omitempty"`
+	Weight          int64                  `protobuf:"varint,8,opt,name=weight,proto3" json:"weight,omitempty"`
+	Status          string                 `protobuf:"bytes,9,opt,name=status,proto3" json:"status,omitempty"`
+	CreatedAt       *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt       *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	ContextWindow   int64                  `protobuf:"varint,12,opt,name=context_window,json=contextWindow,proto3" json:"context_window,omitempty"`
+	MaxOutputTokens int64                  `protobuf:"varint,13,opt,name=max_output_tokens,json=maxOutputTokens,proto3" json:"max_output_tokens,omitempty"`
+	RetryPolicy     string                 `protobuf:"bytes,14,opt,name=retry_policy,json=retryPolicy,proto3" json:"retry_policy,omitempty"`
+	LbStrategy      string                 `protobuf:"bytes,15,opt,name=lb_strategy,json=lbStrategy,proto3" json:"lb_strategy,omitempty"`
+	Pipeline        string                 `protobuf:"bytes,16,opt,name=pipeline,proto3" json:"pipeline,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *Model) Reset() {
@@ -198,11 +203,46 @@ func (x *Model) GetUpdatedAt() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *Model) GetContextWindow() int64 {
+	if x != nil {
+		return x.ContextWindow
+	}
+	return 0
+}
+
+func (x *Model) GetMaxOutputTokens() int64 {
+	if x != nil {
+		return x.MaxOutputTokens
+	}
+	return 0
+}
+
+func (x *Model) GetRetryPolicy() string {
+	if x != nil {
+		return x.RetryPolicy
+	}
+	return ""
+}
+
+func (x *Model) GetLbStrategy() string {
+	if x != nil {
+		return x.LbStrategy
+	}
+	return ""
+}
+
+func (x *Model) GetPipeline() string {
+	if x != nil {
+		return x.Pipeline
+	}
+	return ""
+}
+
 var File_model_relay_model_proto protoreflect.FileDescriptor
 
 const file_model_relay_model_proto_rawDesc = "" +
 	"\n" +
-	"\x17model/relay/model.proto\x12\x05relay\x1a\x1fgoogle/protobuf/timestamp.proto\"\xe8\x02\n" +
+	"\x17model/relay/model.proto\x12\x05relay\x1a\x1fgoogle/protobuf/timestamp.proto\"\x9b\x04\n" +
 	"\x05Model\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1f\n" +
 	"\vprovider_id\x18\x02 \x01(\x03R\n" +
@@ -219,7 +259,13 @@ const file_model_relay_model_proto_rawDesc = "" +
 	"created_at\x18\n" +
 	" \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
 	"\n" +
-	"updated_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt*}\n" +
+	"updated_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12%\n" +
+	"\x0econtext_window\x18\f \x01(\x03R\rcontextWindow\x12*\n" +
+	"\x11max_output_tokens\x18\r \x01(\x03R\x0fmaxOutputTokens\x12!\n" +
+	"\fretry_policy\x18\x0e \x01(\tR\vretryPolicy\x12\x1f\n" +
+	"\vlb_strategy\x18\x0f \x01(\tR\n" +
+	"lbStrategy\x12\x1a\n" +
+	"\bpipeline\x18\x10 \x01(\tR\bpipeline*}\n" +
 	"\vModelStatus\x12\x1c\n" +
 	"\x18MODEL_STATUS_UNSPECIFIED\x10\x00\x12\x18\n" +
 	"\x14MODEL_STATUS_ENABLED\x10\x01\x12\x19\n" +
@@ -37,6 +37,12 @@ type ModelPricing struct {
 	EffectiveFrom     *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=effective_from,json=effectiveFrom,proto3" json:"effective_from,omitempty"`
 	EffectiveTo       *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=effective_to,json=effectiveTo,proto3" json:"effective_to,omitempty"`
 	CreatedAt         *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	PricingMode       string                 `protobuf:"bytes,14,opt,name=pricing_mode,json=pricingMode,proto3" json:"pricing_mode,omitempty"`
+	UnitPrice         float32                `protobuf:"fixed32,15,opt,name=unit_price,json=unitPrice,proto3" json:"unit_price,omitempty"`
+	UnitPriceRules    string                 `protobuf:"bytes,16,opt,name=unit_price_rules,json=unitPriceRules,proto3" json:"unit_price_rules,omitempty"`
+	BatchDiscount     float32                `protobuf:"fixed32,17,opt,name=batch_discount,json=batchDiscount,proto3" json:"batch_discount,omitempty"`
+	AudioInputPrice   float32                `protobuf:"fixed32,18,opt,name=audio_input_price,json=audioInputPrice,proto3" json:"audio_input_price,omitempty"`
+	AudioOutputPrice  float32                `protobuf:"fixed32,19,opt,name=audio_output_price,json=audioOutputPrice,proto3" json:"audio_output_price,omitempty"`
 	unknownFields     protoimpl.UnknownFields
 	sizeCache         protoimpl.SizeCache
 }
@@ -162,11 +168,53 @@ func (x *ModelPricing) GetCreatedAt() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *ModelPricing) GetPricingMode() string {
+	if x != nil {
+		return x.PricingMode
+	}
+	return ""
+}
+
+func (x *ModelPricing) GetUnitPrice() float32 {
+	if x != nil {
+		return x.UnitPrice
+	}
+	return 0
+}
+
+func (x *ModelPricing) GetUnitPriceRules() string {
+	if x != nil {
+		return x.UnitPriceRules
+	}
+	return ""
+}
+
+func (x *ModelPricing) GetBatchDiscount() float32 {
+	if x != nil {
+		return x.BatchDiscount
+	}
+	return 0
+}
+
+func (x *ModelPricing) GetAudioInputPrice() float32 {
+	if x != nil {
+		return x.AudioInputPrice
+	}
+	return 0
+}
+
+func (x *ModelPricing) GetAudioOutputPrice() float32 {
+	if x != nil {
+		return x.AudioOutputPrice
+	}
+	return 0
+}
+
 var File_model_relay_model_pricing_proto protoreflect.FileDescriptor
 
 const file_model_relay_model_pricing_proto_rawDesc = "" +
 	"\n" +
-	"\x1fmodel/relay/model_pricing.proto\x12\x05relay\x1a\x1fgoogle/protobuf/timestamp.proto\"\x90\x04\n" +
+	"\x1fmodel/relay/model_pricing.proto\x12\x05relay\x1a\x1fgoogle/protobuf/timestamp.proto\"\xfd\x05\n" +
 	"\fModelPricing\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x03R\x02id\x12#\n" +
 	"\rprovider_code\x18\x02 \x01(\tR\fproviderCode\x12\x1d\n" +
@@ -184,7 +232,14 @@ const file_model_relay_model_pricing_proto_rawDesc = "" +
 	"\x0eeffective_from\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\reffectiveFrom\x12=\n" +
 	"\feffective_to\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\veffectiveTo\x129\n" +
 	"\n" +
-	"created_at\x18\r \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAtB6Z4github.com/modelgate/modelgate/pkg/proto/model/relayb\x06proto3"
+	"created_at\x18\r \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12!\n" +
+	"\fpricing_mode\x18\x0e \x01(\tR\vpricingMode\x12\x1d\n" +
+	"\n" +
+	"unit_price\x18\x0f \x01(\x02R\tunitPrice\x12(\n" +
+	"\x10unit_price_rules\x18\x10 \x01(\tR\x0eunitPriceRules\x12%\n" +
+	"\x0ebatch_discount\x18\x11 \x01(\x02R\rbatchDiscount\x12*\n" +
+	"\x11audio_input_price\x18\x12 \x01(\x02R\x0faudioInputPrice\x12,\n" +
+	"\x12audio_output_price\x18\x13 \x01(\x02R\x10audioOutputPriceB6Z4github.com/modelgate/modelgate/pkg/proto/model/relayb\x06proto3"
 
 var (
 	file_model_relay_model_pricing_proto_rawDescOnce sync.Once
@@ -23,21 +23,25 @@ const (
 )
 
 type ProviderApiKey struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	ProviderId    int64                  `protobuf:"varint,2,opt,name=provider_id,json=providerId,proto3" json:"provider_id,omitempty"`
-	ProviderCode  string                 `protobuf:"bytes,3,opt,name=provider_code,json=providerCode,proto3" json:"provider_code,omitempty"`
-	Name          string                 `protobuf:"bytes,4,opt,name=name,proto3" json:"name,omitempty"`
-	Key           string                 `protobuf:"bytes,5,opt,name=key,proto3" json:"key,omitempty"`
-	Weight        int64                  `protobuf:"varint,6,opt,name=weight,proto3" json:"weight,omitempty"`
-	Status        string                 `protobuf:"bytes,7,opt,name=status,proto3" json:"status,omitempty"`
-	FaildCount    int64                  `protobuf:"varint,8,opt,name=faild_count,json=faildCount,proto3" json:"faild_count,omitempty"`
-	CoolDownUntil *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=cool_down_until,json=coolDownUntil,proto3" json:"cool_down_until,omitempty"`
-	LastUsedAt    *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=last_used_at,json=lastUsedAt,proto3" json:"last_used_at,omitempty"`
-	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	ProviderId     int64                  `protobuf:"varint,2,opt,name=provider_id,json=providerId,proto3" json:"provider_id,omitempty"`
+	ProviderCode   string                 `protobuf:"bytes,3,opt,name=provider_code,json=providerCode,proto3" json:"provider_code,omitempty"`
+	Name           string                 `protobuf:"bytes,4,opt,name=name,proto3" json:"name,omitempty"`
+	Key            string                 `protobuf:"bytes,5,opt,name=key,proto3" json:"key,omitempty"`
+	Weight         int64                  `protobuf:"varint,6,opt,name=weight,proto3" json:"weight,omitempty"`
+	Status         string                 `protobuf:"bytes,7,opt,name=status,proto3" json:"status,omitempty"`
+	FaildCount     int64                  `protobuf:"varint,8,opt,name=faild_count,json=faildCount,proto3" json:"faild_count,omitempty"`
+	CoolDownUntil  *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=cool_down_until,json=coolDownUntil,proto3" json:"cool_down_until,omitempty"`
+	LastUsedAt     *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=last_used_at,json=lastUsedAt,proto3" json:"last_used_at,omitempty"`
+	CreatedAt      *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt      *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	DisabledReason string                 `protobuf:"bytes,13,opt,name=disabled_reason,json=disabledReason,proto3" json:"disabled_reason,omitempty"`
+	QuoteUsed      int64                  `protobuf:"varint,14,opt,name=quote_used,json=quoteUsed,proto3" json:"quote_used,omitempty"`
+	QuoteLimit     int64                  `protobuf:"varint,15,opt,name=quote_limit,json=quoteLimit,proto3" json:"quote_limit,omitempty"`
+	RateLimit      int64                  `protobuf:"varint,16,opt,name=rate_limit,json=rateLimit,proto3" json:"rate_limit,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *ProviderApiKey) Reset() {
@@ -154,11 +158,39 @@ func (x *ProviderApiKey) GetUpdatedAt() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *ProviderApiKey) GetDisabledReason() string {
+	if x != nil {
+		return x.DisabledReason
+	}
+	return ""
+}
+
+func (x *ProviderApiKey) GetQuoteUsed() int64 {
+	if x != nil {
+		return x.QuoteUsed
+	}
+	return 0
+}
+
+func (x *ProviderApiKey) GetQuoteLimit() int64 {
+	if x != nil {
+		return x.QuoteLimit
+	}
+	return 0
+}
+
+func (x *ProviderApiKey) GetRateLimit() int64 {
+	if x != nil {
+		return x.RateLimit
+	}
+	return 0
+}
+
 var File_model_relay_provider_api_key_proto protoreflect.FileDescriptor
 
 const file_model_relay_provider_api_key_proto_rawDesc = "" +
 	"\n" +
-	"\"model/relay/provider_api_key.proto\x12\x05relay\x1a\x1fgoogle/protobuf/timestamp.proto\"\xd5\x03\n" +
+	"\"model/relay/provider_api_key.proto\x12\x05relay\x1a\x1fgoogle/protobuf/timestamp.proto\"\xdd\x04\n" +
 	"\x0eProviderApiKey\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1f\n" +
 	"\vprovider_id\x18\x02 \x01(\x03R\n" +
@@ -177,7 +209,14 @@ const file_model_relay_provider_api_key_proto_rawDesc = "" +
 	"\n" +
 	"created_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
 	"\n" +
-	"updated_at\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAtB6Z4github.com/modelgate/modelgate/pkg/proto/model/relayb\x06proto3"
+	"updated_at\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12'\n" +
+	"\x0fdisabled_reason\x18\r \x01(\tR\x0edisabledReason\x12\x1d\n" +
+	"\n" +
+	"quote_used\x18\x0e \x01(\x03R\tquoteUsed\x12\x1f\n" +
+	"\vquote_limit\x18\x0f \x01(\x03R\n" +
+	"quoteLimit\x12\x1d\n" +
+	"\n" +
+	"rate_limit\x18\x10 \x01(\x03R\trateLimitB6Z4github.com/modelgate/modelgate/pkg/proto/model/relayb\x06proto3"
 
 var (
 	file_model_relay_provider_api_key_proto_rawDescOnce sync.Once
@@ -0,0 +1,127 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"slices"
+	"strings"
+)
+
+// MultipartFormValues 读取 multipart/form-data 请求体中的普通字段，忽略文件
+func MultipartFormValues(contentType string, body []byte) (map[string]string, error) {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(mediaType, "multipart/") || params["boundary"] == "" {
+		return nil, errors.New("not a multipart request")
+	}
+	values := make(map[string]string)
+	reader := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return values, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if part.FileName() == "" {
+			data, err := io.ReadAll(part)
+			if err != nil {
+				return nil, err
+			}
+			values[part.FormName()] = string(data)
+		}
+		part.Close()
+	}
+}
+
+// MultipartFile 读取 multipart/form-data 请求体中指定字段的文件名和内容
+func MultipartFile(contentType string, body []byte, name string) (filename string, data []byte, err error) {
+	_, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return
+	}
+	reader := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+	for {
+		var part *multipart.Part
+		if part, err = reader.NextPart(); err == io.EOF {
+			return "", nil, errors.New("file " + name + " not found")
+		} else if err != nil {
+			return
+		}
+		if part.FormName() == name && part.FileName() != "" {
+			filename = part.FileName()
+			data, err = io.ReadAll(part)
+			part.Close()
+			return
+		}
+		part.Close()
+	}
+}
+
+// IsMultipart 是否为 multipart/form-data 请求
+func IsMultipart(contentType string) bool {
+	return strings.HasPrefix(strings.ToLower(contentType), "multipart/form-data")
+}
+
+// MultipartRemoveFields 去除 multipart 请求体中的指定字段，返回新的请求体和 Content-Type
+func MultipartRemoveFields(contentType string, body []byte, names ...string) ([]byte, string, error) {
+	return multipartRewrite(contentType, body, names, nil)
+}
+
+// MultipartSetField 设置 multipart 请求体中的普通字段，boundary 不变，Content-Type 不需要修改
+func MultipartSetField(contentType string, body []byte, name, value string) ([]byte, error) {
+	data, _, err := multipartRewrite(contentType, body, []string{name}, func(w *multipart.Writer) error {
+		return w.WriteField(name, value)
+	})
+	return data, err
+}
+
+// multipartRewrite 使用原 boundary 复制请求体，跳过指定字段，最后追加新字段
+func multipartRewrite(contentType string, body []byte, skip []string, extra func(w *multipart.Writer) error) ([]byte, string, error) {
+	_, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, "", err
+	}
+	buf := new(bytes.Buffer)
+	writer := multipart.NewWriter(buf)
+	if err = writer.SetBoundary(params["boundary"]); err != nil {
+		return nil, "", err
+	}
+	reader := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, "", err
+		}
+		if slices.Contains(skip, part.FormName()) {
+			part.Close()
+			continue
+		}
+		w, err := writer.CreatePart(part.Header)
+		if err != nil {
+			return nil, "", err
+		}
+		if _, err = io.Copy(w, part); err != nil {
+			return nil, "", err
+		}
+		part.Close()
+	}
+	if extra != nil {
+		if err = extra(writer); err != nil {
+			return nil, "", err
+		}
+	}
+	if err = writer.Close(); err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), writer.FormDataContentType(), nil
+}
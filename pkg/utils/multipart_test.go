@@ -0,0 +1,62 @@
+package utils
+
+import (
+	"bytes"
+	"mime/multipart"
+	"testing"
+)
+
+func TestMultipartRemoveFields(t *testing.T) {
+	buf := new(bytes.Buffer)
+	writer := multipart.NewWriter(buf)
+	_ = writer.WriteField("model", "gpt-image-1")
+	_ = writer.WriteField("provider", "openai")
+	fw, _ := writer.CreateFormFile("image", "a.png")
+	_, _ = fw.Write([]byte("png"))
+	_ = writer.Close()
+
+	body, contentType, err := MultipartRemoveFields(writer.FormDataContentType(), buf.Bytes(), "provider")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if contentType != writer.FormDataContentType() {
+		t.Fatalf("boundary changed: %s", contentType)
+	}
+	values, err := MultipartFormValues(contentType, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if values["model"] != "gpt-image-1" || values["provider"] != "" || len(values) != 1 {
+		t.Fatalf("unexpected values: %v", values)
+	}
+	if !bytes.Contains(body, []byte("png")) {
+		t.Fatal("file part missing")
+	}
+
+	if body, err = MultipartSetField(contentType, body, "model", "dall-e-3"); err != nil {
+		t.Fatal(err)
+	}
+	if values, _ = MultipartFormValues(contentType, body); values["model"] != "dall-e-3" || len(values) != 1 {
+		t.Fatalf("unexpected values: %v", values)
+	}
+}
+
+func TestMultipartFile(t *testing.T) {
+	buf := new(bytes.Buffer)
+	writer := multipart.NewWriter(buf)
+	_ = writer.WriteField("model", "whisper-1")
+	fw, _ := writer.CreateFormFile("file", "a.mp3")
+	_, _ = fw.Write([]byte("mp3"))
+	_ = writer.Close()
+
+	filename, data, err := MultipartFile(writer.FormDataContentType(), buf.Bytes(), "file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filename != "a.mp3" || string(data) != "mp3" {
+		t.Fatalf("unexpected file: %s %s", filename, data)
+	}
+	if _, _, err = MultipartFile(writer.FormDataContentType(), buf.Bytes(), "image"); err == nil {
+		t.Fatal("expected error for missing file")
+	}
+}
@@ -69,6 +69,25 @@ export const currencyRecord: Record<string, App.I18n.I18nKey> = {
 
 export const currencyOptions = transformRecordToOption(currencyRecord);
 
+export const pricingModeRecord: Record<string, App.I18n.I18nKey> = {
+  'token': 'page.relay.common.pricingMode.token',
+  'image': 'page.relay.common.pricingMode.image',
+  'second': 'page.relay.common.pricingMode.second',
+  'character': 'page.relay.common.pricingMode.character',
+  'search_unit': 'page.relay.common.pricingMode.search_unit'
+};
+
+export const pricingModeOptions = transformRecordToOption(pricingModeRecord);
+
+export const lbStrategyRecord: Record<string, App.I18n.I18nKey> = {
+  'weighted': 'page.relay.common.lbStrategy.weighted',
+  'ewma_latency': 'page.relay.common.lbStrategy.ewma_latency',
+  'least_outstanding': 'page.relay.common.lbStrategy.least_outstanding',
+  'error_penalized': 'page.relay.common.lbStrategy.error_penalized'
+};
+
+export const lbStrategyOptions = transformRecordToOption(lbStrategyRecord);
+
 export const genderRecord: Record<string, App.I18n.I18nKey> = {
   'male': 'page.manage.user.userGender.male',
   'female': 'page.manage.user.userGender.female',
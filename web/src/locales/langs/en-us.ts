@@ -558,6 +558,19 @@ const local: App.I18n.Schema = {
           cny: 'CNY',
           point: 'Point'
         },
+        pricingMode: {
+          token: 'Per Token',
+          image: 'Per Image',
+          second: 'Per Second',
+          character: 'Per Character',
+          search_unit: 'Per Search Unit'
+        },
+        lbStrategy: {
+          weighted: 'Weighted Random',
+          ewma_latency: 'Lowest Latency',
+          least_outstanding: 'Least Outstanding Requests',
+          error_penalized: 'Error Penalized'
+        },
         enableStatus: {
           enabled: 'Enabled',
           disabled: 'Disabled'
@@ -592,11 +605,19 @@ const local: App.I18n.Schema = {
         code: 'Code',
         baseUrl: 'Base URL',
         status: 'Status',
+        config: 'Config',
+        retryPolicy: 'Retry Policy',
+        transport: 'Transport',
+        pipeline: 'Hook Pipeline',
         form: {
           name: 'Name',
           code: 'Code',
           baseUrl: 'Base URL',
           status: 'Status',
+          config: 'Extra config in JSON, e.g. api_version for Azure',
+          retryPolicy: 'Retry policy in JSON with max_retries, base_delay_ms, max_delay_ms and hedge_delay_ms, empty for default',
+          transport: 'HTTP transport in JSON, e.g. proxy_url, ca_cert, insecure_skip_verify, timeout_ms, connect_timeout_ms, read_timeout_ms, idle_timeout_ms, max_conns, max_idle_conns, http2; client_key is stored encrypted and shown as ******, keep it to leave unchanged',
+          pipeline: 'Hook pipeline in JSON, e.g. {"hooks":[{"name":"billing","disabled":true}]}, built-in hooks can only be disabled (except request and stream_write), custom hooks run around "default", empty for default',
         }
       },
       providerApiKey: {
@@ -608,6 +629,12 @@ const local: App.I18n.Schema = {
         weight: 'Weight',
         status: 'Status',
         lastUsedAt: 'Last Used At',
+        faildCount: 'Failed Count',
+        coolDownUntil: 'Cooldown Until',
+        disabledReason: 'Disabled Reason',
+        quoteLimit: 'Quote Limit',
+        quoteUsed: 'Quote Used',
+        rateLimit: 'Rate Limit (QPS)',
         form: {
           providerId: 'Provider ID',
           providerCode: 'Provider Code',
@@ -615,6 +642,8 @@ const local: App.I18n.Schema = {
           key: 'Key',
           weight: 'Weight',
           status: 'Status',
+          quoteLimit: 'Quote limit, 0 for unlimited',
+          rateLimit: 'Requests per second, 0 for unlimited',
         }
       },
       model: {
@@ -627,6 +656,11 @@ const local: App.I18n.Schema = {
         priority: 'Priority',
         weight: 'Weight',
         status: 'Status',
+        contextWindow: 'Context Window',
+        maxOutputTokens: 'Max Output Tokens',
+        retryPolicy: 'Retry Policy',
+        lbStrategy: 'LB Strategy',
+        pipeline: 'Hook Pipeline',
         form: {
           providerId: 'Provider ID',
           code: 'Code',
@@ -636,6 +670,11 @@ const local: App.I18n.Schema = {
           priority: 'Priority',
           weight: 'Weight',
           status: 'Status',
+          contextWindow: 'Context window size in tokens, 0 means unknown',
+          maxOutputTokens: 'Max output tokens, 0 means unknown',
+          retryPolicy: 'Retry policy in JSON, unset fields use the provider policy',
+          lbStrategy: 'Load balancing strategy, shared by models with the same code',
+          pipeline: 'Hook pipeline in JSON, replaces the provider pipeline when set',
         }
       },
       modelPricing: {
@@ -648,9 +687,15 @@ const local: App.I18n.Schema = {
         inputPrice: 'Input Price',
         inputCachePrice: 'Input Cache Price',
         outputPrice: 'Output Price',
+        audioInputPrice: 'Audio Input Price',
+        audioOutputPrice: 'Audio Output Price',
         effectiveFrom: 'Effective From',
         effectiveTo: 'Effective To',
         status: 'Status',
+        pricingMode: 'Pricing Mode',
+        unitPrice: 'Unit Price',
+        unitPriceRules: 'Unit Price Rules',
+        batchDiscount: 'Batch Discount',
         form: {
           providerCode: 'Provider Code',
           modelCode: 'Model Code',
@@ -660,9 +705,14 @@ const local: App.I18n.Schema = {
           inputPrice: 'Input Price',
           inputCachePrice: 'Input Cache Price',
           outputPrice: 'Output Price',
+          audioInputPrice: 'Price of audio input tokens, 0 to use the input price',
+          audioOutputPrice: 'Price of audio output tokens, 0 to use the output price',
           effectiveFrom: 'Effective From',
           effectiveTo: 'Effective To',
           status: 'Status',
+          unitPrice: 'Default price per unit',
+          unitPriceRules: 'JSON array, each rule has size, quality and price',
+          batchDiscount: 'Multiplier for batch jobs, e.g. 0.5 for half price, 0 for no discount',
         }
       },
 
@@ -565,6 +565,19 @@ const local: App.I18n.Schema = {
           cny: '人民币',
           point: '点数'
         },
+        pricingMode: {
+          token: '按 Token',
+          image: '按张',
+          second: '按秒',
+          character: '按字符',
+          search_unit: '按搜索单元'
+        },
+        lbStrategy: {
+          weighted: '加权随机',
+          ewma_latency: '最低延迟',
+          least_outstanding: '最少进行中请求',
+          error_penalized: '错误率降权'
+        },
         provider: {
           status: {
             enabled: '启用',
@@ -602,11 +615,19 @@ const local: App.I18n.Schema = {
         code: '代码',
         baseUrl: '接口URL',
         status: '状态',
+        config: '扩展配置',
+        retryPolicy: '重试策略',
+        transport: '传输配置',
+        pipeline: 'Hook 流水线',
         form: {
           name: '名称',
           code: '代码',
           baseUrl: '接口URL',
           status: '状态',
+          config: 'JSON 格式扩展配置，如 Azure 的 api_version',
+          retryPolicy: 'JSON 格式重试策略，支持 max_retries、base_delay_ms、max_delay_ms、hedge_delay_ms，为空使用默认值',
+          transport: 'JSON 格式 HTTP 传输配置，如 proxy_url、ca_cert、insecure_skip_verify、timeout_ms、connect_timeout_ms、read_timeout_ms、idle_timeout_ms、max_conns、max_idle_conns、http2；client_key 加密保存，显示为 ******，保留掩码表示不修改',
+          pipeline: 'JSON 格式 hook 流水线，如 {"hooks":[{"name":"billing","disabled":true}]}，内置 hook 只能禁用（request、stream_write 除外），自定义 hook 加在 default 前后，为空使用默认值',
         }
       },
       providerApiKey: {
@@ -620,6 +641,12 @@ const local: App.I18n.Schema = {
         weight: '权重',
         status: '状态',
         lastUsedAt: '最后使用时间',
+        faildCount: '连续失败次数',
+        coolDownUntil: '冷却结束时间',
+        disabledReason: '禁用原因',
+        quoteLimit: '配额限制',
+        quoteUsed: '已用配额',
+        rateLimit: '速率限制(QPS)',
         form: {
           providerId: '厂商',
           providerCode: '厂商',
@@ -627,6 +654,8 @@ const local: App.I18n.Schema = {
           key: '密钥',
           weight: '权重',
           status: '状态',
+          quoteLimit: '配额限制，0 表示不限',
+          rateLimit: '每秒请求数，0 表示不限',
         }
       },
       model: {
@@ -641,6 +670,11 @@ const local: App.I18n.Schema = {
         priority: '优先级',
         weight: '权重',
         status: '状态',
+        contextWindow: '上下文窗口',
+        maxOutputTokens: '最大输出Token',
+        retryPolicy: '重试策略',
+        lbStrategy: '负载均衡策略',
+        pipeline: 'Hook 流水线',
         form: {
           providerId: '厂商',
           code: '代码',
@@ -650,6 +684,11 @@ const local: App.I18n.Schema = {
           priority: '优先级',
           weight: '权重',
           status: '状态',
+          contextWindow: '上下文窗口 Token 数，0 表示未知',
+          maxOutputTokens: '最大输出 Token 数，0 表示未知',
+          retryPolicy: 'JSON 格式重试策略，未设置的字段使用厂商的配置',
+          lbStrategy: '负载均衡策略，相同代码的模型共用',
+          pipeline: 'JSON 格式 hook 流水线，配置后替换厂商的流水线',
         }
       },
       modelPricing: {
@@ -664,9 +703,15 @@ const local: App.I18n.Schema = {
         inputPrice: '输入价格',
         inputCachePrice: '输入缓存价格',
         outputPrice: '输出价格',
+        audioInputPrice: '音频输入价格',
+        audioOutputPrice: '音频输出价格',
         effectiveFrom: '生效时间',
         effectiveTo: '失效时间',
         status: '状态',
+        pricingMode: '计费方式',
+        unitPrice: '单价',
+        unitPriceRules: '单价规则',
+        batchDiscount: '批量折扣',
         form: {
           providerCode: '厂商',
           modelCode: '模型代码',
@@ -676,9 +721,14 @@ const local: App.I18n.Schema = {
           inputPrice: '输入价格',
           inputCachePrice: '输入缓存价格',
           outputPrice: '输出价格',
+          audioInputPrice: '音频输入 token 价格，0 表示与输入价格相同',
+          audioOutputPrice: '音频输出 token 价格，0 表示与输出价格相同',
           effectiveFrom: '生效时间',
           effectiveTo: '失效时间',
           status: '状态',
+          unitPrice: '默认单价',
+          unitPriceRules: 'JSON 数组，每条规则包含 size、quality、price',
+          batchDiscount: '批量任务价格系数，如 0.5 表示半价，0 表示不打折',
         }
       },
     },
@@ -729,6 +729,19 @@ declare namespace App {
               cny: string;
               point: string;
             },
+            pricingMode: {
+              token: string;
+              image: string;
+              second: string;
+              character: string;
+              search_unit: string;
+            },
+            lbStrategy: {
+              weighted: string;
+              ewma_latency: string;
+              least_outstanding: string;
+              error_penalized: string;
+            },
             enableStatus: {
               enabled: string;
               disabled: string;
@@ -763,11 +776,19 @@ declare namespace App {
             code: string;
             baseUrl: string;
             status: string;
+            config: string;
+            retryPolicy: string;
+            transport: string;
+            pipeline: string;
             form: {
               name: string;
               code: string;
               baseUrl: string;
               status: string;
+              config: string;
+              retryPolicy: string;
+              transport: string;
+              pipeline: string;
             }
           };
           providerApiKey: {
@@ -781,6 +802,12 @@ declare namespace App {
             weight: string;
             status: string;
             lastUsedAt: string;
+            faildCount: string;
+            coolDownUntil: string;
+            disabledReason: string;
+            quoteLimit: string;
+            quoteUsed: string;
+            rateLimit: string;
             form: {
               providerId: string;
               providerCode: string;
@@ -788,6 +815,8 @@ declare namespace App {
               key: string;
               weight: string;
               status: string;
+              quoteLimit: string;
+              rateLimit: string;
             }
           };
           model: {
@@ -802,6 +831,11 @@ declare namespace App {
             priority: string;
             weight: string;
             status: string;
+            contextWindow: string;
+            maxOutputTokens: string;
+            retryPolicy: string;
+            lbStrategy: string;
+            pipeline: string;
             form: {
               providerId: string;
               code: string;
@@ -811,6 +845,11 @@ declare namespace App {
               priority: string;
               weight: string;
               status: string;
+              contextWindow: string;
+              maxOutputTokens: string;
+              retryPolicy: string;
+              lbStrategy: string;
+              pipeline: string;
             }
           };
           modelPricing: {
@@ -825,9 +864,15 @@ declare namespace App {
             inputPrice: string;
             inputCachePrice: string;
             outputPrice: string;
+            audioInputPrice: string;
+            audioOutputPrice: string;
             effectiveFrom: string;
             effectiveTo: string;
             status: string;
+            pricingMode: string;
+            unitPrice: string;
+            unitPriceRules: string;
+            batchDiscount: string;
             form: {
               providerCode: string;
               modelCode: string;
@@ -837,9 +882,14 @@ declare namespace App {
               inputPrice: string;
               inputCachePrice: string;
               outputPrice: string;
+              audioInputPrice: string;
+              audioOutputPrice: string;
               effectiveFrom: string;
               effectiveTo: string;
               status: string;
+              unitPrice: string;
+              unitPriceRules: string;
+              batchDiscount: string;
             }
           };
         };
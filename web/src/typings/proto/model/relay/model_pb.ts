@@ -12,7 +12,7 @@ import type { Message } from "@bufbuild/protobuf";
  * Describes the file model/relay/model.proto.
  */
 export const file_model_relay_model: GenFile = /*@__PURE__*/
-  fileDesc("Chdtb2RlbC9yZWxheS9tb2RlbC5wcm90bxIFcmVsYXkiggIKBU1vZGVsEgoKAmlkGAEgASgDEhMKC3Byb3ZpZGVyX2lkGAIgASgDEhUKDXByb3ZpZGVyX2NvZGUYAyABKAkSEwoLYWN0dWFsX2NvZGUYBCABKAkSDAoEY29kZRgFIAEoCRIMCgRuYW1lGAYgASgJEhAKCHByaW9yaXR5GAcgASgDEg4KBndlaWdodBgIIAEoAxIOCgZzdGF0dXMYCSABKAkSLgoKY3JlYXRlZF9hdBgKIAEoCzIaLmdvb2dsZS5wcm90b2J1Zi5UaW1lc3RhbXASLgoKdXBkYXRlZF9hdBgLIAEoCzIaLmdvb2dsZS5wcm90b2J1Zi5UaW1lc3RhbXAqfQoLTW9kZWxTdGF0dXMSHAoYTU9ERUxfU1RBVFVTX1VOU1BFQ0lGSUVEEAASGAoUTU9ERUxfU1RBVFVTX0VOQUJMRUQQARIZChVNT0RFTF9TVEFUVVNfRElTQUJMRUQQAhIbChdNT0RFTF9TVEFUVVNfREVQUkVDQVRFRBADQjZaNGdpdGh1Yi5jb20vbW9kZWxnYXRlL21vZGVsZ2F0ZS9wa2cvcHJvdG8vbW9kZWwvcmVsYXliBnByb3RvMw", [file_google_protobuf_timestamp]);
+  fileDesc("Chdtb2RlbC9yZWxheS9tb2RlbC5wcm90bxIFcmVsYXki8gIKBU1vZGVsEgoKAmlkGAEgASgDEhMKC3Byb3ZpZGVyX2lkGAIgASgDEhUKDXByb3ZpZGVyX2NvZGUYAyABKAkSEwoLYWN0dWFsX2NvZGUYBCABKAkSDAoEY29kZRgFIAEoCRIMCgRuYW1lGAYgASgJEhAKCHByaW9yaXR5GAcgASgDEg4KBndlaWdodBgIIAEoAxIOCgZzdGF0dXMYCSABKAkSLgoKY3JlYXRlZF9hdBgKIAEoCzIaLmdvb2dsZS5wcm90b2J1Zi5UaW1lc3RhbXASLgoKdXBkYXRlZF9hdBgLIAEoCzIaLmdvb2dsZS5wcm90b2J1Zi5UaW1lc3RhbXASFgoOY29udGV4dF93aW5kb3cYDCABKAMSGQoRbWF4X291dHB1dF90b2tlbnMYDSABKAMSFAoMcmV0cnlfcG9saWN5GA4gASgJEhMKC2xiX3N0cmF0ZWd5GA8gASgJEhAKCHBpcGVsaW5lGBAgASgJKn0KC01vZGVsU3RhdHVzEhwKGE1PREVMX1NUQVRVU19VTlNQRUNJRklFRBAAEhgKFE1PREVMX1NUQVRVU19FTkFCTEVEEAESGQoVTU9ERUxfU1RBVFVTX0RJU0FCTEVEEAISGwoXTU9ERUxfU1RBVFVTX0RFUFJFQ0FURUQQA0I2WjRnaXRodWIuY29tL21vZGVsZ2F0ZS9tb2RlbGdhdGUvcGtnL3Byb3RvL21vZGVsL3JlbGF5YgZwcm90bzM", [file_google_protobuf_timestamp]);
 
 /**
  * @generated from message relay.Model
@@ -72,6 +72,30 @@ export type Model = Message<"relay.Model"> & {
    * @generated from field: google.protobuf.Timestamp updated_at = 11;
    */
   updatedAt?: Timestamp;
+
+  /**
+   * @generated from field: int64 context_window = 12;
+   */
+  contextWindow: bigint;
+
+  /**
+   * @generated from field: int64 max_output_tokens = 13;
+   */
+  maxOutputTokens: bigint;
+
+  /**
+   * @generated from field: string retry_policy = 14;
+   */
+  retryPolicy: string;
+
+  /**
+   * @generated from field: string lb_strategy = 15;
+   */
+  lbStrategy: string;
+  /**
+   * @generated from field: string pipeline = 16;
+   */
+  pipeline: string;
 };
 
 /**
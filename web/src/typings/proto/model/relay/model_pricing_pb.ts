@@ -12,7 +12,7 @@ import type { Message } from "@bufbuild/protobuf";
  * Describes the file model/relay/model_pricing.proto.
  */
 export const file_model_relay_model_pricing: GenFile = /*@__PURE__*/
-  fileDesc("Ch9tb2RlbC9yZWxheS9tb2RlbF9wcmljaW5nLnByb3RvEgVyZWxheSLzAgoMTW9kZWxQcmljaW5nEgoKAmlkGAEgASgDEhUKDXByb3ZpZGVyX2NvZGUYAiABKAkSEgoKbW9kZWxfY29kZRgDIAEoCRIQCghjdXJyZW5jeRgEIAEoCRIbChNwb2ludHNfcGVyX2N1cnJlbmN5GAUgASgDEhEKCXRva2VuX251bRgGIAEoAxITCgtpbnB1dF9wcmljZRgHIAEoAhIZChFpbnB1dF9jYWNoZV9wcmljZRgIIAEoAhIUCgxvdXRwdXRfcHJpY2UYCSABKAISDgoGc3RhdHVzGAogASgJEjIKDmVmZmVjdGl2ZV9mcm9tGAsgASgLMhouZ29vZ2xlLnByb3RvYnVmLlRpbWVzdGFtcBIwCgxlZmZlY3RpdmVfdG8YDCABKAsyGi5nb29nbGUucHJvdG9idWYuVGltZXN0YW1wEi4KCmNyZWF0ZWRfYXQYDSABKAsyGi5nb29nbGUucHJvdG9idWYuVGltZXN0YW1wQjZaNGdpdGh1Yi5jb20vbW9kZWxnYXRlL21vZGVsZ2F0ZS9wa2cvcHJvdG8vbW9kZWwvcmVsYXliBnByb3RvMw", [file_google_protobuf_timestamp]);
+  fileDesc("Ch9tb2RlbC9yZWxheS9tb2RlbF9wcmljaW5nLnByb3RvEgVyZWxheSKGBAoMTW9kZWxQcmljaW5nEgoKAmlkGAEgASgDEhUKDXByb3ZpZGVyX2NvZGUYAiABKAkSEgoKbW9kZWxfY29kZRgDIAEoCRIQCghjdXJyZW5jeRgEIAEoCRIbChNwb2ludHNfcGVyX2N1cnJlbmN5GAUgASgDEhEKCXRva2VuX251bRgGIAEoAxITCgtpbnB1dF9wcmljZRgHIAEoAhIZChFpbnB1dF9jYWNoZV9wcmljZRgIIAEoAhIUCgxvdXRwdXRfcHJpY2UYCSABKAISDgoGc3RhdHVzGAogASgJEjIKDmVmZmVjdGl2ZV9mcm9tGAsgASgLMhouZ29vZ2xlLnByb3RvYnVmLlRpbWVzdGFtcBIwCgxlZmZlY3RpdmVfdG8YDCABKAsyGi5nb29nbGUucHJvdG9idWYuVGltZXN0YW1wEi4KCmNyZWF0ZWRfYXQYDSABKAsyGi5nb29nbGUucHJvdG9idWYuVGltZXN0YW1wEhQKDHByaWNpbmdfbW9kZRgOIAEoCRISCgp1bml0X3ByaWNlGA8gASgCEhgKEHVuaXRfcHJpY2VfcnVsZXMYECABKAkSFgoOYmF0Y2hfZGlzY291bnQYESABKAISGQoRYXVkaW9faW5wdXRfcHJpY2UYEiABKAISGgoSYXVkaW9fb3V0cHV0X3ByaWNlGBMgASgCQjZaNGdpdGh1Yi5jb20vbW9kZWxnYXRlL21vZGVsZ2F0ZS9wa2cvcHJvdG8vbW9kZWwvcmVsYXliBnByb3RvMw", [file_google_protobuf_timestamp]);
 
 /**
  * @generated from message relay.ModelPricing
@@ -82,6 +82,36 @@ export type ModelPricing = Message<"relay.ModelPricing"> & {
    * @generated from field: google.protobuf.Timestamp created_at = 13;
    */
   createdAt?: Timestamp;
+
+  /**
+   * @generated from field: string pricing_mode = 14;
+   */
+  pricingMode: string;
+
+  /**
+   * @generated from field: float unit_price = 15;
+   */
+  unitPrice: number;
+
+  /**
+   * @generated from field: string unit_price_rules = 16;
+   */
+  unitPriceRules: string;
+
+  /**
+   * @generated from field: float batch_discount = 17;
+   */
+  batchDiscount: number;
+
+  /**
+   * @generated from field: float audio_input_price = 18;
+   */
+  audioInputPrice: number;
+
+  /**
+   * @generated from field: float audio_output_price = 19;
+   */
+  audioOutputPrice: number;
 };
 
 /**
@@ -12,7 +12,7 @@ import type { Message } from "@bufbuild/protobuf";
  * Describes the file model/relay/provider_api_key.proto.
  */
 export const file_model_relay_provider_api_key: GenFile = /*@__PURE__*/
-  fileDesc("CiJtb2RlbC9yZWxheS9wcm92aWRlcl9hcGlfa2V5LnByb3RvEgVyZWxheSLfAgoOUHJvdmlkZXJBcGlLZXkSCgoCaWQYASABKAMSEwoLcHJvdmlkZXJfaWQYAiABKAMSFQoNcHJvdmlkZXJfY29kZRgDIAEoCRIMCgRuYW1lGAQgASgJEgsKA2tleRgFIAEoCRIOCgZ3ZWlnaHQYBiABKAMSDgoGc3RhdHVzGAcgASgJEhMKC2ZhaWxkX2NvdW50GAggASgDEjMKD2Nvb2xfZG93bl91bnRpbBgJIAEoCzIaLmdvb2dsZS5wcm90b2J1Zi5UaW1lc3RhbXASMAoMbGFzdF91c2VkX2F0GAogASgLMhouZ29vZ2xlLnByb3RvYnVmLlRpbWVzdGFtcBIuCgpjcmVhdGVkX2F0GAsgASgLMhouZ29vZ2xlLnByb3RvYnVmLlRpbWVzdGFtcBIuCgp1cGRhdGVkX2F0GAwgASgLMhouZ29vZ2xlLnByb3RvYnVmLlRpbWVzdGFtcEI2WjRnaXRodWIuY29tL21vZGVsZ2F0ZS9tb2RlbGdhdGUvcGtnL3Byb3RvL21vZGVsL3JlbGF5YgZwcm90bzM", [file_google_protobuf_timestamp]);
+  fileDesc("CiJtb2RlbC9yZWxheS9wcm92aWRlcl9hcGlfa2V5LnByb3RvEgVyZWxheSK1AwoOUHJvdmlkZXJBcGlLZXkSCgoCaWQYASABKAMSEwoLcHJvdmlkZXJfaWQYAiABKAMSFQoNcHJvdmlkZXJfY29kZRgDIAEoCRIMCgRuYW1lGAQgASgJEgsKA2tleRgFIAEoCRIOCgZ3ZWlnaHQYBiABKAMSDgoGc3RhdHVzGAcgASgJEhMKC2ZhaWxkX2NvdW50GAggASgDEjMKD2Nvb2xfZG93bl91bnRpbBgJIAEoCzIaLmdvb2dsZS5wcm90b2J1Zi5UaW1lc3RhbXASMAoMbGFzdF91c2VkX2F0GAogASgLMhouZ29vZ2xlLnByb3RvYnVmLlRpbWVzdGFtcBIuCgpjcmVhdGVkX2F0GAsgASgLMhouZ29vZ2xlLnByb3RvYnVmLlRpbWVzdGFtcBIuCgp1cGRhdGVkX2F0GAwgASgLMhouZ29vZ2xlLnByb3RvYnVmLlRpbWVzdGFtcBIXCg9kaXNhYmxlZF9yZWFzb24YDSABKAkSEgoKcXVvdGVfdXNlZBgOIAEoAxITCgtxdW90ZV9saW1pdBgPIAEoAxISCgpyYXRlX2xpbWl0GBAgASgDQjZaNGdpdGh1Yi5jb20vbW9kZWxnYXRlL21vZGVsZ2F0ZS9wa2cvcHJvdG8vbW9kZWwvcmVsYXliBnByb3RvMw", [file_google_protobuf_timestamp]);
 
 /**
  * @generated from message relay.ProviderApiKey
@@ -77,6 +77,26 @@ export type ProviderApiKey = Message<"relay.ProviderApiKey"> & {
    * @generated from field: google.protobuf.Timestamp updated_at = 12;
    */
   updatedAt?: Timestamp;
+
+  /**
+   * @generated from field: string disabled_reason = 13;
+   */
+  disabledReason: string;
+
+  /**
+   * @generated from field: int64 quote_used = 14;
+   */
+  quoteUsed: bigint;
+
+  /**
+   * @generated from field: int64 quote_limit = 15;
+   */
+  quoteLimit: bigint;
+
+  /**
+   * @generated from field: int64 rate_limit = 16;
+   */
+  rateLimit: bigint;
 };
 
 /**
@@ -12,7 +12,7 @@ import type { Message } from "@bufbuild/protobuf";
  * Describes the file model/relay/provider.proto.
  */
 export const file_model_relay_provider: GenFile = /*@__PURE__*/
-  fileDesc("Chptb2RlbC9yZWxheS9wcm92aWRlci5wcm90bxIFcmVsYXkitAEKCFByb3ZpZGVyEgoKAmlkGAEgASgDEgwKBGNvZGUYAiABKAkSDAoEbmFtZRgDIAEoCRIQCghiYXNlX3VybBgEIAEoCRIOCgZzdGF0dXMYBSABKAkSLgoKY3JlYXRlZF9hdBgGIAEoCzIaLmdvb2dsZS5wcm90b2J1Zi5UaW1lc3RhbXASLgoKdXBkYXRlZF9hdBgHIAEoCzIaLmdvb2dsZS5wcm90b2J1Zi5UaW1lc3RhbXBCNlo0Z2l0aHViLmNvbS9tb2RlbGdhdGUvbW9kZWxnYXRlL3BrZy9wcm90by9tb2RlbC9yZWxheWIGcHJvdG8z", [file_google_protobuf_timestamp]);
+  fileDesc("Chptb2RlbC9yZWxheS9wcm92aWRlci5wcm90bxIFcmVsYXki/wEKCFByb3ZpZGVyEgoKAmlkGAEgASgDEgwKBGNvZGUYAiABKAkSDAoEbmFtZRgDIAEoCRIQCghiYXNlX3VybBgEIAEoCRIOCgZzdGF0dXMYBSABKAkSLgoKY3JlYXRlZF9hdBgGIAEoCzIaLmdvb2dsZS5wcm90b2J1Zi5UaW1lc3RhbXASLgoKdXBkYXRlZF9hdBgHIAEoCzIaLmdvb2dsZS5wcm90b2J1Zi5UaW1lc3RhbXASDgoGY29uZmlnGAggASgJEhQKDHJldHJ5X3BvbGljeRgJIAEoCRIRCgl0cmFuc3BvcnQYCiABKAkSEAoIcGlwZWxpbmUYCyABKAlCNlo0Z2l0aHViLmNvbS9tb2RlbGdhdGUvbW9kZWxnYXRlL3BrZy9wcm90by9tb2RlbC9yZWxheWIGcHJvdG8z", [file_google_protobuf_timestamp]);
 
 /**
  * @generated from message relay.Provider
@@ -52,6 +52,25 @@ export type Provider = Message<"relay.Provider"> & {
    * @generated from field: google.protobuf.Timestamp updated_at = 7;
    */
   updatedAt?: Timestamp;
+
+  /**
+   * @generated from field: string config = 8;
+   */
+  config: string;
+
+  /**
+   * @generated from field: string retry_policy = 9;
+   */
+  retryPolicy: string;
+
+  /**
+   * @generated from field: string transport = 10;
+   */
+  transport: string;
+  /**
+   * @generated from field: string pipeline = 11;
+   */
+  pipeline: string;
 };
 
 /**